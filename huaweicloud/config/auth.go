@@ -18,6 +18,7 @@ import (
 	"github.com/chnsz/golangsdk/auth"
 	huaweisdk "github.com/chnsz/golangsdk/openstack"
 
+	iamv3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3"
 	iam_model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3/model"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/pathorcontents"
@@ -47,6 +48,8 @@ type Profile struct {
 	AgencyDomainId   string `json:"agencyDomainId"`
 	AgencyDomainName string `json:"agencyDomainName"`
 	AgencyName       string `json:"agencyName"`
+	// CredentialProcess is the command which prints the temporary credentials in JSON format
+	CredentialProcess string `json:"credentialProcess"`
 }
 
 func buildClient(c *Config) error {
@@ -123,11 +126,27 @@ func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.Provider
 		Proxy:           http.ProxyFromEnvironment,
 		TLSClientConfig: config,
 	}
+	// the cassette is the innermost transport, so that the retries and rate limits still work in replay mode
+	cassetteTransport, err := withCassette(transport)
+	if err != nil {
+		return nil, err
+	}
+	// the API calls are traced by LogRoundTripper, so that the retries are counted in one record
+	if _, err := GetAPITracer(); err != nil {
+		return nil, err
+	}
 
 	client.HTTPClient = http.Client{
+		// the throttled responses are only retried by the LogRoundTripper, the RetryBackoffFunc of golangsdk is not
+		// set as it would multiply the retries and wait up to 30 minutes between them
 		Transport: &LogRoundTripper{
-			Rt:         transport,
-			MaxRetries: c.MaxRetries,
+			Rt: &rateLimitRoundTripper{
+				Rt:     cassetteTransport,
+				Config: c,
+			},
+			MaxRetries:          c.MaxRetries,
+			MaxBackoff:          time.Duration(c.MaxRetryBackoff) * time.Second,
+			RetryableErrorCodes: c.RetryableErrorCodes,
 		},
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if client.AKSKAuthOptions.AccessKey != "" {
@@ -140,11 +159,6 @@ func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.Provider
 		},
 	}
 
-	if c.MaxRetries > 0 {
-		client.MaxBackoffRetries = uint(c.MaxRetries)
-		client.RetryBackoffFunc = retryBackoffFunc
-	}
-
 	// Validate authentication normally.
 	err = huaweisdk.Authenticate(client, ao)
 	if err != nil {
@@ -289,27 +303,71 @@ func buildClientByConfig(c *Config) error {
 		return fmt.Errorf("Error finding profile %s from shared config file", current)
 	}
 
-	c.AccessKey = providerConfig.AccessKeyId
-	c.SecretKey = providerConfig.SecretAccessKey
+	if err := applySharedProfile(c, &providerConfig); err != nil {
+		return err
+	}
+	return buildClientByAKSK(c)
+}
+
+// applySharedProfile sets the credentials of the profile according to the mode used by KooCLI, and the credentials
+// returned by the credential process take precedence over the mode.
+func applySharedProfile(c *Config, profile *Profile) error {
+	mode := profile.Mode
+	if mode == "" {
+		mode = "AKSK"
+	}
+
+	switch {
+	case profile.CredentialProcess != "":
+		c.CredentialProcess = profile.CredentialProcess
+		if err := getAuthConfigByProcess(c); err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] Successfully got the credentials from the credential process, which will expire at: %s",
+			c.SecurityKeyExpiresAt)
+	case mode == "AKSK":
+		c.AccessKey = profile.AccessKeyId
+		c.SecretKey = profile.SecretAccessKey
+		c.SecurityToken = profile.SecurityToken
+	case mode == "STS":
+		if profile.SecurityToken == "" {
+			return fmt.Errorf("the securityToken of profile %s is required in STS mode", profile.Name)
+		}
+		c.AccessKey = profile.AccessKeyId
+		c.SecretKey = profile.SecretAccessKey
+		c.SecurityToken = profile.SecurityToken
+	case mode == "ecsAgency":
+		if err := getAuthConfigByMeta(c); err != nil {
+			return fmt.Errorf("Error fetching Auth credentials from ECS Metadata API for profile %s: %s", profile.Name, err)
+		}
+	default:
+		return fmt.Errorf("the mode %s of profile %s is not supported, the valid values are AKSK, STS and ecsAgency",
+			mode, profile.Name)
+	}
+
+	if c.AccessKey == "" || c.SecretKey == "" {
+		return fmt.Errorf("the accessKeyId and secretAccessKey of profile %s are required in %s mode",
+			profile.Name, mode)
+	}
+
 	// non required fields
-	if providerConfig.Region != "" {
-		c.Region = providerConfig.Region
+	if profile.Region != "" {
+		c.Region = profile.Region
 	}
-	if providerConfig.DomainId != "" {
-		c.DomainID = providerConfig.DomainId
+	if profile.DomainId != "" {
+		c.DomainID = profile.DomainId
 	}
-	if providerConfig.ProjectId != "" {
-		c.TenantID = providerConfig.ProjectId
+	if profile.ProjectId != "" {
+		c.TenantID = profile.ProjectId
 	}
 	// assume role
-	if providerConfig.AgencyName != "" {
-		c.AssumeRoleAgency = providerConfig.AgencyName
+	if profile.AgencyName != "" {
+		c.AssumeRoleAgency = profile.AgencyName
 	}
-	if providerConfig.AgencyDomainName != "" {
-		c.AssumeRoleDomain = providerConfig.AgencyDomainName
+	if profile.AgencyDomainName != "" {
+		c.AssumeRoleDomain = profile.AgencyDomainName
 	}
-
-	return buildClientByAKSK(c)
+	return nil
 }
 
 func buildClientByPassword(c *Config) error {
@@ -353,20 +411,96 @@ func buildClientByPassword(c *Config) error {
 	return genClients(c, projectAuthOptions, domainAuthOptions)
 }
 
+// credentialSource is the identity which is used to assume role.
+type credentialSource struct {
+	accessKey     string
+	secretKey     string
+	securityToken string
+	domainID      string
+}
+
 func buildClientByAgency(c *Config) error {
-	client, err := c.HcIamV3Client(c.Region)
+	// keep the source credentials to re-assume the role
+	if c.assumeRoleSource == nil {
+		c.assumeRoleSource = &credentialSource{
+			accessKey:     c.AccessKey,
+			secretKey:     c.SecretKey,
+			securityToken: c.SecurityToken,
+			domainID:      c.DomainID,
+		}
+	}
+
+	if err := getAuthConfigByAgency(c); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Successfully assumed role, the temporary credentials will expire at: %s", c.AssumeRoleExpiresAt)
+	return buildClientByAKSK(c)
+}
+
+// getAuthConfigByAgency creates the temporary credentials by the source credentials and updates them to the config.
+// The roles in the chain are assumed in order, and the temporary credentials of each role are used to assume the next.
+func getAuthConfigByAgency(c *Config) error {
+	identity := *c
+	identity.AccessKey = c.assumeRoleSource.accessKey
+	identity.SecretKey = c.assumeRoleSource.secretKey
+	identity.SecurityToken = c.assumeRoleSource.securityToken
+	identity.DomainID = c.assumeRoleSource.domainID
+
+	roles := c.AssumeRoleChain
+	if len(roles) == 0 {
+		roles = []AssumeRole{{AgencyName: c.AssumeRoleAgency, DomainName: c.AssumeRoleDomain}}
+	}
+
+	var credential *iam_model.Credential
+	for i, role := range roles {
+		var err error
+		credential, err = createTemporaryAccessKeyByAgency(&identity, role)
+		if err != nil {
+			return fmt.Errorf("Error assuming role %s/%s (%d of %d): %s", role.DomainName, role.AgencyName,
+				i+1, len(roles), err)
+		}
+
+		// the temporary credentials become the identity to assume the next role,
+		// and the domain ID will be queried automatically as the role belongs to another domain
+		identity.AccessKey, identity.SecretKey, identity.SecurityToken = credential.Access, credential.Secret,
+			credential.Securitytoken
+		identity.DomainID = ""
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, credential.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("Error parsing the expiration time of temporary accesskey: %s", err)
+	}
+	c.AccessKey, c.SecretKey, c.SecurityToken = credential.Access, credential.Secret, credential.Securitytoken
+	c.AssumeRoleExpiresAt = expiresAt
+
+	return nil
+}
+
+func createTemporaryAccessKeyByAgency(identity *Config, role AssumeRole) (*iam_model.Credential, error) {
+	hcClient, err := implNewHcClient(identity, identity.Region, "iam", true, false)
 	if err != nil {
-		return fmt.Errorf("Error creating Huaweicloud IAM client: %s", err)
+		return nil, fmt.Errorf("Error creating Huaweicloud IAM client: %s", err)
 	}
+	client := iamv3.NewIamClient(hcClient)
 
 	request := &iam_model.CreateTemporaryAccessKeyByAgencyRequest{}
-	domainNameAssumeRoleIdentityAssumerole := c.AssumeRoleDomain
+	domainNameAssumeRoleIdentityAssumerole := role.DomainName
 	durationSecondsAssumeRoleIdentityAssumerole := assumeRoleDuration
+	if role.Duration > 0 {
+		durationSecondsAssumeRoleIdentityAssumerole = int32(role.Duration)
+	}
 	assumeRoleIdentity := &iam_model.IdentityAssumerole{
-		AgencyName:      c.AssumeRoleAgency,
+		AgencyName:      role.AgencyName,
 		DomainName:      &domainNameAssumeRoleIdentityAssumerole,
 		DurationSeconds: &durationSecondsAssumeRoleIdentityAssumerole,
 	}
+	if role.SessionName != "" {
+		sessionName := role.SessionName
+		assumeRoleIdentity.SessionUser = &iam_model.AssumeroleSessionuser{
+			Name: &sessionName,
+		}
+	}
 	var listMethodsIdentity = []iam_model.AgencyAuthIdentityMethods{
 		iam_model.GetAgencyAuthIdentityMethodsEnum().ASSUME_ROLE,
 	}
@@ -374,6 +508,13 @@ func buildClientByAgency(c *Config) error {
 		Methods:    listMethodsIdentity,
 		AssumeRole: assumeRoleIdentity,
 	}
+	if role.Policy != "" {
+		var policy iam_model.ServicePolicy
+		if err := json.Unmarshal([]byte(role.Policy), &policy); err != nil {
+			return nil, fmt.Errorf("Error parsing the policy of assume role: %s", err)
+		}
+		identityAuth.Policy = &policy
+	}
 	authbody := &iam_model.AgencyAuth{
 		Identity: identityAuth,
 	}
@@ -382,19 +523,86 @@ func buildClientByAgency(c *Config) error {
 	}
 	response, err := client.CreateTemporaryAccessKeyByAgency(request)
 	if err != nil {
-		return fmt.Errorf("Error Creating temporary accesskey by agency: %s", err)
+		return nil, fmt.Errorf("Error Creating temporary accesskey by agency: %s", err)
+	}
+	if response.Credential == nil {
+		return nil, fmt.Errorf("Error Creating temporary accesskey by agency: the credential is empty")
+	}
+	return response.Credential, nil
+}
+
+// reloadExpiredCredentials reloads the temporary credentials which are about to expire, including the security key
+// fetched from ECS metadata API or the credential process and the temporary credentials created by assume role.
+func (c *Config) reloadExpiredCredentials() error {
+	if parent := c.scopeParent; parent != nil {
+		return c.reloadScopeParentCredentials(parent)
+	}
+	if c.SecurityKeyExpiresAt.IsZero() && c.AssumeRoleExpiresAt.IsZero() {
+		return nil
 	}
-	c.AccessKey, c.SecretKey, c.SecurityToken = response.Credential.Access, response.Credential.Secret, response.Credential.Securitytoken
 
-	return buildClientByAKSK(c)
+	c.SecurityKeyLock.Lock()
+	defer c.SecurityKeyLock.Unlock()
+	timeNow := time.Now().Unix()
+	if !c.SecurityKeyExpiresAt.IsZero() && timeNow+keyExpiresDuration > c.SecurityKeyExpiresAt.Unix() {
+		return c.reloadSecurityKey()
+	}
+	if !c.AssumeRoleExpiresAt.IsZero() && timeNow+keyExpiresDuration > c.AssumeRoleExpiresAt.Unix() {
+		return c.reloadAssumeRole()
+	}
+	return nil
+}
+
+// reloadScopeParentCredentials reloads the credentials by the configuration which the project-scoped copy is derived
+// from, and copies the credentials and the clients built with them, so that they are reloaded only once.
+func (c *Config) reloadScopeParentCredentials(parent *Config) error {
+	if err := parent.reloadExpiredCredentials(); err != nil {
+		return err
+	}
+	if parent.SecurityKeyExpiresAt.IsZero() && parent.AssumeRoleExpiresAt.IsZero() {
+		return nil
+	}
+
+	parent.SecurityKeyLock.Lock()
+	defer parent.SecurityKeyLock.Unlock()
+	c.AccessKey, c.SecretKey, c.SecurityToken = parent.AccessKey, parent.SecretKey, parent.SecurityToken
+	c.SecurityKeyExpiresAt, c.AssumeRoleExpiresAt = parent.SecurityKeyExpiresAt, parent.AssumeRoleExpiresAt
+	c.HwClient, c.DomainClient = parent.HwClient, parent.DomainClient
+	return nil
 }
 
+// reloadSecurityKey reloads the temporary security key from the credential process if it's configured, otherwise
+// from ECS metadata API.
 func (c *Config) reloadSecurityKey() error {
-	err := getAuthConfigByMeta(c)
+	if c.CredentialProcess != "" {
+		if err := getAuthConfigByProcess(c); err != nil {
+			return fmt.Errorf("Error reloading Auth credentials from the credential process: %s", err)
+		}
+		log.Printf("Successfully reload the security key from the credential process, which will expire at: %s",
+			c.SecurityKeyExpiresAt)
+	} else {
+		if err := getAuthConfigByMeta(c); err != nil {
+			return fmt.Errorf("Error reloading Auth credentials from ECS Metadata API: %s", err)
+		}
+		log.Printf("Successfully reload metadata security key, which will expire at: %s", c.SecurityKeyExpiresAt)
+	}
+
+	// the security key is the source credentials of assume role
+	if c.assumeRoleSource != nil {
+		c.assumeRoleSource.accessKey = c.AccessKey
+		c.assumeRoleSource.secretKey = c.SecretKey
+		c.assumeRoleSource.securityToken = c.SecurityToken
+		return c.reloadAssumeRole()
+	}
+	return buildClientByAKSK(c)
+}
+
+func (c *Config) reloadAssumeRole() error {
+	err := getAuthConfigByAgency(c)
 	if err != nil {
-		return fmt.Errorf("Error reloading Auth credentials from ECS Metadata API: %s", err)
+		return fmt.Errorf("Error reloading the temporary credentials by assume role: %s", err)
 	}
-	log.Printf("Successfully reload metadata security key, which will expire at: %s", c.SecurityKeyExpiresAt)
+	log.Printf("Successfully re-assumed role, the temporary credentials will expire at: %s", c.AssumeRoleExpiresAt)
 	return buildClientByAKSK(c)
 }
 
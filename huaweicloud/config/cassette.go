@@ -0,0 +1,352 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// The cassette mode is used to record the API requests and responses to a file, and replay them later without
+// network access, it is enabled by the following environment variables:
+//   - HW_CASSETTE_MODE: the mode of cassette, the valid values are record and replay;
+//   - HW_CASSETTE_FILE: the path of the cassette file, defaults to testdata/cassette.json.
+const (
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+
+	defaultCassetteFile = "testdata/cassette.json"
+)
+
+// the headers contain the credentials, which will be scrubbed before saving to the cassette file
+var cassetteSensitiveHeaders = []string{"Authorization", "X-Auth-Token", "X-Subject-Token", "X-Security-Token"}
+
+// Cassette is the recorded API interactions.
+type Cassette struct {
+	// Env is the environment variables when recording, such as the region name
+	Env          map[string]string `json:"env,omitempty"`
+	Interactions []*Interaction    `json:"interactions"`
+
+	path string
+	mode string
+	lock sync.Mutex
+	// secrets are the credential values which will be scrubbed wherever they appear in the headers and bodies
+	secrets []string
+	// replayed is the count of replayed interactions of each request key
+	replayed map[string]int
+}
+
+// Interaction is a pair of the recorded request and response.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+var (
+	activeCassette     *Cassette
+	activeCassetteErr  error
+	activeCassetteOnce sync.Once
+)
+
+// CassetteMode returns the mode of cassette, an empty string means the cassette mode is disabled.
+func CassetteMode() string {
+	mode := os.Getenv("HW_CASSETTE_MODE")
+	if mode == CassetteModeRecord || mode == CassetteModeReplay {
+		return mode
+	}
+	return ""
+}
+
+// GetCassette returns the cassette specified by the environment variables, it is loaded once in the process.
+// Nil will be returned if the cassette mode is disabled.
+func GetCassette() (*Cassette, error) {
+	activeCassetteOnce.Do(func() {
+		mode := CassetteMode()
+		if mode == "" {
+			return
+		}
+
+		path := os.Getenv("HW_CASSETTE_FILE")
+		if path == "" {
+			path = defaultCassetteFile
+		}
+		activeCassette, activeCassetteErr = loadCassette(path, mode)
+	})
+	return activeCassette, activeCassetteErr
+}
+
+func loadCassette(path, mode string) (*Cassette, error) {
+	cassette := Cassette{
+		Env:      make(map[string]string),
+		path:     path,
+		mode:     mode,
+		replayed: make(map[string]int),
+	}
+	if mode == CassetteModeRecord {
+		return &cassette, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the cassette file %s: %s", path, err)
+	}
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("error parsing the cassette file %s: %s", path, err)
+	}
+	if cassette.Env == nil {
+		cassette.Env = make(map[string]string)
+	}
+	log.Printf("[DEBUG] loaded %d interactions from the cassette file %s", len(cassette.Interactions), path)
+	return &cassette, nil
+}
+
+// Mode returns the mode of the cassette.
+func (c *Cassette) Mode() string {
+	return c.mode
+}
+
+// Save writes the recorded interactions to the cassette file, it does nothing in replay mode.
+func (c *Cassette) Save() error {
+	if c == nil || c.mode != CassetteModeRecord {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
+
+// SetEnv saves an environment variable to the cassette, which can be restored in replay mode.
+func (c *Cassette) SetEnv(key, value string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Env[key] = value
+}
+
+// GetEnv returns the environment variable saved in the cassette.
+func (c *Cassette) GetEnv(key string) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.Env[key]
+}
+
+// AddSecrets adds the credential values which should be scrubbed in record mode, the empty values are ignored.
+func (c *Cassette) AddSecrets(secrets ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, v := range secrets {
+		if v != "" {
+			c.secrets = append(c.secrets, v)
+		}
+	}
+}
+
+// scrubSecrets replaces the credential values with asterisks.
+func (c *Cassette) scrubSecrets(s string) string {
+	for _, v := range c.secrets {
+		s = strings.ReplaceAll(s, v, "***")
+	}
+	return s
+}
+
+// cassetteRequestKey returns the key to match the recorded interactions, the requests with the same key are
+// replayed in the recorded order. The query parameters are sorted, so the order of them doesn't matter.
+func cassetteRequestKey(method, rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL
+	}
+	parsed.RawQuery = parsed.Query().Encode()
+	return method + " " + parsed.String()
+}
+
+func (c *Cassette) record(request *http.Request, requestBody []byte, response *http.Response) error {
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	interaction := Interaction{
+		Request: CassetteRequest{
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Headers: c.scrubHeaders(request.Header),
+			Body:    c.scrubSecrets(scrubBody(requestBody)),
+		},
+		Response: CassetteResponse{
+			StatusCode: response.StatusCode,
+			Headers:    c.scrubHeaders(response.Header),
+			Body:       c.scrubSecrets(scrubBody(responseBody)),
+		},
+	}
+	c.Interactions = append(c.Interactions, &interaction)
+	return nil
+}
+
+func (c *Cassette) replay(request *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := cassetteRequestKey(request.Method, request.URL.String())
+	var matched []*Interaction
+	for _, v := range c.Interactions {
+		if cassetteRequestKey(v.Request.Method, v.Request.URL) == key {
+			matched = append(matched, v)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no interaction found in the cassette for request: %s", key)
+	}
+
+	// the last interaction will be replayed repeatedly if the recorded interactions are used up
+	index := c.replayed[key]
+	if index >= len(matched) {
+		index = len(matched) - 1
+	}
+	c.replayed[key]++
+
+	interaction := matched[index]
+	response := &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       request,
+	}
+	if response.Header == nil {
+		response.Header = make(http.Header)
+	}
+	return response, nil
+}
+
+// CassetteRoundTripper satisfies the http.RoundTripper interface, it records the API requests and responses
+// in record mode, or replays the recorded responses without network access in replay mode.
+type CassetteRoundTripper struct {
+	Rt       http.RoundTripper
+	Cassette *Cassette
+}
+
+// RoundTrip performs a round-trip HTTP request, or replays it from the cassette.
+func (crt *CassetteRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if crt.Cassette.mode == CassetteModeReplay {
+		if request.Body != nil {
+			request.Body.Close()
+		}
+		return crt.Cassette.replay(request)
+	}
+
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	response, err := crt.Rt.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := crt.Cassette.record(request, requestBody, response); err != nil {
+		log.Printf("[WARN] failed to record the API interaction: %s", err)
+	}
+	return response, nil
+}
+
+// withCassette wraps the transport with the cassette if the cassette mode is enabled.
+func withCassette(rt http.RoundTripper) (http.RoundTripper, error) {
+	cassette, err := GetCassette()
+	if err != nil || cassette == nil {
+		return rt, err
+	}
+	return &CassetteRoundTripper{Rt: rt, Cassette: cassette}, nil
+}
+
+func (c *Cassette) scrubHeaders(headers http.Header) http.Header {
+	result := headers.Clone()
+	for _, name := range cassetteSensitiveHeaders {
+		if result.Get(name) != "" {
+			result.Set(name, "***")
+		}
+	}
+	for name, values := range result {
+		for i, v := range values {
+			result[name][i] = c.scrubSecrets(v)
+		}
+	}
+	return result
+}
+
+// scrubBody masks the sensitive fields of the JSON body, the other format body is kept as it is.
+func scrubBody(body []byte) string {
+	var data interface{}
+	if len(body) == 0 || json.Unmarshal(body, &data) != nil {
+		return string(body)
+	}
+
+	scrubbed, err := json.Marshal(scrubSecurityFields(data))
+	if err != nil {
+		return string(body)
+	}
+	return string(scrubbed)
+}
+
+// scrubSecurityFields masks the known fields which contain sensitive information, the same as maskSecurityFields,
+// but it keeps the large strings and processes the fields in the lists.
+func scrubSecurityFields(data interface{}) interface{} {
+	switch val := data.(type) {
+	case map[string]interface{}:
+		for k, v := range val {
+			if _, ok := v.(string); ok && isSecurityFields(k) {
+				val[k] = "***"
+				continue
+			}
+			val[k] = scrubSecurityFields(v)
+		}
+	case []interface{}:
+		for i, v := range val {
+			val[i] = scrubSecurityFields(v)
+		}
+	}
+	return data
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			_, _ = w.Write([]byte(`{"status": "CREATING", "password": "Test@123"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "ACTIVE", "password": "Test@123"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := loadCassette(path, CassetteModeRecord)
+	th.AssertNoErr(t, err)
+	recorder.SetEnv("HW_REGION_NAME", "cn-north-4")
+	recorder.AddSecrets("secret-access-key")
+
+	client := http.Client{Transport: &CassetteRoundTripper{Rt: http.DefaultTransport, Cassette: recorder}}
+	for i := 0; i < 2; i++ {
+		request, err := http.NewRequest(http.MethodGet, server.URL+"/v1/servers?limit=10&offset=0", nil)
+		th.AssertNoErr(t, err)
+		request.Header.Set("X-Auth-Token", "secret-token")
+		request.Header.Set("X-Access-Key", "secret-access-key")
+
+		response, err := client.Do(request)
+		th.AssertNoErr(t, err)
+		_, _ = io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+	}
+	th.AssertNoErr(t, recorder.Save())
+
+	// the credentials are not saved to the cassette file
+	data, err := os.ReadFile(path)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, strings.Contains(string(data), "secret-token"))
+	th.AssertEquals(t, false, strings.Contains(string(data), "secret-access-key"))
+	th.AssertEquals(t, false, strings.Contains(string(data), "Test@123"))
+
+	player, err := loadCassette(path, CassetteModeReplay)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "cn-north-4", player.GetEnv("HW_REGION_NAME"))
+
+	// the server is closed, the responses are replayed in the recorded order with the query parameters reordered
+	server.Close()
+	client = http.Client{Transport: &CassetteRoundTripper{Cassette: player}}
+	for _, expected := range []string{"CREATING", "ACTIVE", "ACTIVE"} {
+		response, err := client.Get(server.URL + "/v1/servers?offset=0&limit=10")
+		th.AssertNoErr(t, err)
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, true, strings.Contains(string(body), expected))
+	}
+
+	_, err = client.Get(server.URL + "/v1/networks")
+	th.AssertEquals(t, true, err != nil)
+}
@@ -1,11 +1,9 @@
 package config
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"math"
 	"os"
 	"strings"
 	"sync"
@@ -21,6 +19,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/obs"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/mutexkv"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/ratelimit"
 )
 
 const (
@@ -30,7 +29,8 @@ const (
 
 // MutexKV is a global lock on all resources, it can lock the specified shared string (such as resource ID, resource
 // Name, port, etc.) to prevent other resources from using it, for concurrency control.
-// Usage: MutexKV.Lock({resource ID}) and MutexKV.Unlock({resource ID})
+// Usage: MutexKV.Lock({resource ID}) and MutexKV.Unlock({resource ID}), use MutexKV.RLock and MutexKV.RUnlock if the
+// shared resource is only read, and MutexKV.LockContext to give up waiting when the context is done or timed out.
 var MutexKV = mutexkv.NewMutexKV()
 
 type Config struct {
@@ -58,18 +58,51 @@ type Config struct {
 	SharedConfigFile    string
 	Profile             string
 
-	// metadata security key expires at
+	// CredentialProcess is the command of the shared config profile which prints the temporary credentials
+	CredentialProcess string
+
+	// MaxRetryBackoff is the maximum waiting time in seconds between the retries of an API call,
+	// and RetryableErrorCodes is the additional error codes which should be retried.
+	MaxRetryBackoff     int
+	RetryableErrorCodes []string
+
+	// RateLimits is the maximum requests per second of the services, keyed by the service catalog names,
+	// and DefaultRateLimit is used for the services without an explicit setting. Zero means unlimited.
+	RateLimits       map[string]int
+	DefaultRateLimit int
+
+	// AssumeRoleChain is the roles to assume in order, AssumeRoleAgency and AssumeRoleDomain are the last one.
+	AssumeRoleChain []AssumeRole
+
+	// metadata or credential process security key expires at
 	SecurityKeyExpiresAt time.Time
+	// the temporary credentials of assume role expires at
+	AssumeRoleExpiresAt time.Time
 
 	HwClient     *golangsdk.ProviderClient
 	DomainClient *golangsdk.ProviderClient
 
+	// assumeRoleSource is the credentials used to assume role, they are kept to re-assume the role
+	// before the temporary credentials expire.
+	assumeRoleSource *credentialSource
+
+	// rateLimiters is the rate limiters of the services, and endpointServices is the service names of the
+	// customizing endpoint hosts.
+	rateLimiters     *ratelimit.LimiterKV
+	endpointServices map[string]string
+
 	// websiteType is the site type of HuaweiCloud.
 	// The value can be Chinese(default) and International.
 	websiteType string
 	// the custom endpoints used to override the default endpoint URL
 	Endpoints map[string]string
 
+	// DefaultTags is the tags configured in the provider, which will be applied to all taggable resources.
+	DefaultTags map[string]string
+	// IgnoreTagKeys and IgnoreTagKeyPrefixes are the tags which will be ignored by all resources and data sources.
+	IgnoreTagKeys        []string
+	IgnoreTagKeyPrefixes []string
+
 	// RegionProjectIDMap is a map which stores the region-projectId pairs,
 	// and region name will be the key and projectID will be the value in this map.
 	RegionProjectIDMap map[string]string
@@ -78,8 +111,15 @@ type Config struct {
 	// prevent sending duplicate query requests
 	RPLock *sync.Mutex
 
-	// SecurityKeyLock is used to make the accessing of SecurityKeyExpiresAt serial,
-	// prevent sending duplicate query metadata api
+	// ProjectScope is the IAM project specified by the resource-level project_id or project_name, which overrides
+	// the default project of the region, e.g. the sub-project cn-north-4_prod.
+	ProjectScope *ProjectScope
+	// scopeParent is the configuration which the project-scoped copy is derived from, the temporary credentials are
+	// reloaded by it and shared with the copy.
+	scopeParent *Config
+
+	// SecurityKeyLock is used to make the accessing of SecurityKeyExpiresAt and AssumeRoleExpiresAt serial,
+	// prevent sending duplicate query metadata api or assume role api
 	SecurityKeyLock *sync.Mutex
 
 	// Legacy
@@ -94,11 +134,26 @@ type Config struct {
 	Metadata any
 }
 
+// AssumeRole is the role to assume by the agency.
+type AssumeRole struct {
+	AgencyName string
+	DomainName string
+	// Duration is the validity period of the temporary credentials in seconds
+	Duration    int
+	SessionName string
+	// Policy is an inline policy in JSON format to scope the temporary credentials
+	Policy string
+}
+
 func (c *Config) LoadAndValidate() error {
 	if c.MaxRetries < 0 {
 		return fmt.Errorf("max_retries should be a positive value")
 	}
 
+	if err := c.initRateLimiters(); err != nil {
+		return err
+	}
+
 	err := buildClient(c)
 	if err != nil {
 		return err
@@ -207,29 +262,6 @@ func (c *Config) SetServiceEndpoint(service, endpoint string) {
 	}
 }
 
-func retryBackoffFunc(ctx context.Context, respErr *golangsdk.ErrUnexpectedResponseCode, e error, retries uint) error {
-	minutes := int(math.Pow(2, float64(retries)))
-	if minutes > 30 { // won't wait more than 30 minutes
-		minutes = 30
-	}
-
-	log.Printf("[WARN] Received StatusTooManyRequests response code, try to sleep %d minutes", minutes)
-	sleep := time.Duration(minutes) * time.Minute
-
-	if ctx != nil {
-		select {
-		case <-time.After(sleep):
-		case <-ctx.Done():
-			return e
-		}
-	} else {
-		//lintignore:R018
-		time.Sleep(sleep)
-	}
-
-	return nil
-}
-
 func getObsEndpoint(c *Config, region string) string {
 	if endpoint, ok := c.Endpoints["obs"]; ok {
 		// replace the region in customizing OBS endpoint
@@ -243,11 +275,20 @@ func getObsEndpoint(c *Config, region string) string {
 	return fmt.Sprintf("https://obs.%s.%s/", region, c.Cloud)
 }
 
+// ObjectStorageEndpoint returns the OBS endpoint of the region, e.g. https://obs.cn-north-4.myhuaweicloud.com/
+func (c *Config) ObjectStorageEndpoint(region string) string {
+	return getObsEndpoint(c, region)
+}
+
 func (c *Config) ObjectStorageClientWithSignature(region string) (*obs.ObsClient, error) {
 	if c.AccessKey == "" || c.SecretKey == "" {
 		return nil, fmt.Errorf("missing credentials for OBS, need access_key and secret_key values for provider")
 	}
 
+	if err := c.reloadExpiredCredentials(); err != nil {
+		return nil, err
+	}
+
 	clientConfigure := obs.WithHttpClient(&c.DomainClient.HTTPClient)
 	userAgentConfigure := obs.WithUserAgent(buildObsUserAgent())
 	envProxyConfigure := obs.WithProxyFromEnv(true)
@@ -266,17 +307,8 @@ func (c *Config) ObjectStorageClient(region string) (*obs.ObsClient, error) {
 		return nil, fmt.Errorf("missing credentials for OBS, need access_key and secret_key values for provider")
 	}
 
-	if !c.SecurityKeyExpiresAt.IsZero() {
-		c.SecurityKeyLock.Lock()
-		defer c.SecurityKeyLock.Unlock()
-		timeNow := time.Now().Unix()
-		expairesAtInt := c.SecurityKeyExpiresAt.Unix()
-		if timeNow+keyExpiresDuration > expairesAtInt {
-			err := c.reloadSecurityKey()
-			if err != nil {
-				return nil, err
-			}
-		}
+	if err := c.reloadExpiredCredentials(); err != nil {
+		return nil, err
 	}
 
 	clientConfigure := obs.WithHttpClient(&c.DomainClient.HTTPClient)
@@ -312,17 +344,8 @@ func (c *Config) NewServiceClient(srv, region string) (*golangsdk.ServiceClient,
 		serviceCatalog.Name = name
 	}
 
-	if !c.SecurityKeyExpiresAt.IsZero() {
-		c.SecurityKeyLock.Lock()
-		defer c.SecurityKeyLock.Unlock()
-		timeNow := time.Now().Unix()
-		expairesAtInt := c.SecurityKeyExpiresAt.Unix()
-		if timeNow+keyExpiresDuration > expairesAtInt {
-			err := c.reloadSecurityKey()
-			if err != nil {
-				return nil, err
-			}
-		}
+	if err := c.reloadExpiredCredentials(); err != nil {
+		return nil, err
 	}
 
 	client := c.HwClient
@@ -334,6 +357,17 @@ func (c *Config) NewServiceClient(srv, region string) (*golangsdk.ServiceClient,
 		if region != "" && region != c.Region {
 			return nil, fmt.Errorf("Resource-level region must be the same as Provider-level region when using customizing endpoints")
 		}
+		if c.ProjectScope != nil && !serviceCatalog.WithOutProjectID {
+			projectID, err := c.getProjectIDByRegion(client, c.Region)
+			if err != nil {
+				return nil, err
+			}
+			clone := new(golangsdk.ProviderClient)
+			*clone = *client
+			clone.ProjectID = projectID
+			clone.AKSKAuthOptions.ProjectId = projectID
+			client = clone
+		}
 		return c.newServiceClientByEndpoint(client, srv, endpoint)
 	}
 	return c.newServiceClientByName(client, serviceCatalog, region)
@@ -349,17 +383,13 @@ func (c *Config) newServiceClientByName(client *golangsdk.ProviderClient, catalo
 	if region != c.Region && (c.AccessKey == "" || c.SecretKey == "") {
 		return nil, fmt.Errorf("Resource-level region must be the same as Provider-level region when using non AK/SK authentication if Resource-level region set")
 	}
+	if c.ProjectScope != nil && (c.AccessKey == "" || c.SecretKey == "") {
+		return nil, fmt.Errorf("Resource-level project_id or project_name only supports AK/SK authentication")
+	}
 
-	c.RPLock.Lock()
-	defer c.RPLock.Unlock()
-	projectID, ok := c.RegionProjectIDMap[region]
-	if !ok {
-		// Not find in the map, then try to query and store.
-		err := c.loadUserProjects(client, region)
-		if err != nil {
-			return nil, err
-		}
-		projectID = c.RegionProjectIDMap[region]
+	projectID, err := c.getProjectIDByRegion(client, region)
+	if err != nil {
+		return nil, err
 	}
 
 	// update ProjectID and region in ProviderClient
@@ -512,22 +542,80 @@ func (c *Config) loadUserProjects(client *golangsdk.ProviderClient, region strin
 	return nil
 }
 
-// GetProjectID is used to get the project ID for services
+// GetProjectID is used to get the project ID for services, an empty string is returned if it's not found. The
+// project scope of a resource is validated by ValidateProjectScope before the resource uses the configuration, so the
+// scoped configuration doesn't return an empty project ID because of the scope.
 func (c *Config) GetProjectID(region string) string {
+	projectID, err := c.getProjectIDByRegion(c.DomainClient, region)
+	if err != nil {
+		log.Printf("[WARN] can not find the project ID of %s: %s", region, err)
+		return ""
+	}
+	return projectID
+}
+
+// getProjectIDByRegion returns the project ID of the region, or the project ID of the project scope if specified.
+// The project IDs are queried lazily and cached in RegionProjectIDMap, whose key is the region or the project name.
+func (c *Config) getProjectIDByRegion(client *golangsdk.ProviderClient, region string) (string, error) {
+	key := region
+	if scope := c.ProjectScope; scope != nil {
+		if scope.ID != "" {
+			return scope.ID, nil
+		}
+		// the name of the sub-project starts with the region, e.g. cn-north-4_prod
+		if scope.Name != region && !strings.HasPrefix(scope.Name, region+"_") {
+			return "", fmt.Errorf("the project %s does not belong to the region %s", scope.Name, region)
+		}
+		key = scope.Name
+	}
+
 	c.RPLock.Lock()
 	defer c.RPLock.Unlock()
-
-	projectID, ok := c.RegionProjectIDMap[region]
+	projectID, ok := c.RegionProjectIDMap[key]
 	if !ok {
 		// Not find in the map, then try to query and store.
-		if err := c.loadUserProjects(c.DomainClient, region); err != nil {
-			log.Printf("[WARN] can not find the project ID of %s: %s", region, err)
-			return ""
+		if err := c.loadUserProjects(client, key); err != nil {
+			return "", err
 		}
-		projectID = c.RegionProjectIDMap[region]
+		projectID = c.RegionProjectIDMap[key]
 	}
+	return projectID, nil
+}
 
-	return projectID
+// ProjectScope is the IAM project used by a resource instead of the default project of the region, only one of the
+// ID and the name is specified.
+type ProjectScope struct {
+	ID   string
+	Name string
+}
+
+// WithProjectScope returns a copy of the configuration whose service clients are scoped to the project specified by
+// the ID or the name, the project IDs queried by the name are cached in the original configuration, and the temporary
+// credentials are reloaded by the original configuration.
+func (c *Config) WithProjectScope(projectID, projectName string) *Config {
+	if projectID == "" && projectName == "" {
+		return c
+	}
+
+	clone := *c
+	if clone.scopeParent == nil {
+		clone.scopeParent = c
+	}
+	clone.ProjectScope = &ProjectScope{
+		ID:   projectID,
+		Name: projectName,
+	}
+	return &clone
+}
+
+// ValidateProjectScope checks whether the project of the scope exists in the region, nil will be returned if the
+// configuration is not scoped.
+func (c *Config) ValidateProjectScope(region string) error {
+	if c.ProjectScope == nil {
+		return nil
+	}
+	_, err := c.getProjectIDByRegion(c.DomainClient, region)
+	return err
 }
 
 // GetRegion returns the region that was specified in the resource. If a
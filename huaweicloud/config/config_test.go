@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/chnsz/golangsdk"
 	th "github.com/chnsz/golangsdk/testhelper"
@@ -80,3 +82,83 @@ func TestCheckObsEndpoint(t *testing.T) {
 	expected = "https://oss.region-1.myhuaweicloud.com/"
 	th.AssertEquals(t, expected, getObsEndpoint(cfg, "region-1"))
 }
+
+func TestProjectScope(t *testing.T) {
+	cfg := &Config{
+		Region:    "cn-north-4",
+		Cloud:     "myhuaweicloud.com",
+		AccessKey: "ak",
+		SecretKey: "sk",
+		HwClient:  &golangsdk.ProviderClient{ProjectID: "default"},
+		RegionProjectIDMap: map[string]string{
+			"cn-north-4":      "default",
+			"cn-north-4_prod": "prod",
+		},
+		RPLock: new(sync.Mutex),
+	}
+
+	client, err := cfg.NewServiceClient("ecs", "cn-north-4")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.cn-north-4.myhuaweicloud.com/v1/default/", client.ResourceBase)
+
+	scoped := cfg.WithProjectScope("", "cn-north-4_prod")
+	client, err = scoped.NewServiceClient("ecs", "cn-north-4")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.cn-north-4.myhuaweicloud.com/v1/prod/", client.ResourceBase)
+	th.AssertEquals(t, "prod", client.ProjectID)
+	th.AssertEquals(t, "prod", scoped.GetProjectID("cn-north-4"))
+
+	client, err = cfg.WithProjectScope("p1", "").NewServiceClient("ecs", "cn-north-4")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.cn-north-4.myhuaweicloud.com/v1/p1/", client.ResourceBase)
+
+	_, err = scoped.NewServiceClient("ecs", "cn-south-1")
+	th.AssertEquals(t, "the project cn-north-4_prod does not belong to the region cn-south-1", err.Error())
+
+	th.AssertNoErr(t, scoped.ValidateProjectScope("cn-north-4"))
+	th.AssertEquals(t, "the project cn-north-4_prod does not belong to the region cn-south-1",
+		scoped.ValidateProjectScope("cn-south-1").Error())
+
+	// the original configuration is not changed
+	th.AssertEquals(t, true, cfg.ProjectScope == nil)
+	th.AssertEquals(t, cfg, cfg.WithProjectScope("", ""))
+	th.AssertNoErr(t, cfg.ValidateProjectScope("cn-south-1"))
+}
+
+func TestProjectScopeSharesCredentials(t *testing.T) {
+	cfg := &Config{
+		Region:    "cn-north-4",
+		AccessKey: "ak",
+		SecretKey: "sk",
+		// the credentials don't expire in an hour, so they are not reloaded
+		SecurityKeyExpiresAt: time.Now().Add(time.Hour),
+		SecurityKeyLock:      new(sync.Mutex),
+	}
+	scoped := cfg.WithProjectScope("p1", "").WithProjectScope("p2", "")
+
+	// the credentials reloaded by the original configuration are used by the scoped copy
+	cfg.AccessKey, cfg.SecretKey, cfg.SecurityToken = "new-ak", "new-sk", "new-token"
+	th.AssertNoErr(t, scoped.reloadExpiredCredentials())
+	th.AssertEquals(t, "new-ak", scoped.AccessKey)
+	th.AssertEquals(t, "new-sk", scoped.SecretKey)
+	th.AssertEquals(t, "new-token", scoped.SecurityToken)
+	th.AssertEquals(t, "p2", scoped.ProjectScope.ID)
+}
+
+func TestNewHcClientTransportError(t *testing.T) {
+	conf := &Config{
+		AccessKey:      "ak",
+		SecretKey:      "sk",
+		DomainID:       "domain-id",
+		ClientCertFile: "invalid certificate",
+		ClientKeyFile:  "invalid key",
+		Endpoints:      map[string]string{"iam": "https://iam.example.com/"},
+	}
+
+	// the client must not be built without the logging, rate limit and trace round trippers
+	_, err := implNewHcClient(conf, "cn-north-4", "iam", true, false)
+	if err == nil {
+		t.Fatal("expected an error when the HTTP transport can not be built")
+	}
+	th.AssertEquals(t, true, strings.Contains(err.Error(), "failed to build the HTTP transport"))
+}
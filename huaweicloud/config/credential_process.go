@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// credentialProcessTimeout is the maximum time to wait for the credential process
+const credentialProcessTimeout = time.Minute
+
+// processCredential is the output of the credential process, which is the same as the temporary credentials returned
+// by IAM, e.g. the output of "hcloud IAM CreateTemporaryAccessKeyByToken". The credentials can also be wrapped in the
+// "credential" object. The credentials never expire if expires_at is omitted.
+type processCredential struct {
+	Access        string `json:"access"`
+	Secret        string `json:"secret"`
+	SecurityToken string `json:"securitytoken"`
+	ExpiresAt     string `json:"expires_at"`
+
+	expiresAt time.Time
+}
+
+// credentialProcessCache caches the credentials by the command, so that the command is not run by every provider
+// until the credentials are about to expire.
+var credentialProcessCache = struct {
+	sync.Mutex
+	items map[string]*processCredential
+}{
+	items: make(map[string]*processCredential),
+}
+
+// getAuthConfigByProcess sets the credentials returned by the credential process, the cached credentials are used if
+// they are not about to expire.
+func getAuthConfigByProcess(c *Config) error {
+	credentialProcessCache.Lock()
+	defer credentialProcessCache.Unlock()
+
+	credential, ok := credentialProcessCache.items[c.CredentialProcess]
+	if !ok || (!credential.expiresAt.IsZero() && time.Now().Unix()+keyExpiresDuration > credential.expiresAt.Unix()) {
+		var err error
+		credential, err = runCredentialProcess(c.CredentialProcess)
+		if err != nil {
+			return err
+		}
+		credentialProcessCache.items[c.CredentialProcess] = credential
+	}
+
+	c.AccessKey, c.SecretKey, c.SecurityToken = credential.Access, credential.Secret, credential.SecurityToken
+	c.SecurityKeyExpiresAt = credential.expiresAt
+	return nil
+}
+
+func runCredentialProcess(command string) (*processCredential, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running the credential process: %s: %s", err, stderr.String())
+	}
+
+	var result struct {
+		processCredential
+		Credential *processCredential `json:"credential"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("error parsing the output of the credential process: %s", err)
+	}
+
+	credential := &result.processCredential
+	if result.Credential != nil {
+		credential = result.Credential
+	}
+	if credential.Access == "" || credential.Secret == "" {
+		return nil, fmt.Errorf("the access and secret are missing in the output of the credential process")
+	}
+	if credential.ExpiresAt != "" {
+		credential.expiresAt, err = time.Parse(time.RFC3339, credential.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the expires_at of the credential process: %s", err)
+		}
+	}
+	return credential, nil
+}
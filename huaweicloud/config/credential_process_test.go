@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestCredentialProcessCacheAndRefresh(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential process of the test is a shell script")
+	}
+
+	// the script counts the runs and prints the credentials which expire after the given seconds
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "count")
+	writeScript := func(name string, expiresIn time.Duration) string {
+		expiresAt := time.Now().Add(expiresIn).UTC().Format(time.RFC3339)
+		script := filepath.Join(dir, name)
+		content := fmt.Sprintf(`#!/bin/sh
+echo run >> %s
+echo '{"credential": {"access": "ak", "secret": "sk", "securitytoken": "token", "expires_at": "%s"}}'
+`, counter, expiresAt)
+		th.AssertNoErr(t, os.WriteFile(script, []byte(content), 0700))
+		return script
+	}
+	runs := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "run")
+	}
+
+	c := Config{CredentialProcess: writeScript("valid.sh", time.Hour)}
+	th.AssertNoErr(t, getAuthConfigByProcess(&c))
+	th.AssertNoErr(t, getAuthConfigByProcess(&c))
+	th.AssertEquals(t, 1, runs())
+	th.AssertEquals(t, "ak", c.AccessKey)
+	th.AssertEquals(t, "sk", c.SecretKey)
+	th.AssertEquals(t, "token", c.SecurityToken)
+	th.AssertEquals(t, false, c.SecurityKeyExpiresAt.IsZero())
+
+	// the credentials which are about to expire are refreshed
+	c.CredentialProcess = writeScript("expiring.sh", time.Minute)
+	th.AssertNoErr(t, getAuthConfigByProcess(&c))
+	th.AssertNoErr(t, getAuthConfigByProcess(&c))
+	th.AssertEquals(t, 3, runs())
+}
+
+func TestCredentialProcessErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential process of the test is a shell command")
+	}
+
+	cases := map[string]string{
+		"exit 1":                  "error running the credential process",
+		"echo not-json":           "error parsing the output of the credential process",
+		`echo '{"access": "ak"}'`: "the access and secret are missing",
+		`echo '{"access": "ak", "secret": "sk", "expires_at": "tomorrow"}'`: "error parsing the expires_at",
+	}
+	for command, message := range cases {
+		_, err := runCredentialProcess(command)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("expected the error of %q to contain %q, got: %v", command, message, err)
+		}
+	}
+}
+
+func TestApplySharedProfileModes(t *testing.T) {
+	profile := Profile{
+		Name:            "test",
+		AccessKeyId:     "ak",
+		SecretAccessKey: "sk",
+		SecurityToken:   "token",
+		Region:          "cn-north-4",
+		AgencyName:      "agency",
+	}
+
+	for _, mode := range []string{"", "AKSK", "STS"} {
+		c := Config{}
+		profile.Mode = mode
+		th.AssertNoErr(t, applySharedProfile(&c, &profile))
+		th.AssertEquals(t, "ak", c.AccessKey)
+		th.AssertEquals(t, "token", c.SecurityToken)
+		th.AssertEquals(t, "cn-north-4", c.Region)
+		th.AssertEquals(t, "agency", c.AssumeRoleAgency)
+	}
+
+	profile.Mode = "STS"
+	profile.SecurityToken = ""
+	err := applySharedProfile(&Config{}, &profile)
+	th.AssertEquals(t, true, err != nil && strings.Contains(err.Error(), "securityToken"))
+
+	profile.Mode = "SSO"
+	err = applySharedProfile(&Config{}, &profile)
+	th.AssertEquals(t, true, err != nil && strings.Contains(err.Error(), "not supported"))
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// extraEndpointKeys are the keys of the customizing endpoints which are not in the service catalog.
+var extraEndpointKeys = []string{"obs"}
+
+// versionSuffix matches the version suffix of the keys, e.g. v3 of rdsv3
+var versionSuffix = regexp.MustCompile(`^(.+?)v\d+$`)
+
+// CatalogEndpoint is the endpoint of a service in the catalog resolved for a region.
+type CatalogEndpoint struct {
+	Service    string `json:"service"`
+	Product    string `json:"product,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Scope      string `json:"scope"`
+	Endpoint   string `json:"endpoint"`
+	URL        string `json:"url"`
+	Customized bool   `json:"customized"`
+}
+
+// EndpointKeys returns all the keys that can be used to customize the service endpoints in order.
+func EndpointKeys() []string {
+	keys := make([]string, 0, len(allServiceCatalog)+len(multiCatalogKeys)+len(extraEndpointKeys))
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	for key := range allServiceCatalog {
+		add(key)
+	}
+	for key := range multiCatalogKeys {
+		add(key)
+	}
+	for _, key := range extraEndpointKeys {
+		add(key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateEndpointKey returns an error with the most similar keys if the key of the customizing endpoint is unknown.
+func ValidateEndpointKey(key string) error {
+	keys := EndpointKeys()
+	for _, k := range keys {
+		if k == key {
+			return nil
+		}
+	}
+
+	suggestions := similarKeys(key, keys)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("unknown endpoint key %q", key)
+	}
+	return fmt.Errorf("unknown endpoint key %q, did you mean %s?", key, strings.Join(suggestions, " or "))
+}
+
+// NormalizeEndpoints validates the keys of the customizing endpoints and completes the URLs with the "https://"
+// prefix and the "/" suffix, the endpoints of the services with multiple versions are shared by the derived keys.
+func NormalizeEndpoints(endpoints map[string]string) (map[string]string, error) {
+	epMap := make(map[string]string)
+
+	for key, val := range endpoints {
+		if err := ValidateEndpointKey(key); err != nil {
+			return nil, err
+		}
+
+		endpoint := strings.TrimSpace(val)
+		// check empty string
+		if endpoint == "" {
+			return nil, fmt.Errorf("the value of customer endpoint %s must be specified", key)
+		}
+
+		// add prefix "https://" and suffix "/"
+		if !strings.HasPrefix(endpoint, "http") {
+			endpoint = fmt.Sprintf("https://%s", endpoint)
+		}
+		if !strings.HasSuffix(endpoint, "/") {
+			endpoint = fmt.Sprintf("%s/", endpoint)
+		}
+		epMap[key] = endpoint
+	}
+
+	// unify the endpoint which has multiple versions
+	for key := range endpoints {
+		ep, ok := epMap[key]
+		if !ok {
+			continue
+		}
+
+		multiKeys := GetServiceDerivedCatalogKeys(key)
+		for _, k := range multiKeys {
+			epMap[k] = ep
+		}
+	}
+	return epMap, nil
+}
+
+// ResolveServiceCatalog returns the endpoints of all the services in the catalog for the region, which are resolved
+// offline in the same way as the service clients, the Cloud, RegionClient and Endpoints of the config are used.
+func ResolveServiceCatalog(c *Config, region string) []CatalogEndpoint {
+	services := make([]string, 0, len(allServiceCatalog))
+	for srv := range allServiceCatalog {
+		services = append(services, srv)
+	}
+	sort.Strings(services)
+
+	result := make([]CatalogEndpoint, 0, len(services))
+	for _, srv := range services {
+		catalog := allServiceCatalog[srv]
+		scope := "project"
+		if catalog.Scope == "global" && !c.RegionClient {
+			scope = "global"
+		}
+		_, customized := c.Endpoints[srv]
+
+		endpoint := GetServiceEndpoint(c, srv, region)
+		url := endpoint
+		if catalog.Version != "" {
+			url += catalog.Version + "/"
+		}
+		if !catalog.WithOutProjectID {
+			url += "{project_id}/"
+		}
+		if catalog.ResourceBase != "" {
+			url += catalog.ResourceBase + "/"
+		}
+
+		result = append(result, CatalogEndpoint{
+			Service:    srv,
+			Product:    catalog.Product,
+			Version:    catalog.Version,
+			Scope:      scope,
+			Endpoint:   endpoint,
+			URL:        url,
+			Customized: customized,
+		})
+	}
+	return result
+}
+
+// similarKeys returns at most three keys which are the most similar to the key.
+func similarKeys(key string, keys []string) []string {
+	type candidate struct {
+		key      string
+		distance int
+	}
+
+	maxDistance := len(key) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	candidates := make([]candidate, 0)
+	for _, k := range keys {
+		if d := editDistance(key, k); d <= maxDistance {
+			candidates = append(candidates, candidate{key: k, distance: d})
+		}
+	}
+	// the key without the version suffix is the most likely one, e.g. rds for rdsv3
+	if matches := versionSuffix.FindStringSubmatch(key); len(matches) == 2 {
+		for i := range candidates {
+			if candidates[i].key == matches[1] {
+				candidates[i].distance = 0
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	result := make([]string, 0, 3)
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		result = append(result, fmt.Sprintf("%q", candidates[i].key))
+	}
+	return result
+}
+
+// editDistance returns the Levenshtein distance between the two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestValidateEndpointKey(t *testing.T) {
+	th.AssertNoErr(t, ValidateEndpointKey("rds"))
+	th.AssertNoErr(t, ValidateEndpointKey("config"))
+	th.AssertNoErr(t, ValidateEndpointKey("obs"))
+
+	err := ValidateEndpointKey("rdsv3")
+	th.AssertEquals(t, `unknown endpoint key "rdsv3", did you mean "rds" or "rdsv1" or "rdsv31"?`, err.Error())
+
+	err = ValidateEndpointKey("ecss")
+	th.AssertEquals(t, `unknown endpoint key "ecss", did you mean "css" or "ecs" or "bcs"?`, err.Error())
+
+	err = ValidateEndpointKey("unknown-service")
+	th.AssertEquals(t, `unknown endpoint key "unknown-service"`, err.Error())
+}
+
+func TestNormalizeEndpoints(t *testing.T) {
+	endpoints, err := NormalizeEndpoints(map[string]string{"ecs": " ecs.example.com "})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.example.com/", endpoints["ecs"])
+	th.AssertEquals(t, "https://ecs.example.com/", endpoints["ecsv21"])
+
+	_, err = NormalizeEndpoints(map[string]string{"ecs": ""})
+	th.AssertEquals(t, "the value of customer endpoint ecs must be specified", err.Error())
+	_, err = NormalizeEndpoints(map[string]string{"ecss": "ecs.example.com"})
+	th.AssertEquals(t, true, err != nil)
+}
+
+func TestResolveServiceCatalog(t *testing.T) {
+	cfg := &Config{
+		Cloud:     "example.com",
+		Endpoints: map[string]string{"vpc": "https://vpc.internal/"},
+	}
+
+	catalog := make(map[string]CatalogEndpoint)
+	for _, ep := range ResolveServiceCatalog(cfg, "region-1") {
+		catalog[ep.Service] = ep
+	}
+	th.AssertEquals(t, "https://ecs.region-1.example.com/v1/{project_id}/", catalog["ecs"].URL)
+	th.AssertEquals(t, "project", catalog["ecs"].Scope)
+	th.AssertEquals(t, "https://mkt.example.com/", catalog["mkt"].Endpoint)
+	th.AssertEquals(t, "global", catalog["mkt"].Scope)
+	th.AssertEquals(t, "https://vpc.internal/", catalog["vpc"].Endpoint)
+	th.AssertEquals(t, true, catalog["vpc"].Customized)
+
+	cfg.RegionClient = true
+	for _, ep := range ResolveServiceCatalog(cfg, "region-1") {
+		if ep.Service == "mkt" {
+			th.AssertEquals(t, "https://mkt.region-1.example.com/", ep.Endpoint)
+			th.AssertEquals(t, "project", ep.Scope)
+		}
+	}
+}
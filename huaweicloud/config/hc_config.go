@@ -9,13 +9,11 @@ import (
 	"net/url"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/basic"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/global"
 	hcconfig "github.com/huaweicloud/huaweicloud-sdk-go-v3/core/config"
-	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/httphandler"
 	hcregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
 	aomv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/aom/v2"
 	ccev3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cce/v3"
@@ -61,17 +59,9 @@ func buildAuthCredentials(c *Config, region string, isDerived bool) (*basic.Cred
 		credentials.DerivedPredicate = basic.DefaultDerivedPredicate
 	}
 
-	c.RPLock.Lock()
-	defer c.RPLock.Unlock()
-	projectID, ok := c.RegionProjectIDMap[region]
-	if !ok {
-		// Not find in the map, then try to query and store.
-		client := c.HwClient
-		err := c.loadUserProjects(client, region)
-		if err != nil {
-			return nil, err
-		}
-		projectID = c.RegionProjectIDMap[region]
+	projectID, err := c.getProjectIDByRegion(c.HwClient, region)
+	if err != nil {
+		return nil, err
 	}
 
 	credentials.ProjectId = projectID
@@ -94,7 +84,7 @@ func buildGlobalAuthCredentials(c *Config, region string) (*global.Credentials,
 	return &credentials, nil
 }
 
-func buildHTTPConfig(c *Config) *hcconfig.HttpConfig {
+func buildHTTPConfig(c *Config) (*hcconfig.HttpConfig, error) {
 	httpConfig := hcconfig.DefaultHttpConfig()
 
 	if c.MaxRetries > 0 {
@@ -105,11 +95,6 @@ func buildHTTPConfig(c *Config) *hcconfig.HttpConfig {
 		httpConfig = httpConfig.WithIgnoreSSLVerification(true)
 	}
 
-	httpHandler := httphandler.NewHttpHandler().
-		AddRequestHandler(logRequestHandler).
-		AddResponseHandler(logResponseHandler)
-	httpConfig = httpConfig.WithHttpHandler(httpHandler)
-
 	if proxyURL := getProxyFromEnv(); proxyURL != "" {
 		if parsed, err := url.Parse(proxyURL); err == nil {
 			logp.Printf("[DEBUG] using https proxy: %s://%s", parsed.Scheme, parsed.Host)
@@ -129,7 +114,13 @@ func buildHTTPConfig(c *Config) *hcconfig.HttpConfig {
 		}
 	}
 
-	return httpConfig
+	// the HTTP transport has the highest priority, the proxy and SSL verification are handled by it
+	transport, err := newHcTransport(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the HTTP transport: %s", err)
+	}
+
+	return httpConfig.WithHttpTransport(transport), nil
 }
 
 // HcVpcV3Client is the VPC service client using huaweicloud-sdk-go-v3 package
@@ -234,6 +225,10 @@ func (c *Config) HcMpcV1Client(region string) (*mpcv1.MpcClient, error) {
 
 // HcIoTdaV5Client is the IoTDA service client using huaweicloud-sdk-go-v3 package
 func (c *Config) HcIoTdaV5Client(region string, isDerived bool) (*iotdav5.IoTDAClient, error) {
+	if err := c.reloadExpiredCredentials(); err != nil {
+		return nil, err
+	}
+
 	hcClient, err := implNewHcClient(c, region, "iotda", false, isDerived)
 	if err != nil {
 		return nil, err
@@ -315,6 +310,9 @@ func (c *Config) HcCceV3Client(region string) (*ccev3.CceClient, error) {
 
 // NewHcClient is the common client using huaweicloud-sdk-go-v3 package
 func NewHcClient(c *Config, region, product string, isGlobal bool) (*core.HcHttpClient, error) {
+	if err := c.reloadExpiredCredentials(); err != nil {
+		return nil, err
+	}
 	return implNewHcClient(c, region, product, isGlobal, false)
 }
 
@@ -324,9 +322,14 @@ func implNewHcClient(c *Config, region, product string, isGlobal, isDerived bool
 		return nil, fmt.Errorf("failed to get the endpoint of %q service in region %s", product, region)
 	}
 
+	httpConfig, err := buildHTTPConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
 	builder := core.NewHcHttpClientBuilder().
 		WithRegion(hcregion.NewRegion(region, endpoint)).
-		WithHttpConfig(buildHTTPConfig(c))
+		WithHttpConfig(httpConfig)
 
 	if isGlobal {
 		credentials, err := buildGlobalAuthCredentials(c, region)
@@ -358,6 +361,45 @@ func implNewHcClient(c *Config, region, product string, isGlobal, isDerived bool
 	return builder.Build().PreInvoke(headers), nil
 }
 
+// newHcTransport returns the transport for huaweicloud-sdk-go-v3 which logs the requests, limits the request rate,
+// sends the requests through the cassette and records them in the API trace.
+func newHcTransport(c *Config) (*http.Transport, error) {
+	cassette, err := GetCassette()
+	if err != nil {
+		return nil, err
+	}
+	tracer, err := GetAPITracer()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := generateTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	var rt http.RoundTripper = &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	if cassette != nil {
+		rt = &CassetteRoundTripper{Rt: rt, Cassette: cassette}
+	}
+	if tracer != nil {
+		rt = &traceRoundTripper{Rt: rt, Tracer: tracer}
+	}
+	// the request handlers can not abort the request, so the rate limit is applied by the transport
+	if c.rateLimiters != nil {
+		rt = &rateLimitRoundTripper{Rt: rt, Config: c}
+	}
+	rt = &hcLogRoundTripper{Rt: rt}
+
+	// the registered protocols take precedence over the default implementation of http.Transport
+	transport := &http.Transport{}
+	transport.RegisterProtocol("https", rt)
+	transport.RegisterProtocol("http", rt)
+	return transport, nil
+}
+
 func getProxyFromEnv() string {
 	var url string
 
@@ -372,28 +414,56 @@ func getProxyFromEnv() string {
 	return url
 }
 
-func logRequestHandler(request http.Request) {
-	requestAt := fmt.Sprintf("%d-0", time.Now().UnixMilli())
-	log.Printf("[DEBUG] [%s] API Request URL: %s %s\nAPI Request Headers:\n%s",
-		requestAt, request.Method, request.URL, FormatHeaders(request.Header, "\n"))
+// hcLogRoundTripper logs the API requests and responses of huaweicloud-sdk-go-v3. The request handlers of the SDK
+// only get a copy of the request, so the log ID is assigned here and stored in the request context, which makes the
+// API trace records share the same ID with the logs.
+type hcLogRoundTripper struct {
+	Rt http.RoundTripper
+}
 
-	if request.Body != nil {
-		if err := logRequest(request.Body, request.Header.Get("Content-Type"), requestAt); err != nil {
-			log.Printf("[WARN] [%s] failed to log API Request Body: %s", requestAt, err)
+func (lrt *hcLogRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	logId := newLogID()
+	request = withLogID(request, logId)
+
+	log.Printf("[DEBUG] [%s] API Request URL: %s %s\nAPI Request Headers:\n%s",
+		logId, request.Method, request.URL, FormatHeaders(request.Header, "\n"))
+	if request.Body != nil && !isStreamContent(request.Header) {
+		body, err := io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		if err := logRequest(io.NopCloser(bytes.NewReader(body)), request.Header.Get("Content-Type"), logId); err != nil {
+			log.Printf("[WARN] [%s] failed to log API Request Body: %s", logId, err)
 		}
 	}
-}
 
-func logResponseHandler(response http.Response) {
-	responseAt := fmt.Sprintf("%d-0", time.Now().UnixMilli())
-	log.Printf("[DEBUG] [%s] API Response Code: %d\nAPI Response Headers:\n%s",
-		responseAt, response.StatusCode, FormatHeaders(response.Header, "\n"))
+	response, err := lrt.Rt.RoundTrip(request)
+	if response == nil {
+		return response, err
+	}
 
-	if response.Body != nil {
-		if err := logResponse(response.Body, response.Header.Get("Content-Type"), responseAt); err != nil {
-			log.Printf("[WARN] [%s] failed to log API Response Body: %s", responseAt, err)
+	log.Printf("[DEBUG] [%s] API Response Code: %d\nAPI Response Headers:\n%s",
+		logId, response.StatusCode, FormatHeaders(response.Header, "\n"))
+	if response.Body != nil && !isStreamContent(response.Header) {
+		body, readErr := io.ReadAll(response.Body)
+		response.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		response.Body = io.NopCloser(bytes.NewReader(body))
+		if logErr := logResponse(io.NopCloser(bytes.NewReader(body)), response.Header.Get("Content-Type"),
+			logId); logErr != nil {
+			log.Printf("[WARN] [%s] failed to log API Response Body: %s", logId, logErr)
 		}
 	}
+	return response, err
+}
+
+// isStreamContent checks whether the body is a stream, e.g. the object content, which should not be read for logging.
+func isStreamContent(header http.Header) bool {
+	return strings.Contains(header.Get("Content-Type"), "octet-stream")
 }
 
 // logRequest will log the HTTP Request details, then close the original.
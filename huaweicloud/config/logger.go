@@ -2,11 +2,11 @@ package config
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"net/http"
 	"sort"
 	"strings"
@@ -22,20 +22,33 @@ const MAXFieldLength int = 1024
 var logAtomicId int64
 var maxTimeout = 10 * time.Minute
 
+// logIDContextKey is the context key of the log ID, which is shared by the logs and the API trace of a request.
+type logIDContextKey struct{}
+
+func newLogID() string {
+	return fmt.Sprintf("%d-%d", time.Now().UnixMilli(), atomic.AddInt64(&logAtomicId, 1))
+}
+
+// withLogID returns a shallow copy of the request with the log ID stored in its context.
+func withLogID(request *http.Request, logId string) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), logIDContextKey{}, logId))
+}
+
+// logIDFromContext returns the log ID stored in the context, or an empty string if not found.
+func logIDFromContext(ctx context.Context) string {
+	logId, _ := ctx.Value(logIDContextKey{}).(string)
+	return logId
+}
+
 // LogRoundTripper satisfies the http.RoundTripper interface and is used to
 // customize the default http client RoundTripper to allow for logging.
 type LogRoundTripper struct {
 	Rt         http.RoundTripper
 	MaxRetries int
-}
-
-func retryTimeout(count int) time.Duration {
-	seconds := math.Pow(2, float64(count))
-	timeout := time.Duration(seconds) * time.Second
-	if timeout > maxTimeout { // won't wait more than maxTimeout
-		timeout = maxTimeout
-	}
-	return timeout
+	// MaxBackoff is the maximum waiting time between the retries, defaults to 10 minutes
+	MaxBackoff time.Duration
+	// RetryableErrorCodes is the additional error codes in the response body which should be retried
+	RetryableErrorCodes []string
 }
 
 // RoundTrip performs a round-trip HTTP request and logs relevant information about it.
@@ -43,11 +56,16 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 	var err error
 	var response *http.Response
 	var bs bytes.Buffer
+	var retries int
 
-	atomicId := atomic.AddInt64(&logAtomicId, 1)
-	logId := fmt.Sprintf("%d-%d", time.Now().UnixMilli(), atomicId)
+	logId := newLogID()
+	begin := time.Now()
 
 	defer func() {
+		if tracer, _ := GetAPITracer(); tracer != nil {
+			tracer.Record(newAPITraceRecord(traceSDKGolangsdk, logId, request, response, err, begin, retries))
+		}
+
 		// logging the API request and response
 		var logErr error
 		if request != nil {
@@ -86,28 +104,42 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 
 	// executes a single HTTP transaction
 	response, err = lrt.Rt.RoundTrip(request)
-	if response == nil {
-		errMessage := err.Error()
-		if strings.Contains(errMessage, "no such host") {
-			return nil, err
+
+	for retry := 1; ; retry++ {
+		shouldRetry, reason := lrt.shouldRetry(request, response, err)
+		if !shouldRetry {
+			break
 		}
-	}
 
-	// Retrying connection
-	retry := 1
-	for response == nil {
 		if retry > lrt.MaxRetries {
-			log.Printf("[DEBUG] [%s] connection error, retries exhausted. Aborting", logId)
-			err = fmt.Errorf("connection error, retries exhausted. Aborting. Last error was: %s", err)
-			return nil, err
+			log.Printf("[DEBUG] [%s] %s, retries exhausted. Aborting", logId, reason)
+			if response == nil {
+				err = fmt.Errorf("connection error, retries exhausted. Aborting. Last error was: %s", err)
+			}
+			return response, err
 		}
 
-		log.Printf("[DEBUG] [%s] connection error, retry number %d: %s", logId, retry, err)
+		retries = retry
+		timeout := lrt.retryTimeout(retry, response)
+		log.Printf("[DEBUG] [%s] %s, retry number %d after %s", logId, reason, retry, timeout)
 
-		//lintignore:R018
-		time.Sleep(retryTimeout(retry))
+		if response != nil && response.Body != nil {
+			// drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(timeout):
+		}
+
+		// rewind the request body which was consumed by the last transaction
+		if request.Body != nil {
+			request.Body = io.NopCloser(bytes.NewReader(bs.Bytes()))
+		}
 		response, err = lrt.Rt.RoundTrip(request)
-		retry++
 	}
 
 	return response, err
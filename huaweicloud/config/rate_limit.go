@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/ratelimit"
+)
+
+// rateLimitRoundTripper satisfies the http.RoundTripper interface and is used to limit the request rate of each
+// service before sending the requests.
+type rateLimitRoundTripper struct {
+	Rt     http.RoundTripper
+	Config *Config
+}
+
+// RoundTrip waits for the rate limiter of the service, then performs a round-trip HTTP request.
+func (rrt *rateLimitRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := rrt.Config.waitRateLimit(request); err != nil {
+		return nil, err
+	}
+	return rrt.Rt.RoundTrip(request)
+}
+
+// initRateLimiters builds the rate limiters of services, the limiters are keyed by the service catalog name,
+// which is parsed from the endpoint host.
+func (c *Config) initRateLimiters() error {
+	limits := make(map[string]float64, len(c.RateLimits))
+	for srv, limit := range c.RateLimits {
+		catalog, ok := allServiceCatalog[srv]
+		if !ok {
+			return fmt.Errorf("the service %q in rate_limits is invalid or not supported", srv)
+		}
+
+		// the services with multiple versions share the same limiter, the strictest limit will be used
+		if exist, ok := limits[catalog.Name]; !ok || float64(limit) < exist {
+			limits[catalog.Name] = float64(limit)
+		}
+	}
+
+	// the host of customizing endpoints may not start with the service catalog name
+	c.endpointServices = make(map[string]string)
+	for srv, endpoint := range c.Endpoints {
+		catalog, ok := allServiceCatalog[srv]
+		if !ok {
+			continue
+		}
+		if parsed, err := url.Parse(endpoint); err == nil && parsed.Host != "" {
+			c.endpointServices[parsed.Host] = catalog.Name
+		}
+	}
+
+	c.rateLimiters = ratelimit.NewLimiterKV(limits, float64(c.DefaultRateLimit))
+	return nil
+}
+
+// waitRateLimit blocks until the rate limiter of the service allows the request.
+func (c *Config) waitRateLimit(request *http.Request) error {
+	if c == nil || c.rateLimiters == nil || request.URL == nil {
+		return nil
+	}
+
+	service, ok := c.endpointServices[request.URL.Host]
+	if !ok {
+		// the bucket name is skipped for the OBS endpoint in the virtual-hosted style
+		service, _ = parseServiceAndRegion(request.URL.Hostname())
+	}
+	return c.rateLimiters.Wait(request.Context(), service)
+}
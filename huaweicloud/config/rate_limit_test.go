@@ -0,0 +1,41 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+type noopRoundTripper struct{}
+
+func (noopRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestRateLimitSharedByBuckets(t *testing.T) {
+	conf := &Config{DefaultRateLimit: 1}
+	th.AssertNoErr(t, conf.initRateLimiters())
+
+	client := http.Client{
+		Transport: &rateLimitRoundTripper{Rt: noopRoundTripper{}, Config: conf},
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "https://bucket-a.obs.cn-north-4.myhuaweicloud.com/", nil)
+	th.AssertNoErr(t, err)
+	response, err := client.Do(request)
+	th.AssertNoErr(t, err)
+	response.Body.Close()
+
+	// the token of the OBS limiter is consumed by the first bucket, so the request of the other bucket must wait
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	otherBucket := "https://bucket-b.obs.cn-north-4.myhuaweicloud.com/"
+	request, err = http.NewRequestWithContext(ctx, http.MethodGet, otherBucket, nil)
+	th.AssertNoErr(t, err)
+	_, err = client.Do(request)
+	if err == nil {
+		t.Fatal("expected the request to be aborted by the rate limiter")
+	}
+}
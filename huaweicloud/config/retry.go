@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// defaultRetryableErrorCodes is the error codes which mean the request is throttled and rejected before processing.
+var defaultRetryableErrorCodes = []string{
+	"APIGW.0308", // the request is throttled by API Gateway
+	"Ecs.0069",   // the ECS service is busy and the request is not processed
+}
+
+// retryableStatusCodes is the status codes of the transient errors. The server may have processed the request,
+// so only the idempotent requests can be retried.
+var retryableStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// isIdempotentMethod checks whether the request can be sent repeatedly without side effects.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isDialError checks whether the error occurs before the request is sent, e.g. connection refused.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldRetry checks whether the request should be retried, and returns the reason of retrying.
+// The request is retried in the following cases:
+//   - connection errors for the idempotent requests, and dial errors for all requests;
+//   - HTTP 429 or the retryable error codes for all requests, as they are rejected before processing;
+//   - HTTP 502, 503 and 504 for the idempotent requests.
+func (lrt *LogRoundTripper) shouldRetry(request *http.Request, response *http.Response, err error) (bool, string) {
+	if response == nil {
+		if err == nil || strings.Contains(err.Error(), "no such host") {
+			return false, ""
+		}
+		if isIdempotentMethod(request.Method) || isDialError(err) {
+			return true, "connection error: " + err.Error()
+		}
+		return false, ""
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		return true, "too many requests"
+	}
+
+	if response.StatusCode >= 400 {
+		if code := parseErrorCode(response); code != "" && (utils.StrSliceContains(defaultRetryableErrorCodes, code) ||
+			utils.StrSliceContains(lrt.RetryableErrorCodes, code)) {
+			return true, "retryable error code " + code
+		}
+	}
+
+	for _, statusCode := range retryableStatusCodes {
+		if response.StatusCode == statusCode && isIdempotentMethod(request.Method) {
+			return true, "status code " + strconv.Itoa(statusCode)
+		}
+	}
+	return false, ""
+}
+
+// parseErrorCode reads the error code from the JSON response body, and the body will be restored for the next reading.
+func parseErrorCode(response *http.Response) string {
+	if response.Body == nil || !strings.HasPrefix(response.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ""
+	}
+
+	for _, expression := range []string{"error_code", "errorCode", "code", "error.code", "error.error_code"} {
+		if code, ok := utils.PathSearch(expression, data, nil).(string); ok && code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// parseRetryAfter returns the waiting time specified by the Retry-After header, which can be the seconds or a date.
+func parseRetryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// retryTimeout returns the waiting time before the next retry. The Retry-After header is honoured if it is specified,
+// otherwise a full-jitter exponential backoff is used, and it won't wait more than the max backoff.
+func (lrt *LogRoundTripper) retryTimeout(count int, response *http.Response) time.Duration {
+	maxBackoff := lrt.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = maxTimeout
+	}
+
+	if wait, ok := parseRetryAfter(response); ok {
+		if wait > maxBackoff {
+			return maxBackoff
+		}
+		return wait
+	}
+
+	seconds := math.Pow(2, float64(count))
+	backoff := time.Duration(seconds) * time.Second
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	//nolint:gosec
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func testRetryServer(t *testing.T, method string, failures int32, failFunc func(w http.ResponseWriter)) int32 {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			failFunc(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := http.Client{
+		Transport: &LogRoundTripper{
+			Rt:         http.DefaultTransport,
+			MaxRetries: 3,
+			MaxBackoff: 10 * time.Millisecond,
+		},
+	}
+
+	request, err := http.NewRequest(method, server.URL, strings.NewReader(`{"name": "test"}`))
+	th.AssertNoErr(t, err)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	th.AssertNoErr(t, err)
+	response.Body.Close()
+
+	return atomic.LoadInt32(&requests)
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	requests := testRetryServer(t, http.MethodPost, 2, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	th.AssertEquals(t, int32(3), requests)
+}
+
+func TestRetryErrorCode(t *testing.T) {
+	requests := testRetryServer(t, http.MethodPost, 1, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error_code": "APIGW.0308", "error_msg": "The request is throttled."}`))
+	})
+	th.AssertEquals(t, int32(2), requests)
+}
+
+func TestRetryBusyErrorCode(t *testing.T) {
+	requests := testRetryServer(t, http.MethodPost, 1, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": "Ecs.0069", "message": "The system is busy."}}`))
+	})
+	th.AssertEquals(t, int32(2), requests)
+}
+
+func TestRetryServiceUnavailable(t *testing.T) {
+	unavailable := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	// the idempotent requests are retried
+	th.AssertEquals(t, int32(2), testRetryServer(t, http.MethodGet, 1, unavailable))
+	// the non-idempotent requests are not retried as the server may have processed them
+	th.AssertEquals(t, int32(1), testRetryServer(t, http.MethodPost, 1, unavailable))
+}
+
+func TestRetryTimeout(t *testing.T) {
+	lrt := &LogRoundTripper{MaxBackoff: 5 * time.Second}
+
+	response := &http.Response{Header: http.Header{}}
+	response.Header.Set("Retry-After", "3")
+	th.AssertEquals(t, 3*time.Second, lrt.retryTimeout(1, response))
+
+	response.Header.Set("Retry-After", "120")
+	th.AssertEquals(t, 5*time.Second, lrt.retryTimeout(1, response))
+
+	for i := 1; i < 10; i++ {
+		if timeout := lrt.retryTimeout(i, nil); timeout < 0 || timeout > 5*time.Second {
+			t.Fatalf("the retry timeout %s is out of range", timeout)
+		}
+	}
+}
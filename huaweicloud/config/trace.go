@@ -0,0 +1,337 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/iampolicy"
+)
+
+const (
+	// apiTraceFileEnv is the file to write the API trace, one JSON record per API call
+	apiTraceFileEnv = "HW_API_TRACE_FILE"
+	// iamPolicyFileEnv is the file to write the custom IAM policy derived from the API calls when the provider shuts down
+	iamPolicyFileEnv = "HW_IAM_POLICY_FILE"
+
+	traceSDKGolangsdk = "golangsdk"
+	traceSDKHcV3      = "huaweicloud-sdk-go-v3"
+)
+
+// APITraceRecord is the record of an API call written to the trace file.
+type APITraceRecord struct {
+	Time    string `json:"time"`
+	LogID   string `json:"log_id"`
+	SDK     string `json:"sdk"`
+	Service string `json:"service"`
+	Region  string `json:"region,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Status  int    `json:"status,omitempty"`
+	// LatencyMs is the time used by the call in milliseconds, including the waiting time of the retries
+	LatencyMs int64  `json:"latency_ms"`
+	Retries   int    `json:"retries"`
+	RequestID string `json:"request_id,omitempty"`
+	// Resource is the type of the resource or data source which makes the call, and ResourceID is the ID of it.
+	// The resource name in the configuration is never passed to the provider, so it can't be traced.
+	Resource   string `json:"resource,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// APITraceSummary is the statistics of the API calls of a service, it is written when the provider shuts down.
+type APITraceSummary struct {
+	Calls          int   `json:"calls"`
+	Errors         int   `json:"errors"`
+	Retries        int   `json:"retries"`
+	TotalLatencyMs int64 `json:"total_latency_ms"`
+	AvgLatencyMs   int64 `json:"avg_latency_ms"`
+	MaxLatencyMs   int64 `json:"max_latency_ms"`
+}
+
+// APITracer writes the API calls made by both golangsdk and huaweicloud-sdk-go-v3 to the trace file, and derives the
+// IAM policy from them if the policy file is configured.
+type APITracer struct {
+	lock    sync.Mutex
+	closed  bool
+	file    *os.File
+	summary map[string]*APITraceSummary
+	// policy collects the actions of the API calls, which are written to policyPath
+	policy     *iampolicy.Generator
+	policyPath string
+	// operations is the resource operations in progress, which are used to find the resource of an API call
+	operations map[*traceOperation]bool
+}
+
+type traceOperation struct {
+	resourceType string
+	id           string
+}
+
+var (
+	apiTracer     *APITracer
+	apiTracerErr  error
+	apiTracerOnce sync.Once
+)
+
+// GetAPITracer returns the API tracer configured by HW_API_TRACE_FILE and HW_IAM_POLICY_FILE, nil will be returned if
+// neither is configured.
+func GetAPITracer() (*APITracer, error) {
+	apiTracerOnce.Do(func() {
+		if !APITraceEnabled() {
+			return
+		}
+
+		apiTracer, apiTracerErr = newAPITracer(os.Getenv(apiTraceFileEnv))
+		if apiTracerErr == nil && os.Getenv(iamPolicyFileEnv) != "" {
+			apiTracer.policy = iampolicy.NewGenerator(nil)
+			apiTracer.policyPath = os.Getenv(iamPolicyFileEnv)
+		}
+	})
+	return apiTracer, apiTracerErr
+}
+
+// newAPITracer returns the API tracer which appends the records to the file, so that the calls of multiple provider
+// instances can be written to the same file. The records are not written if the path is empty.
+func newAPITracer(path string) (*APITracer, error) {
+	tracer := APITracer{
+		summary:    make(map[string]*APITraceSummary),
+		operations: make(map[*traceOperation]bool),
+	}
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening the API trace file %s: %s", path, err)
+		}
+		tracer.file = file
+	}
+	return &tracer, nil
+}
+
+// APITraceEnabled returns whether the API trace is configured by HW_API_TRACE_FILE or HW_IAM_POLICY_FILE.
+func APITraceEnabled() bool {
+	return os.Getenv(apiTraceFileEnv) != "" || os.Getenv(iamPolicyFileEnv) != ""
+}
+
+// TraceOperation marks the beginning of an operation of the resource, the API calls made before the returned function
+// is called are traced with the resource. The ID is empty if the resource is being created.
+func TraceOperation(resourceType, id string) func() {
+	tracer, _ := GetAPITracer()
+	if tracer == nil {
+		return func() {}
+	}
+	return tracer.StartOperation(resourceType, id)
+}
+
+// StartOperation marks the beginning of an operation of the resource, the returned function marks the end of it.
+func (t *APITracer) StartOperation(resourceType, id string) func() {
+	op := &traceOperation{resourceType: resourceType, id: id}
+	t.lock.Lock()
+	t.operations[op] = true
+	t.lock.Unlock()
+
+	return func() {
+		t.lock.Lock()
+		delete(t.operations, op)
+		t.lock.Unlock()
+	}
+}
+
+// CloseAPITrace writes the summary of the API calls and closes the trace file if the API trace is enabled.
+func CloseAPITrace() {
+	if tracer, _ := GetAPITracer(); tracer != nil {
+		tracer.Close()
+	}
+}
+
+// Close writes the summary of the API calls grouped by services and closes the trace file, the records after that
+// are dropped. The IAM policy derived from the API calls is also written if it's configured.
+func (t *APITracer) Close() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closed {
+		return
+	}
+	t.closed = true
+
+	if t.policy != nil {
+		t.writePolicy()
+	}
+	if t.file == nil {
+		return
+	}
+	for _, s := range t.summary {
+		s.AvgLatencyMs = s.TotalLatencyMs / int64(s.Calls)
+	}
+	t.write(map[string]interface{}{
+		"time":    time.Now().UTC().Format(time.RFC3339Nano),
+		"summary": t.summary,
+	})
+	if err := t.file.Close(); err != nil {
+		log.Printf("[WARN] failed to close the API trace file: %s", err)
+	}
+	t.file = nil
+}
+
+// writePolicy writes the IAM policy document. The actions which are not vetted are derived from the paths of the API
+// calls and logged as warnings, they must be reviewed before granting the policy.
+func (t *APITracer) writePolicy() {
+	for _, m := range t.policy.Unvetted() {
+		log.Printf("[WARN] the IAM action of %s %s of %s is not vetted, %s is derived from the path and must be reviewed",
+			m.Method, m.Path, m.Service, m.Action)
+	}
+	data, err := iampolicy.MarshalPolicies(t.policy.Policies())
+	if err != nil {
+		log.Printf("[WARN] failed to marshal the IAM policy: %s", err)
+		return
+	}
+	if err := os.WriteFile(t.policyPath, append(data, '\n'), 0600); err != nil {
+		log.Printf("[WARN] failed to write the IAM policy file %s: %s", t.policyPath, err)
+	}
+}
+
+// Record writes the record of an API call to the trace file, the resource is filled if it's known.
+func (t *APITracer) Record(record *APITraceRecord) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closed {
+		return
+	}
+	if t.policy != nil {
+		t.policy.AddCall(record.Service, record.Method, record.Path)
+	}
+	if t.file == nil {
+		return
+	}
+	if record.Resource == "" {
+		if op := t.findOperation(record.Path); op != nil {
+			record.Resource = op.resourceType
+			record.ResourceID = op.id
+		}
+	}
+
+	s, ok := t.summary[record.Service]
+	if !ok {
+		s = &APITraceSummary{}
+		t.summary[record.Service] = s
+	}
+	s.Calls++
+	s.Retries += record.Retries
+	s.TotalLatencyMs += record.LatencyMs
+	if record.LatencyMs > s.MaxLatencyMs {
+		s.MaxLatencyMs = record.LatencyMs
+	}
+	if record.Error != "" || record.Status >= http.StatusBadRequest {
+		s.Errors++
+	}
+
+	t.write(record)
+}
+
+// findOperation returns the operation whose resource ID is in the path, or the only operation in progress.
+func (t *APITracer) findOperation(path string) *traceOperation {
+	var found []*traceOperation
+	for op := range t.operations {
+		if op.id != "" && strings.Contains(path, op.id) {
+			found = append(found, op)
+		}
+	}
+	if len(found) == 0 && len(t.operations) == 1 {
+		for op := range t.operations {
+			found = append(found, op)
+		}
+	}
+
+	if len(found) != 1 {
+		return nil
+	}
+	return found[0]
+}
+
+func (t *APITracer) write(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[WARN] failed to marshal the API trace: %s", err)
+		return
+	}
+	if _, err := t.file.Write(append(data, '\n')); err != nil {
+		log.Printf("[WARN] failed to write the API trace: %s", err)
+	}
+}
+
+// newAPITraceRecord builds the record of the API call, the latency is calculated from the beginning time.
+func newAPITraceRecord(sdk, logId string, request *http.Request, response *http.Response, err error,
+	begin time.Time, retries int) *APITraceRecord {
+	service, region := parseServiceAndRegion(request.URL.Hostname())
+	record := APITraceRecord{
+		Time:      begin.UTC().Format(time.RFC3339Nano),
+		LogID:     logId,
+		SDK:       sdk,
+		Service:   service,
+		Region:    region,
+		Method:    request.Method,
+		Path:      request.URL.Path,
+		LatencyMs: time.Since(begin).Milliseconds(),
+		Retries:   retries,
+	}
+	if response != nil {
+		record.Status = response.StatusCode
+		record.RequestID = response.Header.Get("X-Request-Id")
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return &record
+}
+
+// parseServiceAndRegion parses the host in the format of {service}.{region}.{cloud} or {service}.{cloud}, the host
+// is used as the service name if it's an IP address, e.g. the custom endpoint in the test.
+func parseServiceAndRegion(host string) (service, region string) {
+	if net.ParseIP(host) != nil || host == "localhost" {
+		return host, ""
+	}
+
+	parts := strings.Split(host, ".")
+	// the bucket name is the first part of the OBS endpoint in the virtual-hosted style
+	for i, part := range parts {
+		if part == "obs" {
+			parts = parts[i:]
+			break
+		}
+	}
+
+	service = parts[0]
+	// the cloud contains at least two parts, e.g. myhuaweicloud.com
+	if len(parts) > 3 {
+		region = parts[1]
+	}
+	return
+}
+
+// traceRoundTripper records the API calls of huaweicloud-sdk-go-v3, the SDK retries the request by itself, so every
+// attempt is recorded without retries.
+type traceRoundTripper struct {
+	Rt     http.RoundTripper
+	Tracer *APITracer
+}
+
+func (t *traceRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// the log ID is assigned by hcLogRoundTripper, so that the record can be found in the logs
+	logId := logIDFromContext(request.Context())
+	if logId == "" {
+		logId = newLogID()
+	}
+	begin := time.Now()
+
+	response, err := t.Rt.RoundTrip(request)
+	t.Tracer.Record(newAPITraceRecord(traceSDKHcV3, logId, request, response, err, begin, 0))
+	return response, err
+}
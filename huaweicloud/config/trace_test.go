@@ -0,0 +1,165 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/iampolicy"
+)
+
+func TestAPITraceRecordsAndSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request-"+r.Method)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	tracer, err := newAPITracer(path)
+	th.AssertNoErr(t, err)
+	client := http.Client{Transport: &traceRoundTripper{Rt: http.DefaultTransport, Tracer: tracer}}
+
+	// the resource is found by the only operation in progress, or by the ID in the path
+	endCreate := tracer.StartOperation("huaweicloud_vpc", "")
+	endDelete := tracer.StartOperation("huaweicloud_vpc_subnet", "subnet-id")
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		request, err := http.NewRequest(method, server.URL+"/v1/project-id/subnets/subnet-id", nil)
+		th.AssertNoErr(t, err)
+		response, err := client.Do(request)
+		th.AssertNoErr(t, err)
+		_, _ = io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+	}
+	endDelete()
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/v1/project-id/vpcs", nil)
+	th.AssertNoErr(t, err)
+	response, err := client.Do(request)
+	th.AssertNoErr(t, err)
+	response.Body.Close()
+	endCreate()
+	tracer.Close()
+
+	file, err := os.Open(path)
+	th.AssertNoErr(t, err)
+	defer file.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		th.AssertNoErr(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	th.AssertEquals(t, 4, len(lines))
+
+	th.AssertEquals(t, "GET", lines[0]["method"])
+	th.AssertEquals(t, "/v1/project-id/subnets/subnet-id", lines[0]["path"])
+	th.AssertEquals(t, float64(200), lines[0]["status"])
+	th.AssertEquals(t, "request-GET", lines[0]["request_id"])
+	th.AssertEquals(t, "huaweicloud_vpc_subnet", lines[0]["resource"])
+	th.AssertEquals(t, "subnet-id", lines[0]["resource_id"])
+	th.AssertEquals(t, "127.0.0.1", lines[0]["service"])
+	th.AssertEquals(t, float64(404), lines[1]["status"])
+	th.AssertEquals(t, "huaweicloud_vpc", lines[2]["resource"])
+
+	summary := lines[3]["summary"].(map[string]interface{})["127.0.0.1"].(map[string]interface{})
+	th.AssertEquals(t, float64(3), summary["calls"])
+	th.AssertEquals(t, float64(1), summary["errors"])
+}
+
+func TestAPITraceParseServiceAndRegion(t *testing.T) {
+	cases := map[string][2]string{
+		"ecs.cn-north-4.myhuaweicloud.com":        {"ecs", "cn-north-4"},
+		"iam.myhuaweicloud.com":                   {"iam", ""},
+		"vpc.eu-west-101.myhuaweicloud.eu":        {"vpc", "eu-west-101"},
+		"bucket.obs.cn-north-4.myhuaweicloud.com": {"obs", "cn-north-4"},
+		"127.0.0.1": {"127.0.0.1", ""},
+		"bss-intl.ap-southeast-1.myhuaweicloud.com": {"bss-intl", "ap-southeast-1"},
+	}
+	for host, expected := range cases {
+		service, region := parseServiceAndRegion(host)
+		th.AssertEquals(t, expected[0], service)
+		th.AssertEquals(t, expected[1], region)
+	}
+}
+
+func TestAPITraceWritesIAMPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	tracer, err := newAPITracer("")
+	th.AssertNoErr(t, err)
+	tracer.policy = iampolicy.NewGenerator(nil)
+	tracer.policyPath = path
+
+	projectID := "0970dd7a1300f5672ff2c003c60ae115"
+	vpcID := "2cca1b0c-8a2e-4d43-ae29-5d8d1d2b6c4f"
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		tracer.Record(&APITraceRecord{Service: "vpc", Method: method, Path: "/v1/" + projectID + "/vpcs"})
+	}
+	tracer.Record(&APITraceRecord{Service: "vpc", Method: http.MethodGet, Path: "/v1/" + projectID + "/vpcs/" + vpcID})
+	tracer.Close()
+
+	data, err := os.ReadFile(path)
+	th.AssertNoErr(t, err)
+	var policy iampolicy.Policy
+	th.AssertNoErr(t, json.Unmarshal(data, &policy))
+	th.AssertEquals(t, "1.1", policy.Version)
+	th.AssertDeepEquals(t, []string{"vpc:vpcs:create", "vpc:vpcs:get", "vpc:vpcs:list"}, policy.Statement[0].Action)
+}
+
+func TestAPITraceSharesLogID(t *testing.T) {
+	var requestLogID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "vpc-id"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	tracer, err := newAPITracer(path)
+	th.AssertNoErr(t, err)
+	inner := roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		requestLogID = logIDFromContext(request.Context())
+		return http.DefaultTransport.RoundTrip(request)
+	})
+	client := http.Client{
+		Transport: &hcLogRoundTripper{Rt: &traceRoundTripper{Rt: inner, Tracer: tracer}},
+	}
+
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/v1/project-id/vpcs/vpc-id", nil)
+	th.AssertNoErr(t, err)
+	response, err := client.Do(request)
+	th.AssertNoErr(t, err)
+	// the response body is still readable after being logged
+	body, err := io.ReadAll(response.Body)
+	th.AssertNoErr(t, err)
+	response.Body.Close()
+	th.AssertEquals(t, `{"id": "vpc-id"}`, string(body))
+	tracer.Close()
+
+	content, err := os.ReadFile(path)
+	th.AssertNoErr(t, err)
+	var record map[string]interface{}
+	th.AssertNoErr(t, json.Unmarshal(bytes.SplitN(content, []byte("\n"), 2)[0], &record))
+	if requestLogID == "" {
+		t.Fatal("the log ID is not stored in the request context")
+	}
+	th.AssertEquals(t, requestLogID, record["log_id"])
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
@@ -1,13 +1,26 @@
 package encryption
 
 import (
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"math/big"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/internal/vault/pgpkeys"
 )
 
+// passwordCharsets are the character types of the generated passwords, the special characters are accepted by the
+// RDS, DDS and DCS instances.
+var passwordCharsets = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnopqrstuvwxyz",
+	"23456789",
+	"!@#^*-_=+?",
+}
+
 // RetrieveGPGKey returns the PGP key specified as the pgpKey parameter, or queries
 // the public key from the keybase service if the parameter is a keybase username
 // prefixed with the phrase "keybase:"
@@ -37,3 +50,118 @@ func EncryptValue(encryptionKey, value, description string) (string, string, err
 
 	return fingerprints[0], base64.StdEncoding.EncodeToString(encryptedValue[0]), nil
 }
+
+// EncryptToState encrypts the value with the PGP key specified by pgp_key, and saves the encrypted value to the
+// encryptedKey and the fingerprint to key_fingerprint. Both are cleared if pgp_key is not specified.
+func EncryptToState(d *schema.ResourceData, encryptedKey, value, description string) error {
+	pgpKey, ok := d.GetOk("pgp_key")
+	if !ok {
+		if err := d.Set(encryptedKey, nil); err != nil {
+			return err
+		}
+		return d.Set("key_fingerprint", nil)
+	}
+
+	encryptionKey, err := RetrieveGPGKey(pgpKey.(string))
+	if err != nil {
+		return err
+	}
+	fingerprint, encrypted, err := EncryptValue(encryptionKey, value, description)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Set(encryptedKey, encrypted); err != nil {
+		return err
+	}
+	return d.Set("key_fingerprint", fingerprint)
+}
+
+// SetSensitiveValue saves the value to the key, or saves the encrypted value to encrypted_<key> if pgp_key is
+// specified, so that the raw value is not saved in the state.
+func SetSensitiveValue(d *schema.ResourceData, key, value, description string) error {
+	if _, ok := d.GetOk("pgp_key"); !ok {
+		return d.Set(key, value)
+	}
+
+	if err := EncryptToState(d, "encrypted_"+key, value, description); err != nil {
+		return err
+	}
+	return d.Set(key, nil)
+}
+
+// GetPassword returns the password specified by the key. If it's not specified and pgp_key is specified, a random
+// password is generated, which is only saved encrypted in the state.
+func GetPassword(d *schema.ResourceData, key string) (string, error) {
+	if password := d.Get(key).(string); password != "" {
+		return password, nil
+	}
+	if _, ok := d.GetOk("pgp_key"); !ok {
+		return "", nil
+	}
+	return GeneratePassword(16)
+}
+
+// GetUpdatedPassword returns the password specified by the key when it or pgp_key is changed, and whether the password
+// of the instance needs to be reset. A random password is generated and reset if pgp_key is changed and the password
+// is not specified, because the generated one can't be encrypted with the new key.
+func GetUpdatedPassword(d *schema.ResourceData, key string) (password string, reset bool, err error) {
+	if !d.HasChanges(key, "pgp_key") {
+		return "", false, nil
+	}
+
+	if d.HasChange(key) {
+		password, err = GetPassword(d, key)
+		return password, password != "", err
+	}
+	if password = d.Get(key).(string); password != "" {
+		return password, false, nil
+	}
+	if _, ok := d.GetOk("pgp_key"); !ok {
+		return "", false, nil
+	}
+	password, err = GeneratePassword(16)
+	return password, err == nil, err
+}
+
+// GeneratePassword returns a random password of the length, which contains uppercase and lowercase letters, digits
+// and special characters.
+func GeneratePassword(length int) (string, error) {
+	if length < len(passwordCharsets) {
+		return "", fmt.Errorf("the password length must be at least %d", len(passwordCharsets))
+	}
+
+	password := make([]byte, length)
+	all := strings.Join(passwordCharsets, "")
+	for i := range password {
+		charset := all
+		// each character type appears at least once
+		if i < len(passwordCharsets) {
+			charset = passwordCharsets[i]
+		}
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+
+	// shuffle the password so the character types are not in a fixed order
+	for i := len(password) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
@@ -0,0 +1,145 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSensitiveValue(t *testing.T) {
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	assert.NoError(t, err)
+	// the identities are self-signed by SerializePrivate
+	assert.NoError(t, entity.SerializePrivate(io.Discard, nil))
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, entity.Serialize(buf))
+	pgpKey := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	resourceSchema := map[string]*schema.Schema{
+		"pgp_key":         {Type: schema.TypeString, Optional: true},
+		"token":           {Type: schema.TypeString, Computed: true},
+		"encrypted_token": {Type: schema.TypeString, Computed: true},
+		"key_fingerprint": {Type: schema.TypeString, Computed: true},
+	}
+
+	// the raw value is saved without pgp_key
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+	assert.NoError(t, SetSensitiveValue(d, "token", "secret", "token"))
+	assert.Equal(t, "secret", d.Get("token"))
+	assert.Equal(t, "", d.Get("encrypted_token"))
+
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"pgp_key": pgpKey})
+	assert.NoError(t, SetSensitiveValue(d, "token", "secret", "token"))
+	assert.Equal(t, "", d.Get("token"))
+	assert.NotEmpty(t, d.Get("key_fingerprint"))
+
+	encrypted, err := base64.StdEncoding.DecodeString(d.Get("encrypted_token").(string))
+	assert.NoError(t, err)
+	md, err := openpgp.ReadMessage(bytes.NewReader(encrypted), openpgp.EntityList{entity}, nil, nil)
+	assert.NoError(t, err)
+	plaintext, err := io.ReadAll(md.UnverifiedBody)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+}
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := GeneratePassword(16)
+	assert.NoError(t, err)
+	assert.Len(t, password, 16)
+	for _, charset := range passwordCharsets {
+		assert.True(t, strings.ContainsAny(password, charset), "%s has no character of %s", password, charset)
+	}
+
+	another, err := GeneratePassword(16)
+	assert.NoError(t, err)
+	assert.NotEqual(t, password, another)
+
+	_, err = GeneratePassword(3)
+	assert.Error(t, err)
+}
+
+func TestGetPassword(t *testing.T) {
+	resourceSchema := map[string]*schema.Schema{
+		"pgp_key":  {Type: schema.TypeString, Optional: true},
+		"password": {Type: schema.TypeString, Optional: true},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"password": "Test@123"})
+	password, err := GetPassword(d, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "Test@123", password)
+
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+	password, err = GetPassword(d, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "", password)
+
+	d = schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{"pgp_key": "keybase:test"})
+	password, err = GetPassword(d, "password")
+	assert.NoError(t, err)
+	assert.Len(t, password, 16)
+}
+
+func TestGetUpdatedPassword(t *testing.T) {
+	var password string
+	var reset bool
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"pgp_key":  {Type: schema.TypeString, Optional: true},
+			"password": {Type: schema.TypeString, Optional: true},
+			"name":     {Type: schema.TypeString, Optional: true},
+		},
+		Update: func(d *schema.ResourceData, _ interface{}) (err error) {
+			password, reset, err = GetUpdatedPassword(d, "password")
+			return err
+		},
+	}
+
+	cases := []struct {
+		name          string
+		state, config map[string]interface{}
+		password      string
+		reset         bool
+	}{
+		{"nothing changed", map[string]interface{}{"password": "Test@123", "name": "a"},
+			map[string]interface{}{"password": "Test@123", "name": "b"}, "", false},
+		{"password changed", map[string]interface{}{"password": "Test@123"},
+			map[string]interface{}{"password": "Test@456"}, "Test@456", true},
+		{"pgp_key changed with password", map[string]interface{}{"password": "Test@123", "pgp_key": "keybase:a"},
+			map[string]interface{}{"password": "Test@123", "pgp_key": "keybase:b"}, "Test@123", false},
+		{"pgp_key removed without password", map[string]interface{}{"pgp_key": "keybase:a"},
+			map[string]interface{}{}, "", false},
+	}
+
+	for _, c := range cases {
+		state := &terraform.InstanceState{ID: "id", Attributes: map[string]string{"id": "id"}}
+		for k, v := range c.state {
+			state.Attributes[k] = v.(string)
+		}
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(c.config), nil)
+		assert.NoError(t, err, c.name)
+		password, reset = "unset", false
+		_, diags := r.Apply(context.Background(), state, diff, nil)
+		assert.False(t, diags.HasError(), c.name)
+		assert.Equal(t, c.password, password, c.name)
+		assert.Equal(t, c.reset, reset, c.name)
+	}
+
+	// a new password is generated if pgp_key is changed and the password is not specified
+	state := &terraform.InstanceState{ID: "id", Attributes: map[string]string{"id": "id", "pgp_key": "keybase:a"}}
+	diff, err := r.Diff(context.Background(), state,
+		terraform.NewResourceConfigRaw(map[string]interface{}{"pgp_key": "keybase:b"}), nil)
+	assert.NoError(t, err)
+	_, diags := r.Apply(context.Background(), state, diff, nil)
+	assert.False(t, diags.HasError())
+	assert.Len(t, password, 16)
+	assert.True(t, reset)
+}
@@ -0,0 +1,199 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Operators are the operators supported by the filter block.
+var Operators = []string{
+	"=", "!=", ">", ">=", "<", "<=", "in", "notIn",
+	"contains", "strictContains", "startsWith", "endsWith",
+	"has", "hasContains", "regex", "cidrContains", "hasTag",
+}
+
+// Block is a filter block of the plural data sources, the item is kept if the value at the path matches any of the
+// values, except that "!=" and "notIn" require none of the values is matched.
+type Block struct {
+	Path     string
+	Operator string
+	Values   []string
+}
+
+// Schema returns the repeatable filter block, the blocks are AND-ed.
+func Schema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"path": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: `The path of the attribute in the items, e.g. name, tags or flavor.vcpus.`,
+				},
+				"operator": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "=",
+					ValidateFunc: validation.StringInSlice(Operators, false),
+					Description:  `The operator used to compare the attribute with the values.`,
+				},
+				"values": {
+					Type:        schema.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Description: `The values to compare with.`,
+				},
+			},
+		},
+		Description: `The generic filters applied to the items.`,
+	}
+}
+
+// ParseBlocks returns the filter blocks of the data source.
+func ParseBlocks(d *schema.ResourceData) []Block {
+	raw := d.Get("filter").([]interface{})
+	blocks := make([]Block, 0, len(raw))
+	for _, v := range raw {
+		m := v.(map[string]interface{})
+		values := make([]string, 0)
+		for _, val := range m["values"].([]interface{}) {
+			values = append(values, fmt.Sprintf("%v", val))
+		}
+		blocks = append(blocks, Block{
+			Path:     m["path"].(string),
+			Operator: m["operator"].(string),
+			Values:   values,
+		})
+	}
+	return blocks
+}
+
+// QueryPaths is the whitelist of a data source which maps its query arguments to the paths of the items, only the
+// filter blocks on these paths are sent to the API. The paths must be declared for the query parameters which match
+// the attributes exactly or return a superset of them, since the filter blocks are still applied to the response.
+type QueryPaths map[string]string
+
+// Value returns the argument if it's set, otherwise the value of the filter block on the whitelisted path of the
+// argument with the "=" operator and a single value.
+func (q QueryPaths) Value(d *schema.ResourceData, key string) string {
+	if v, ok := d.GetOk(key); ok {
+		return fmt.Sprintf("%v", v)
+	}
+
+	path, ok := q[key]
+	if !ok {
+		return ""
+	}
+	for _, b := range ParseBlocks(d) {
+		if b.Path == path && (b.Operator == "=" || b.Operator == "in") && len(b.Values) == 1 {
+			return b.Values[0]
+		}
+	}
+	return ""
+}
+
+// Apply filters the items with the filter blocks of the data source.
+func Apply[T any](d *schema.ResourceData, items []T) ([]T, error) {
+	return FilterItems(ParseBlocks(d), items)
+}
+
+// FilterItems returns the items matching all the filter blocks, the paths are relative to the items.
+func FilterItems[T any](blocks []Block, items []T) ([]T, error) {
+	if len(blocks) == 0 || len(items) == 0 {
+		return items, nil
+	}
+
+	// the items are wrapped with their indexes, so that the original items are returned
+	var data any
+	wrapped := make([]any, len(items))
+	for i, item := range items {
+		wrapped[i] = map[string]any{"index": i, "item": item}
+	}
+	data = wrapped
+
+	for _, b := range blocks {
+		filter := New().Data(data)
+		if err := b.where(filter); err != nil {
+			return nil, err
+		}
+		rst, err := filter.Get()
+		if err != nil {
+			return nil, err
+		}
+		data = rst
+	}
+
+	matched, _ := data.([]interface{})
+	result := make([]T, 0, len(matched))
+	for _, v := range matched {
+		index, _ := v.(map[string]interface{})["index"].(float64)
+		result = append(result, items[int(index)])
+	}
+	return result, nil
+}
+
+func (b *Block) where(filter *JsonFilter) error {
+	key := "item." + b.Path
+	switch b.Operator {
+	case "!=", "notIn":
+		// none of the values is matched
+		for _, v := range b.Values {
+			for _, val := range compareValues(v) {
+				filter.Where(key, "!=", val)
+			}
+		}
+		return nil
+	case ">", ">=", "<", "<=":
+		for i, v := range b.Values {
+			num, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("the values of the operator %s must be numbers, got %s", b.Operator, v)
+			}
+			whereOrOr(filter, i == 0, key, b.Operator, num)
+		}
+		return nil
+	}
+
+	operator := b.Operator
+	if operator == "in" {
+		operator = "="
+	}
+	first := true
+	for _, v := range b.Values {
+		values := []any{v}
+		if operator == "=" {
+			values = compareValues(v)
+		}
+		for _, val := range values {
+			whereOrOr(filter, first, key, operator, val)
+			first = false
+		}
+	}
+	return nil
+}
+
+func whereOrOr(filter *JsonFilter, first bool, key, operator string, val any) {
+	if first {
+		filter.Where(key, operator, val)
+		return
+	}
+	filter.OrWhere(key, operator, val)
+}
+
+// compareValues returns the string and its number or boolean form if any, since the types of the attributes are
+// unknown.
+func compareValues(v string) []any {
+	if num, err := strconv.ParseFloat(v, 64); err == nil {
+		return []any{v, num}
+	}
+	if b, err := strconv.ParseBool(v); err == nil {
+		return []any{v, b}
+	}
+	return []any{v}
+}
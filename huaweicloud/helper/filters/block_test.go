@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterItems(t *testing.T) {
+	items := []map[string]interface{}{
+		{
+			"name":   "vpc-web",
+			"cidr":   "192.168.0.0/16",
+			"vcpus":  2,
+			"tags":   map[string]string{"env": "prod", "owner": "web"},
+			"subnet": map[string]interface{}{"cidr": "192.168.1.0/24"},
+		},
+		{
+			"name":   "vpc-db",
+			"cidr":   "10.0.0.0/8",
+			"vcpus":  8,
+			"tags":   map[string]string{"env": "test"},
+			"subnet": map[string]interface{}{"cidr": "10.1.0.0/16"},
+		},
+		{
+			"name":  "default",
+			"cidr":  "172.16.0.0/12",
+			"vcpus": 4,
+			"tags":  []map[string]string{{"key": "env", "value": "prod"}},
+		},
+	}
+
+	names := func(blocks ...Block) []string {
+		rst, err := FilterItems(blocks, items)
+		assert.NoError(t, err)
+		names := make([]string, 0, len(rst))
+		for _, item := range rst {
+			names = append(names, item["name"].(string))
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"vpc-web", "vpc-db", "default"}, names())
+	assert.Equal(t, []string{"vpc-web", "default"}, names(Block{Path: "name", Operator: "=", Values: []string{"vpc-web", "default"}}))
+	assert.Equal(t, []string{"default"}, names(Block{Path: "name", Operator: "notIn", Values: []string{"vpc-web", "vpc-db"}}))
+	assert.Equal(t, []string{"vpc-web", "vpc-db"}, names(Block{Path: "name", Operator: "regex", Values: []string{"^vpc-"}}))
+	assert.Equal(t, []string{"vpc-db", "default"}, names(Block{Path: "vcpus", Operator: ">=", Values: []string{"4"}}))
+	assert.Equal(t, []string{"default"}, names(Block{Path: "vcpus", Operator: "=", Values: []string{"4"}}))
+	assert.Equal(t, []string{"vpc-db"}, names(Block{Path: "cidr", Operator: "cidrContains", Values: []string{"10.2.3.4"}}))
+	assert.Equal(t, []string{"vpc-web"}, names(Block{Path: "cidr", Operator: "cidrContains", Values: []string{"192.168.2.0/24"}}))
+	assert.Equal(t, []string{"vpc-web"}, names(Block{Path: "subnet.cidr", Operator: "cidrContains", Values: []string{"192.168.1.10"}}))
+	assert.Equal(t, []string{"vpc-web", "default"}, names(Block{Path: "tags", Operator: "hasTag", Values: []string{"env=prod"}}))
+	assert.Equal(t, []string{"vpc-web"}, names(Block{Path: "tags", Operator: "hasTag", Values: []string{"owner"}}))
+
+	// the blocks are AND-ed
+	assert.Equal(t, []string{"default"}, names(
+		Block{Path: "tags", Operator: "hasTag", Values: []string{"env=prod"}},
+		Block{Path: "vcpus", Operator: ">", Values: []string{"2"}},
+	))
+
+	_, err := FilterItems([]Block{{Path: "vcpus", Operator: ">", Values: []string{"two"}}}, items)
+	assert.Error(t, err)
+}
+
+func TestQueryPathsValue(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString, Optional: true},
+		"status": {Type: schema.TypeString, Optional: true},
+		"filter": Schema(),
+	}
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		"status": "ACTIVE",
+		"filter": []interface{}{
+			map[string]interface{}{"path": "name", "operator": "=", "values": []interface{}{"vpc-web"}},
+			map[string]interface{}{"path": "cidr", "operator": "=", "values": []interface{}{"10.0.0.0/8"}},
+		},
+	})
+
+	paths := QueryPaths{"name": "name", "status": "status"}
+	assert.Equal(t, "vpc-web", paths.Value(d, "name"))
+	assert.Equal(t, "ACTIVE", paths.Value(d, "status"))
+	// the filter on a path which is not whitelisted is only applied to the response
+	assert.Equal(t, "", paths.Value(d, "cidr"))
+}
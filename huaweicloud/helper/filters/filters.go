@@ -3,7 +3,9 @@ package filters
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/thedevsaddam/gojsonq"
@@ -13,6 +15,8 @@ type QueryCond struct {
 	Key      string
 	Operator string
 	Value    any
+	// Or is whether the condition is OR-ed with the previous conditions
+	Or bool
 }
 
 type JsonFilter struct {
@@ -30,7 +34,10 @@ func New() *JsonFilter {
 	return &JsonFilter{
 		query: gojsonq.New().
 			Macro("has", has).
-			Macro("hasContains", hasContain),
+			Macro("hasContains", hasContain).
+			Macro("regex", matchRegex).
+			Macro("cidrContains", cidrContains).
+			Macro("hasTag", hasTag),
 		queries: make([]QueryCond, 0),
 	}
 }
@@ -65,6 +72,20 @@ func (f *JsonFilter) Where(key, operator string, val any) *JsonFilter {
 	return f
 }
 
+// OrWhere adds a condition which is OR-ed with the previous conditions.
+func (f *JsonFilter) OrWhere(key, operator string, val any) *JsonFilter {
+	if val == nil {
+		return f
+	}
+	f.queries = append(f.queries, QueryCond{
+		Key:      key,
+		Operator: operator,
+		Value:    val,
+		Or:       true,
+	})
+	return f
+}
+
 func (f *JsonFilter) Get() (any, error) {
 	dt := reflect.TypeOf(f.jsonData)
 	if dt.Kind() == reflect.Slice {
@@ -81,7 +102,7 @@ func (f *JsonFilter) filterSlice() (any, error) {
 
 	query := f.query.JSONString(string(b))
 	for _, q := range f.queries {
-		query = query.Where(q.Key, q.Operator, q.Value)
+		query = where(query, q)
 	}
 	return query.Get(), nil
 }
@@ -99,7 +120,7 @@ func (f *JsonFilter) filterJson() (any, error) {
 	query := f.query.JSONString(string(b)).From(f.node)
 
 	for _, q := range f.queries {
-		query = query.Where(q.Key, q.Operator, q.Value)
+		query = where(query, q)
 	}
 
 	if f.node == "" {
@@ -115,6 +136,13 @@ func (f *JsonFilter) filterJson() (any, error) {
 	}
 }
 
+func where(query *gojsonq.JSONQ, q QueryCond) *gojsonq.JSONQ {
+	if q.Or {
+		return query.OrWhere(q.Key, q.Operator, q.Value)
+	}
+	return query.Where(q.Key, q.Operator, q.Value)
+}
+
 func putMap(keyPath string, mp map[string]any, val any) map[string]any {
 	keys := strings.Split(keyPath, ".")
 
@@ -201,3 +229,78 @@ func hasContain(x interface{}, y interface{}) (bool, error) {
 	}
 	return false, nil
 }
+
+// matchRegex reports whether x matches the regular expression y.
+func matchRegex(x interface{}, y interface{}) (bool, error) {
+	xv, ok := x.(string)
+	if !ok {
+		return false, fmt.Errorf("%v must be string", x)
+	}
+	re, err := regexp.Compile(fmt.Sprintf("%v", y))
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(xv), nil
+}
+
+// cidrContains reports whether the CIDR x, or any of the CIDRs if x is a list, contains the IP address or CIDR y.
+func cidrContains(x interface{}, y interface{}) (bool, error) {
+	cidrs := toStrSlice(x)
+	if cidrs == nil {
+		cidrs = []string{fmt.Sprintf("%v", x)}
+	}
+
+	target := fmt.Sprintf("%v", y)
+	ip, targetNet, err := net.ParseCIDR(target)
+	if err != nil {
+		if ip = net.ParseIP(target); ip == nil {
+			return false, fmt.Errorf("%s is not a valid IP address or CIDR", target)
+		}
+	}
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if !ipNet.Contains(ip) {
+			continue
+		}
+		if targetNet == nil {
+			return true, nil
+		}
+		ones, _ := ipNet.Mask.Size()
+		targetOnes, _ := targetNet.Mask.Size()
+		if targetOnes >= ones {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasTag reports whether the tags x have the tag y, which is in the format of key=value or key. The tags can be a map
+// or a list of objects with key and value.
+func hasTag(x interface{}, y interface{}) (bool, error) {
+	tags := make(map[string]string)
+	switch xv := x.(type) {
+	case map[string]interface{}:
+		for k, v := range xv {
+			tags[k] = fmt.Sprintf("%v", v)
+		}
+	case []interface{}:
+		for _, item := range xv {
+			if tag, ok := item.(map[string]interface{}); ok {
+				tags[fmt.Sprintf("%v", tag["key"])] = fmt.Sprintf("%v", tag["value"])
+			}
+		}
+	default:
+		return false, fmt.Errorf("%v must be tags", x)
+	}
+
+	key, value, withValue := strings.Cut(fmt.Sprintf("%v", y), "=")
+	v, ok := tags[key]
+	if !ok {
+		return false, nil
+	}
+	return !withValue || v == value, nil
+}
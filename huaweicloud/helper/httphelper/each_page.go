@@ -0,0 +1,135 @@
+package httphelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/pagination"
+)
+
+// PageHandler is called with each page of the response, the filters have been applied to the page.
+// Returning false stops the iteration.
+type PageHandler func(page *gjson.Result) (bool, error)
+
+// MaxItems limits the number of items read by EachPage, the items are counted by the data path of the pager after
+// filtering, 0 means no limit.
+func (c *HttpHelper) MaxItems(maxItems int) *HttpHelper {
+	c.maxItems = maxItems
+	return c
+}
+
+// EachPage requests the pages one by one and calls the handler with each page, instead of merging all the pages in
+// memory like Request. The iteration stops when the context is done, the handler returns false or the max items
+// are reached. The request without a pager is treated as a single page.
+func (c *HttpHelper) EachPage(ctx context.Context, handler PageHandler) error {
+	if c.result.Err != nil {
+		return c.result.Err
+	}
+	if c.method == "" {
+		return fmt.Errorf("`method` is empty, please specify the client through Client(method string)")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c.buildURL()
+	c.appendQueryParams()
+
+	if c.pager == nil {
+		c.client = clientWithContext(ctx, c.client)
+		c.requestNoPage()
+		if c.result.Err != nil {
+			return c.result.Err
+		}
+		var body any
+		if err := json.Unmarshal(c.responseBody, &body); err != nil {
+			return err
+		}
+		_, _, err := c.handlePage(body, 0, handler)
+		return err
+	}
+
+	count := 0
+	err := pagination.NewPager(clientWithContext(ctx, c.client), c.url, c.pager).
+		EachPage(func(page pagination.Page) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+
+			next, n, err := c.handlePage(page.GetBody(), count, handler)
+			count += n
+			return next, err
+		})
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// handlePage applies the filters and the max items to the page before calling the handler, it returns whether to
+// continue and the number of the items handled.
+func (c *HttpHelper) handlePage(body any, count int, handler PageHandler) (bool, int, error) {
+	data, err := c.applyFilters(body)
+	if err != nil {
+		return false, 0, err
+	}
+
+	n, reached := 0, false
+	if c.dataPath != "" {
+		n, reached = limitItems(data, c.dataPath, c.maxItems-count, c.maxItems > 0)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return false, n, err
+	}
+	page := gjson.ParseBytes(b)
+	next, err := handler(&page)
+	if reached {
+		log.Printf("[DEBUG] [EachPage] the max items (%d) are reached, stop query", c.maxItems)
+		next = false
+	}
+	return next, n, err
+}
+
+// limitItems truncates the items at the data path to the remaining number if the limit is enabled, it returns the
+// number of the items left and whether the limit is reached.
+func limitItems(data any, dataPath string, remaining int, limited bool) (int, bool) {
+	keys := strings.Split(dataPath, ".")
+	parent, ok := data.(map[string]any)
+	for i := 0; ok && i < len(keys)-1; i++ {
+		parent, ok = parent[keys[i]].(map[string]any)
+	}
+	if !ok {
+		return 0, false
+	}
+
+	key := keys[len(keys)-1]
+	items, ok := parent[key].([]any)
+	if !ok {
+		return 0, false
+	}
+	if !limited {
+		return len(items), false
+	}
+	if len(items) >= remaining {
+		parent[key] = items[:remaining]
+		return remaining, true
+	}
+	return len(items), false
+}
+
+// clientWithContext returns a copy of the client whose requests are bound to the context.
+func clientWithContext(ctx context.Context, client *golangsdk.ServiceClient) *golangsdk.ServiceClient {
+	providerClient := *client.ProviderClient
+	providerClient.Context = ctx
+	serviceClient := *client
+	serviceClient.ProviderClient = &providerClient
+	return &serviceClient
+}
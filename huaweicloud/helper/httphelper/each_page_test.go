@@ -0,0 +1,129 @@
+package httphelper
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/filters"
+)
+
+// newOffsetPageServer serves 10 users in pages by offset and limit, the age of the user i is 10+i.
+func newOffsetPageServer(requests *int32) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(requests, 1)
+		offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+
+		users := make([]map[string]any, 0)
+		for i := offset; i < offset+limit && i < 10; i++ {
+			users = append(users, map[string]any{"name": fmt.Sprintf("user%d", i), "age": 10 + i})
+		}
+		body, _ := bodyToBytes(map[string]any{"users": users})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+}
+
+//nolint:gosec
+func newPageHelper(server *httptest.Server) *HttpHelper {
+	client := server.Client()
+	client.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+
+	return New(&golangsdk.ServiceClient{
+		ProviderClient: &golangsdk.ProviderClient{HTTPClient: *client},
+		Endpoint:       server.URL,
+	}).Method("GET").URI("users")
+}
+
+func collectNames(names *[]string) PageHandler {
+	return func(page *gjson.Result) (bool, error) {
+		for _, u := range page.Get("users").Array() {
+			*names = append(*names, u.Get("name").String())
+		}
+		return true, nil
+	}
+}
+
+func TestEachPage(t *testing.T) {
+	var requests int32
+	server := newOffsetPageServer(&requests)
+	defer server.Close()
+
+	names := make([]string, 0)
+	err := newPageHelper(server).
+		OffsetPager("users", "offset", "limit", 3).
+		EachPage(context.Background(), collectNames(&names))
+	assert.NoError(t, err)
+	assert.Len(t, names, 10)
+	// 4 pages with the users and an empty page
+	assert.Equal(t, int32(5), requests)
+}
+
+func TestEachPageMaxItems(t *testing.T) {
+	var requests int32
+	server := newOffsetPageServer(&requests)
+	defer server.Close()
+
+	names := make([]string, 0)
+	err := newPageHelper(server).
+		OffsetPager("users", "offset", "limit", 3).
+		MaxItems(4).
+		EachPage(context.Background(), collectNames(&names))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user0", "user1", "user2", "user3"}, names)
+	assert.Equal(t, int32(2), requests)
+}
+
+func TestEachPageFilter(t *testing.T) {
+	var requests int32
+	server := newOffsetPageServer(&requests)
+	defer server.Close()
+
+	// the items are counted after filtering
+	names := make([]string, 0)
+	err := newPageHelper(server).
+		OffsetPager("users", "offset", "limit", 3).
+		Filter(filters.New().From("users").Where("age", ">", 14)).
+		MaxItems(2).
+		EachPage(context.Background(), collectNames(&names))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user5", "user6"}, names)
+	assert.Equal(t, int32(3), requests)
+}
+
+func TestEachPageStop(t *testing.T) {
+	var requests int32
+	server := newOffsetPageServer(&requests)
+	defer server.Close()
+
+	pages := 0
+	err := newPageHelper(server).
+		OffsetPager("users", "offset", "limit", 3).
+		EachPage(context.Background(), func(_ *gjson.Result) (bool, error) {
+			pages++
+			return false, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pages)
+	assert.Equal(t, int32(1), requests)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = newPageHelper(server).
+		OffsetPager("users", "offset", "limit", 3).
+		EachPage(ctx, collectNames(new([]string)))
+	assert.ErrorIs(t, err, context.Canceled)
+}
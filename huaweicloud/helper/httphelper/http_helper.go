@@ -30,6 +30,9 @@ type HttpHelper struct {
 	filters     []*filters.JsonFilter
 
 	pager func(r pagination.PageResult) pagination.Page
+	// dataPath is the path of the items in the pages, and maxItems is the limit of the items read by EachPage
+	dataPath string
+	maxItems int
 
 	responseBody []byte
 	result       golangsdk.Result
@@ -81,6 +84,7 @@ func (c *HttpHelper) OkCode(okCodes ...int) *HttpHelper {
 }
 
 func (c *HttpHelper) MarkerPager(dataPath, nextExp, markerKey string) *HttpHelper {
+	c.dataPath = dataPath
 	timestamp, _ := uuid.GenerateUUID()
 	c.pager = func(r pagination.PageResult) pagination.Page {
 		p := MarkerPager{
@@ -98,6 +102,7 @@ func (c *HttpHelper) MarkerPager(dataPath, nextExp, markerKey string) *HttpHelpe
 }
 
 func (c *HttpHelper) PageSizePager(dataPath, pageNumKey, perPageKey string, perPage int) *HttpHelper {
+	c.dataPath = dataPath
 	if perPage > 0 {
 		c.queryExt[perPageKey] = perPage
 	}
@@ -117,6 +122,7 @@ func (c *HttpHelper) PageSizePager(dataPath, pageNumKey, perPageKey string, perP
 }
 
 func (c *HttpHelper) LinkPager(dataPath, linkExp string) *HttpHelper {
+	c.dataPath = dataPath
 	timestamp, _ := uuid.GenerateUUID()
 	c.pager = func(r pagination.PageResult) pagination.Page {
 		return LinkPager{
@@ -131,6 +137,7 @@ func (c *HttpHelper) LinkPager(dataPath, linkExp string) *HttpHelper {
 }
 
 func (c *HttpHelper) OffsetPager(dataPath, offsetKey, limitKey string, defaultLimit int) *HttpHelper {
+	c.dataPath = dataPath
 	if defaultLimit > 0 {
 		c.queryExt[limitKey] = defaultLimit
 		c.queryExt[offsetKey] = 0
@@ -273,7 +280,12 @@ func (c *HttpHelper) requestNoPage() {
 	case "PATCH":
 		_, err = c.client.Patch(c.url, c.body, &c.result.Body, c.requestOpts)
 	case "DELETE":
-		_, err = c.client.DeleteWithBodyResp(c.url, c.body, &c.result.Body, c.requestOpts)
+		// the body is omitted if not specified, the same as ServiceClient.Delete, rather than being sent as "null"
+		var body any
+		if c.body != nil {
+			body = c.body
+		}
+		_, err = c.client.DeleteWithBodyResp(c.url, body, &c.result.Body, c.requestOpts)
 	}
 
 	c.result.Err = err
@@ -301,29 +313,41 @@ func (c *HttpHelper) doFilter() {
 		return
 	}
 
+	data, err := c.applyFilters(data)
+	if err != nil {
+		c.result.Err = err
+		return
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		c.result.Err = err
+		return
+	}
+	c.responseBody = b
+}
+
+func (c *HttpHelper) applyFilters(data any) (any, error) {
 	for _, filter := range c.filters {
 		query := filters.New().
 			Data(data).
 			From(filter.GetFrom())
 
 		for _, q := range filter.GetQueries() {
+			if q.Or {
+				query = query.OrWhere(q.Key, q.Operator, q.Value)
+				continue
+			}
 			query = query.Where(q.Key, q.Operator, q.Value)
 		}
 
 		r, err := query.Get()
 		if err != nil {
-			c.result.Err = err
-			return
+			return nil, err
 		}
 		data = r
 	}
-
-	b, err := json.Marshal(data)
-	if err != nil {
-		c.result.Err = err
-		return
-	}
-	c.responseBody = b
+	return data, nil
 }
 
 func marshalQueryParams(params map[string]any) string {
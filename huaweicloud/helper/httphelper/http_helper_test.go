@@ -448,6 +448,27 @@ func TestPostPutPatchDelete(t *testing.T) {
 	}
 }
 
+func TestDeleteBody(t *testing.T) {
+	var body string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		body = string(b)
+		_, _ = fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	// no body is sent if it's not specified
+	tc := &HttpTestCase{method: "DELETE"}
+	_, err := tc.newClient(server).Request().Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "", body)
+
+	tc = &HttpTestCase{method: "DELETE", body: map[string]any{"delete_publicip": true}}
+	_, err = tc.newClient(server).Request().Result()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"delete_publicip":true}`, strings.TrimSpace(body))
+}
+
 func TestMergeMaps(t *testing.T) {
 	map1 := map[string]interface{}{
 		"a": 1,
@@ -0,0 +1,56 @@
+package iampolicy
+
+// vettedActions are the IAM actions of the APIs checked against the permission documents of the services. The actions
+// of the other APIs are derived from the paths and must be reviewed before granting them.
+var vettedActions = []Template{
+	// ECS
+	{Service: "ecs", Method: "POST", Path: "/v1/{project_id}/cloudservers", Action: "ecs:cloudServers:createServers"},
+	{Service: "ecs", Method: "POST", Path: "/v1.1/{project_id}/cloudservers", Action: "ecs:cloudServers:createServers"},
+	{Service: "ecs", Method: "GET", Path: "/v1/{project_id}/cloudservers/detail", Action: "ecs:cloudServers:list"},
+	{Service: "ecs", Method: "GET", Path: "/v1/{project_id}/cloudservers/{server_id}", Action: "ecs:cloudServers:get"},
+	{Service: "ecs", Method: "POST", Path: "/v1/{project_id}/cloudservers/delete",
+		Action: "ecs:cloudServers:deleteServers"},
+	{Service: "ecs", Method: "POST", Path: "/v1.1/{project_id}/cloudservers/{server_id}/resize",
+		Action: "ecs:cloudServers:resize"},
+
+	// VPC
+	{Service: "vpc", Method: "POST", Path: "/v1/{project_id}/vpcs", Action: "vpc:vpcs:create"},
+	{Service: "vpc", Method: "GET", Path: "/v1/{project_id}/vpcs", Action: "vpc:vpcs:list"},
+	{Service: "vpc", Method: "GET", Path: "/v1/{project_id}/vpcs/{vpc_id}", Action: "vpc:vpcs:get"},
+	{Service: "vpc", Method: "PUT", Path: "/v1/{project_id}/vpcs/{vpc_id}", Action: "vpc:vpcs:update"},
+	{Service: "vpc", Method: "DELETE", Path: "/v1/{project_id}/vpcs/{vpc_id}", Action: "vpc:vpcs:delete"},
+	{Service: "vpc", Method: "POST", Path: "/v1/{project_id}/subnets", Action: "vpc:subnets:create"},
+	{Service: "vpc", Method: "GET", Path: "/v1/{project_id}/subnets", Action: "vpc:subnets:list"},
+	{Service: "vpc", Method: "GET", Path: "/v1/{project_id}/subnets/{subnet_id}", Action: "vpc:subnets:get"},
+	{Service: "vpc", Method: "PUT", Path: "/v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}",
+		Action: "vpc:subnets:update"},
+	{Service: "vpc", Method: "DELETE", Path: "/v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}",
+		Action: "vpc:subnets:delete"},
+
+	// CCE
+	{Service: "cce", Method: "POST", Path: "/api/v3/projects/{project_id}/clusters", Action: "cce:cluster:create"},
+	{Service: "cce", Method: "GET", Path: "/api/v3/projects/{project_id}/clusters", Action: "cce:cluster:list"},
+	{Service: "cce", Method: "GET", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}",
+		Action: "cce:cluster:get"},
+	{Service: "cce", Method: "PUT", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}",
+		Action: "cce:cluster:update"},
+	{Service: "cce", Method: "DELETE", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}",
+		Action: "cce:cluster:delete"},
+	{Service: "cce", Method: "POST", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools",
+		Action: "cce:nodepool:create"},
+	{Service: "cce", Method: "GET", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools",
+		Action: "cce:nodepool:list"},
+	{Service: "cce", Method: "GET", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools/{nodepool_id}",
+		Action: "cce:nodepool:get"},
+	{Service: "cce", Method: "PUT", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools/{nodepool_id}",
+		Action: "cce:nodepool:update"},
+	{Service: "cce", Method: "DELETE",
+		Path:   "/api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools/{nodepool_id}",
+		Action: "cce:nodepool:delete"},
+
+	// IAM
+	{Service: "iam", Method: "GET", Path: "/v3/projects", Action: "iam:projects:listProjects"},
+	{Service: "iam", Method: "POST", Path: "/v3/projects", Action: "iam:projects:createProject"},
+	{Service: "iam", Method: "GET", Path: "/v3/projects/{project_id}", Action: "iam:projects:getProject"},
+	{Service: "iam", Method: "PATCH", Path: "/v3/projects/{project_id}", Action: "iam:projects:updateProject"},
+}
@@ -0,0 +1,392 @@
+// Package iampolicy derives the least-privilege custom IAM policies from the API calls made by the provider.
+//
+// The API paths are matched with the templates declared by the resources (docs/api) if they are provided, otherwise
+// the IDs in the paths are detected by their formats. The actions of the APIs are looked up in a table checked against
+// the permission documents of the services, e.g. GET /api/v3/projects/{project_id}/clusters/{cluster_id} of CCE is
+// cce:cluster:get. The actions of the APIs which are not in the table are derived from the paths in the format of
+// service:resource:action, and are reported as unvetted since they may not be the real actions.
+package iampolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// PolicyVersion is the version of the custom policy document
+	PolicyVersion = "1.1"
+
+	// RoleTypeProject is the role type of the project-level services
+	RoleTypeProject = "XA"
+	// RoleTypeGlobal is the role type of the global services
+	RoleTypeGlobal = "AX"
+)
+
+var (
+	// globalServices are the services whose policies must be granted in a global role
+	globalServices = map[string]bool{
+		"iam": true, "obs": true, "bss": true, "cdn": true, "eps": true, "rms": true, "tms": true, "organizations": true,
+	}
+
+	// resourceNames are the names used by IAM for the collections which differ from the API paths
+	resourceNames = map[string]string{
+		"cloudservers":          "cloudServers",
+		"cloudvolumes":          "volumes",
+		"cloudimages":           "images",
+		"publicips":             "publicIps",
+		"os-availability-zone":  "availabilityZones",
+		"os-keypairs":           "keypairs",
+		"scaling_group":         "scalingGroups",
+		"scaling_configuration": "scalingConfigs",
+		"scaling_policy":        "scalingPolicies",
+	}
+
+	// verbs are the last segments of the paths which are the actions themselves, e.g. /cloudservers/delete
+	verbs = map[string]string{
+		"delete": "delete",
+		"detail": "list",
+		"resize": "resize",
+		"start":  "start",
+		"stop":   "stop",
+		"reboot": "reboot",
+		"extend": "extend",
+		"count":  "list",
+	}
+
+	versionRegexp = regexp.MustCompile(`^v\d+(\.\d+)?$`)
+	paramRegexp   = regexp.MustCompile(`^\{[^/]+\}$`)
+	idRegexp      = regexp.MustCompile(`^([0-9a-fA-F]{32}|[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}|\d+)$`)
+)
+
+// Template is an API path template declared by a resource, e.g. GET /v1/{project_id}/vpcs/{vpc_id} of VPC.
+type Template struct {
+	Service string
+	Method  string
+	Path    string
+	// Action is the IAM action of the API, it's derived from the path if empty
+	Action string
+
+	pattern *regexp.Regexp
+}
+
+// Policy is the custom policy document of huaweicloud_identity_role.
+type Policy struct {
+	Version   string      `json:"Version"`
+	Statement []Statement `json:"Statement"`
+}
+
+// Statement is a statement of the policy document.
+type Statement struct {
+	Effect string   `json:"Effect"`
+	Action []string `json:"Action"`
+}
+
+// Mapping is the action derived from an API path, which is used to review the policy.
+type Mapping struct {
+	Service string
+	Method  string
+	Path    string
+	Action  string
+	// Matched is whether the path is matched with a template, the IDs in the path are detected by their formats if not
+	Matched bool
+	// Vetted is whether the action is found in the table of the vetted actions, it's derived from the path if not
+	Vetted bool
+}
+
+// Generator collects the API calls and generates the policies.
+type Generator struct {
+	templates []*Template
+	mappings  map[string]*Mapping
+	// actions are the vetted actions keyed by the APIs, see actionKey
+	actions map[string]string
+}
+
+// NewGenerator returns a generator which matches the API paths with the templates and the APIs of the vetted actions.
+func NewGenerator(templates []Template) *Generator {
+	all := make([]Template, 0, len(vettedActions)+len(templates))
+	all = append(all, vettedActions...)
+	all = append(all, templates...)
+
+	g := Generator{
+		templates: make([]*Template, 0, len(all)),
+		mappings:  make(map[string]*Mapping),
+		actions:   make(map[string]string),
+	}
+	for i := range all {
+		t := all[i]
+		t.Service = strings.ToLower(t.Service)
+		t.Method = strings.ToUpper(t.Method)
+		t.pattern = templatePattern(t.Path)
+		g.templates = append(g.templates, &t)
+		if t.Action != "" {
+			g.actions[actionKey(t.Service, t.Method, t.Path)] = t.Action
+		}
+	}
+	return &g
+}
+
+// AddTemplate adds the action of an API path template, which is declared by a resource in the plan.
+func (g *Generator) AddTemplate(service, method, path string) {
+	g.add(strings.ToLower(service), strings.ToUpper(method), path, true)
+}
+
+// AddCall adds the action of an API call, the path is replaced with the matched template if any.
+func (g *Generator) AddCall(service, method, path string) {
+	service, method = strings.ToLower(service), strings.ToUpper(method)
+	if t := g.match(service, method, path); t != nil {
+		g.add(t.Service, method, t.Path, true)
+		return
+	}
+	g.add(service, method, path, false)
+}
+
+func (g *Generator) add(service, method, path string, matched bool) {
+	key := fmt.Sprintf("%s %s %s", service, method, path)
+	if _, ok := g.mappings[key]; ok {
+		return
+	}
+
+	mapping := Mapping{
+		Service: service,
+		Method:  method,
+		Path:    path,
+		Matched: matched,
+	}
+	if action, ok := g.actions[actionKey(service, method, path)]; ok {
+		mapping.Action = action
+		mapping.Vetted = true
+	} else {
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+		if !matched {
+			for i, s := range segments {
+				if idRegexp.MatchString(s) {
+					segments[i] = "{id}"
+				}
+			}
+		}
+		mapping.Action = deriveAction(service, method, segments)
+	}
+	g.mappings[key] = &mapping
+}
+
+// actionKey returns the key of an API in the table of the vetted actions, the names of the path parameters are
+// ignored since they differ between the documents, e.g. {id} and {vpc_id}.
+func actionKey(service, method, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		if paramRegexp.MatchString(s) {
+			segments[i] = "{}"
+		}
+	}
+	return fmt.Sprintf("%s %s /%s", service, method, strings.Join(segments, "/"))
+}
+
+// match returns the most specific template matching the call, the templates of the same service take precedence.
+func (g *Generator) match(service, method, path string) *Template {
+	var found *Template
+	for _, t := range g.templates {
+		if t.Method != method || !t.pattern.MatchString(path) {
+			continue
+		}
+		if found == nil || (t.Service == service && found.Service != service) ||
+			(t.Service == found.Service && strings.Count(t.Path, "{") < strings.Count(found.Path, "{")) {
+			found = t
+		}
+	}
+	return found
+}
+
+// Mappings returns the actions derived from the API paths, sorted by the services and paths.
+func (g *Generator) Mappings() []*Mapping {
+	result := make([]*Mapping, 0, len(g.mappings))
+	for _, m := range g.mappings {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Service != result[j].Service {
+			return result[i].Service < result[j].Service
+		}
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// Unvetted returns the mappings whose actions are derived from the API paths, they must be reviewed before granting
+// the policies since the derived actions may not exist.
+func (g *Generator) Unvetted() []*Mapping {
+	result := make([]*Mapping, 0)
+	for _, m := range g.Mappings() {
+		if !m.Vetted {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Policies returns the policy documents keyed by the role types, the global services and the project-level services
+// can't be granted in the same role.
+func (g *Generator) Policies() map[string]*Policy {
+	actions := make(map[string]map[string]bool)
+	for _, m := range g.mappings {
+		roleType := RoleTypeProject
+		if globalServices[m.Service] {
+			roleType = RoleTypeGlobal
+		}
+		if actions[roleType] == nil {
+			actions[roleType] = make(map[string]bool)
+		}
+		actions[roleType][m.Action] = true
+	}
+
+	policies := make(map[string]*Policy, len(actions))
+	for roleType, set := range actions {
+		list := make([]string, 0, len(set))
+		for action := range set {
+			list = append(list, action)
+		}
+		sort.Strings(list)
+		policies[roleType] = &Policy{
+			Version:   PolicyVersion,
+			Statement: []Statement{{Effect: "Allow", Action: list}},
+		}
+	}
+	return policies
+}
+
+// MarshalPolicies returns the policy document in JSON format, the policies are keyed by the role types only if both
+// the global and project-level policies are required.
+func MarshalPolicies(policies map[string]*Policy) ([]byte, error) {
+	var v interface{} = policies
+	if len(policies) == 1 {
+		for _, p := range policies {
+			v = p
+		}
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// deriveAction derives the action from the path segments. The resource is the first collection after the version and
+// project ID, and the action is decided by the method, the ID of the resource and the sub-resources, e.g.
+// GET /vpcs lists vpcs, GET /vpcs/{id} gets a vpc, and GET /vpcs/{id}/tags lists the tags of a vpc (listTags).
+func deriveAction(service, method string, segments []string) string {
+	// skip the prefix, e.g. /v1/{project_id} and /api/v3/projects/{project_id}, the project ID of an unmatched path is
+	// the first ID after the version
+	start := 0
+	for i, s := range segments {
+		if s == "{project_id}" || s == "{domain_id}" {
+			start = i + 1
+		}
+	}
+	for start < len(segments) && isPathPrefix(service, segments, start) {
+		start++
+		if start < len(segments) && segments[start] == "{id}" {
+			start++
+		}
+	}
+	segments = segments[start:]
+	if len(segments) == 0 {
+		return fmt.Sprintf("%s:*:%s", service, methodVerb(method, false))
+	}
+
+	resource := resourceName(segments[0])
+	rest := segments[1:]
+	withID := len(rest) > 0 && paramRegexp.MatchString(rest[0])
+	if withID {
+		rest = rest[1:]
+	}
+
+	// the action APIs are treated as the operations of the resources, e.g. POST /vpcs/{id}/tags/action is createTags
+	var words []string
+	for _, s := range rest {
+		if !paramRegexp.MatchString(s) && s != "action" {
+			words = append(words, s)
+		}
+	}
+	if len(words) == 0 {
+		return fmt.Sprintf("%s:%s:%s", service, resource, methodVerb(method, withID))
+	}
+	if len(words) == 1 {
+		if verb, ok := verbs[words[0]]; ok {
+			return fmt.Sprintf("%s:%s:%s", service, resource, verb)
+		}
+	}
+
+	sub := camelCase(strings.Join(words, "-"))
+	endsWithID := paramRegexp.MatchString(rest[len(rest)-1])
+	return fmt.Sprintf("%s:%s:%s%s", service, resource, methodVerb(method, endsWithID), upperFirst(sub))
+}
+
+// isPathPrefix returns whether the segment is a part of the path prefix, including the versions and the service name,
+// e.g. /v3/{project_id}/vpc/vpcs. "projects" is the prefix only if it's followed by the project ID, e.g.
+// /api/v3/projects/{project_id}.
+func isPathPrefix(service string, segments []string, i int) bool {
+	segment := segments[i]
+	if segment == "projects" {
+		return i+1 < len(segments) && paramRegexp.MatchString(segments[i+1])
+	}
+	return versionRegexp.MatchString(segment) || segment == "api" || segment == service ||
+		strings.HasSuffix(segment, "-api")
+}
+
+func methodVerb(method string, withID bool) string {
+	switch method {
+	case "GET", "HEAD":
+		if withID {
+			return "get"
+		}
+		return "list"
+	case "POST":
+		if withID {
+			return "update"
+		}
+		return "create"
+	case "PUT", "PATCH":
+		return "update"
+	case "DELETE":
+		return "delete"
+	}
+	return strings.ToLower(method)
+}
+
+func resourceName(segment string) string {
+	if name, ok := resourceNames[segment]; ok {
+		return name
+	}
+	return camelCase(segment)
+}
+
+// camelCase converts the names separated by dashes and underscores to camel case, e.g. security-groups is
+// securityGroups.
+func camelCase(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i := 1; i < len(parts); i++ {
+		parts[i] = upperFirst(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// templatePattern converts the path template to the regular expression, each parameter matches a segment.
+func templatePattern(path string) *regexp.Regexp {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		if paramRegexp.MatchString(s) {
+			segments[i] = `[^/]+`
+		} else {
+			segments[i] = regexp.QuoteMeta(s)
+		}
+	}
+	return regexp.MustCompile(`^/` + strings.Join(segments, "/") + `/?$`)
+}
@@ -0,0 +1,89 @@
+package iampolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVettedActionsFromTemplates(t *testing.T) {
+	cases := map[[2]string]string{
+		{"POST", "/v1.1/{project_id}/cloudservers"}:             "ecs:cloudServers:createServers",
+		{"GET", "/v1/{project_id}/cloudservers/detail"}:         "ecs:cloudServers:list",
+		{"GET", "/v1/{project_id}/cloudservers/{id}"}:           "ecs:cloudServers:get",
+		{"POST", "/v1/{project_id}/cloudservers/delete"}:        "ecs:cloudServers:deleteServers",
+		{"POST", "/v1.1/{project_id}/cloudservers/{id}/resize"}: "ecs:cloudServers:resize",
+	}
+	for k, expected := range cases {
+		g := NewGenerator(nil)
+		g.AddTemplate("ECS", k[0], k[1])
+		m := g.Mappings()[0]
+		if m.Action != expected || !m.Vetted {
+			t.Errorf("expected the vetted action of %s %s to be %s, got %s (vetted: %t)", k[0], k[1], expected,
+				m.Action, m.Vetted)
+		}
+	}
+}
+
+func TestDeriveActionFromTemplates(t *testing.T) {
+	cases := map[[2]string]string{
+		{"GET", "/v1/{project_id}/cloudservers/{server_id}/block_device/{volume_id}"}: "ecs:cloudServers:getBlockDevice",
+		{"GET", "/v2/cloudimages"}: "ecs:images:list",
+	}
+	for k, expected := range cases {
+		g := NewGenerator(nil)
+		g.AddTemplate("ECS", k[0], k[1])
+		if unvetted := g.Unvetted(); len(unvetted) != 1 || unvetted[0].Action != expected {
+			t.Errorf("expected the unvetted action of %s %s to be %s, got %v", k[0], k[1], expected, unvetted)
+		}
+	}
+}
+
+func TestGeneratorMatchesCalls(t *testing.T) {
+	g := NewGenerator([]Template{
+		{Service: "VPC", Method: "GET", Path: "/v1/{project_id}/vpcs/{vpc_id}"},
+		{Service: "VPC", Method: "GET", Path: "/v1/{project_id}/vpcs"},
+		{Service: "CCE", Method: "GET", Path: "/api/v3/projects/{project_id}/clusters/{cluster_id}"},
+	})
+
+	g.AddCall("vpc", "GET", "/v1/0970dd7a1300f5672ff2c003c60ae115/vpcs/my-vpc")
+	g.AddCall("vpc", "GET", "/v1/0970dd7a1300f5672ff2c003c60ae115/vpcs")
+	g.AddCall("cce", "GET", "/api/v3/projects/0970dd7a1300f5672ff2c003c60ae115/clusters/cluster-name")
+	// the unmatched paths are derived with the IDs detected by their formats
+	g.AddCall("vpc", "DELETE", "/v1/0970dd7a1300f5672ff2c003c60ae115/security-groups/"+
+		"2cca1b0c-8a2e-4d43-ae29-5d8d1d2b6c4f")
+	g.AddCall("iam", "GET", "/v3/projects")
+
+	mappings := g.Mappings()
+	actions := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		actions = append(actions, m.Action)
+	}
+	expected := []string{
+		"cce:cluster:get",
+		"iam:projects:listProjects",
+		"vpc:securityGroups:delete",
+		"vpc:vpcs:list",
+		"vpc:vpcs:get",
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected the actions %v, got %v", expected, actions)
+	}
+
+	policies := g.Policies()
+	if len(policies) != 2 {
+		t.Fatalf("expected the global and project-level policies, got %v", policies)
+	}
+	if actions := policies[RoleTypeGlobal].Statement[0].Action; !reflect.DeepEqual(actions,
+		[]string{"iam:projects:listProjects"}) {
+		t.Errorf("unexpected global actions: %v", actions)
+	}
+	if actions := policies[RoleTypeProject].Statement[0].Action; len(actions) != 4 {
+		t.Errorf("unexpected project-level actions: %v", actions)
+	}
+
+	// the action of the security group API is not vetted, so it's reported for review
+	unvetted := g.Unvetted()
+	if len(unvetted) != 1 || unvetted[0].Action != "vpc:securityGroups:delete" {
+		t.Errorf("expected the security group API to be unvetted, got %v", unvetted)
+	}
+}
@@ -1,48 +1,357 @@
 package mutexkv
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
-// MutexKV is a simple key/value store for arbitrary mutexes. It can be used to
+const (
+	// defaultWarnInterval is the interval of dumping the keys held longer than the interval
+	defaultWarnInterval = 5 * time.Minute
+)
+
+// MutexKV is a simple key/value store for arbitrary read/write mutexes. It can be used to
 // serialize changes across arbitrary collaborators that share knowledge of the
 // keys they must serialize on.
+// The holders and waiters of each key are tracked, the keys held longer than the warning interval are dumped
+// periodically with their waiters, so that a stuck lock can be located from the logs.
 type MutexKV struct {
-	lock  sync.Mutex
-	store map[string]*sync.Mutex
+	lock         sync.Mutex
+	store        map[string]*keyLock
+	warnInterval time.Duration
+	watching     bool
+}
+
+// keyLock is a read/write lock of a key, whose state is protected by the lock of MutexKV.
+type keyLock struct {
+	writer  *lockHolder
+	readers []*lockHolder
+	waiters []*lockHolder
+	// released is closed and replaced when the lock is released, to wake up the waiters
+	released chan struct{}
+}
+
+// lockHolder records who holds or waits for a lock.
+type lockHolder struct {
+	write  bool
+	caller string
+	since  time.Time
+}
+
+func (h *lockHolder) String() string {
+	mode := "read"
+	if h.write {
+		mode = "write"
+	}
+	return fmt.Sprintf("%s (%s, %s)", h.caller, mode, time.Since(h.since).Truncate(time.Second))
 }
 
 // Lock Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *MutexKV) Lock(key string) {
 	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
+	_ = m.acquire(context.Background(), key, true, caller(2))
 	log.Printf("[DEBUG] Locked %q", key)
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *MutexKV) Unlock(key string) {
 	log.Printf("[DEBUG] Unlocking %q", key)
-	m.get(key).Unlock()
+	m.release(key, true, "")
 	log.Printf("[DEBUG] Unlocked %q", key)
 }
 
-// Returns a mutex for the given key, no guarantee of its lock status
-func (m *MutexKV) get(key string) *sync.Mutex {
+// RLock locks the mutex for reading for the given key, the readers of a key don't block each other but block the
+// writers. Caller is responsible for calling RUnlock for the same key
+func (m *MutexKV) RLock(key string) {
+	log.Printf("[DEBUG] Read locking %q", key)
+	_ = m.acquire(context.Background(), key, false, caller(2))
+	log.Printf("[DEBUG] Read locked %q", key)
+}
+
+// RUnlock undoes a single RLock call for the given key, the read lock acquired by the same function is released
+func (m *MutexKV) RUnlock(key string) {
+	log.Printf("[DEBUG] Read unlocking %q", key)
+	m.release(key, false, callerFunction(2))
+	log.Printf("[DEBUG] Read unlocked %q", key)
+}
+
+// LockContext locks the mutex for the given key, it gives up and returns an error if the lock isn't acquired before
+// the context is done or the timeout (if positive) expires. Caller must call Unlock only if no error returned.
+func (m *MutexKV) LockContext(ctx context.Context, key string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Locking %q", key)
+	if err := m.acquireWithTimeout(ctx, key, true, timeout, caller(2)); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Locked %q", key)
+	return nil
+}
+
+// RLockContext is the same as LockContext except that it locks the mutex for reading. Caller must call RUnlock only
+// if no error returned.
+func (m *MutexKV) RLockContext(ctx context.Context, key string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Read locking %q", key)
+	if err := m.acquireWithTimeout(ctx, key, false, timeout, caller(2)); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Read locked %q", key)
+	return nil
+}
+
+func (m *MutexKV) acquireWithTimeout(ctx context.Context, key string, write bool, timeout time.Duration,
+	callerName string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := m.acquire(ctx, key, write, callerName); err != nil {
+		return fmt.Errorf("error waiting for the lock of %q: %s, the holders: %s", key, err, m.Holders(key))
+	}
+	return nil
+}
+
+func (m *MutexKV) acquire(ctx context.Context, key string, write bool, callerName string) error {
+	holder := &lockHolder{
+		write:  write,
+		caller: callerName,
+		since:  time.Now(),
+	}
+
+	m.lock.Lock()
+	kl := m.get(key)
+	m.watch()
+	for {
+		if kl.available(write) {
+			holder.since = time.Now()
+			if write {
+				kl.writer = holder
+			} else {
+				kl.readers = append(kl.readers, holder)
+			}
+			m.lock.Unlock()
+			return nil
+		}
+
+		kl.waiters = append(kl.waiters, holder)
+		released := kl.released
+		m.lock.Unlock()
+
+		var err error
+		select {
+		case <-released:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		m.lock.Lock()
+		kl.waiters = removeHolder(kl.waiters, holder)
+		if err != nil {
+			// the readers may be blocked by this writer
+			kl.broadcast()
+			m.cleanup(key, kl)
+			m.lock.Unlock()
+			return err
+		}
+	}
+}
+
+// release releases the lock of the key, the reader acquired the lock in the given function is removed, or the
+// earliest one if the lock is released by another function.
+func (m *MutexKV) release(key string, write bool, function string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	kl, ok := m.store[key]
+	switch {
+	case write && (!ok || kl.writer == nil):
+		panic(fmt.Sprintf("mutexkv: unlock of unlocked key %q", key))
+	case !write && (!ok || len(kl.readers) == 0):
+		panic(fmt.Sprintf("mutexkv: runlock of unlocked key %q", key))
+	case write:
+		kl.writer = nil
+	default:
+		reader := kl.readers[0]
+		for _, h := range kl.readers {
+			if strings.HasPrefix(h.caller, function+" (") {
+				reader = h
+				break
+			}
+		}
+		kl.readers = removeHolder(kl.readers, reader)
+	}
+	kl.broadcast()
+	m.cleanup(key, kl)
+}
+
+// Holders returns the description of the holders and waiters of the given key
+func (m *MutexKV) Holders(key string) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	kl, ok := m.store[key]
+	if !ok {
+		return "none"
+	}
+	return kl.String()
+}
+
+// Returns the lock for the given key, no guarantee of its lock status
+func (m *MutexKV) get(key string) *keyLock {
+	kl, ok := m.store[key]
+	if !ok {
+		kl = &keyLock{released: make(chan struct{})}
+		m.store[key] = kl
+	}
+	return kl
+}
+
+// cleanup removes the lock of the key if it's neither held nor waited for
+func (m *MutexKV) cleanup(key string, kl *keyLock) {
+	if kl.writer == nil && len(kl.readers) == 0 && len(kl.waiters) == 0 {
+		delete(m.store, key)
+	}
+}
+
+// watch starts the goroutine dumping the long-held keys if it's not running, the goroutine exits when no key is
+// locked.
+func (m *MutexKV) watch() {
+	if m.watching || m.warnInterval <= 0 {
+		return
+	}
+	m.watching = true
+
+	go func() {
+		ticker := time.NewTicker(m.warnInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			warnings, ok := m.longHeldKeys()
+			if !ok {
+				return
+			}
+			for _, warning := range warnings {
+				log.Printf("[WARN] %s", warning)
+			}
+		}
+	}()
+}
+
+// longHeldKeys returns the keys held longer than the warning interval with their holders and waiters, and false if
+// no key is locked, in which case the watching is stopped.
+func (m *MutexKV) longHeldKeys() ([]string, bool) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	mutex, ok := m.store[key]
+
+	if len(m.store) == 0 {
+		m.watching = false
+		return nil, false
+	}
+
+	warnings := make([]string, 0)
+	for key, kl := range m.store {
+		if since, ok := kl.heldSince(); ok && time.Since(since) >= m.warnInterval {
+			warnings = append(warnings, fmt.Sprintf("the lock %q has been held for %s, %s",
+				key, time.Since(since).Truncate(time.Second), kl))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings, true
+}
+
+func (kl *keyLock) available(write bool) bool {
+	if kl.writer != nil {
+		return false
+	}
+	if write {
+		return len(kl.readers) == 0
+	}
+	// the waiting writers take precedence over the new readers, so that they are not starved
+	for _, w := range kl.waiters {
+		if w.write {
+			return false
+		}
+	}
+	return true
+}
+
+func (kl *keyLock) broadcast() {
+	close(kl.released)
+	kl.released = make(chan struct{})
+}
+
+// heldSince returns the time when the earliest holder acquired the lock
+func (kl *keyLock) heldSince() (time.Time, bool) {
+	if kl.writer != nil {
+		return kl.writer.since, true
+	}
+	if len(kl.readers) > 0 {
+		return kl.readers[0].since, true
+	}
+	return time.Time{}, false
+}
+
+func (kl *keyLock) String() string {
+	holders := kl.readers
+	if kl.writer != nil {
+		holders = []*lockHolder{kl.writer}
+	}
+	return fmt.Sprintf("held by [%s], waited for by [%s]", joinHolders(holders), joinHolders(kl.waiters))
+}
+
+func joinHolders(holders []*lockHolder) string {
+	rst := make([]string, len(holders))
+	for i, h := range holders {
+		rst[i] = h.String()
+	}
+	return strings.Join(rst, ", ")
+}
+
+func removeHolder(holders []*lockHolder, holder *lockHolder) []*lockHolder {
+	for i, h := range holders {
+		if h == holder {
+			return append(holders[:i], holders[i+1:]...)
+		}
+	}
+	return holders
+}
+
+// caller returns the function and the position which calls the lock methods
+func caller(skip int) string {
+	pc, file, line, ok := runtime.Caller(skip)
 	if !ok {
-		mutex = &sync.Mutex{}
-		m.store[key] = mutex
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s:%d)", functionName(pc), filepath.Base(file), line)
+}
+
+// callerFunction returns the function which calls the unlock methods
+func callerFunction(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	return functionName(pc)
+}
+
+func functionName(pc uintptr) string {
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		return filepath.Base(fn.Name())
 	}
-	return mutex
+	return "unknown"
 }
 
 // NewMutexKV returns a properly initialized MutexKV
 func NewMutexKV() *MutexKV {
 	return &MutexKV{
-		store: make(map[string]*sync.Mutex),
+		store:        make(map[string]*keyLock),
+		warnInterval: defaultWarnInterval,
 	}
 }
@@ -1,6 +1,8 @@
 package mutexkv
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -65,3 +67,143 @@ func TestMutexKVDifferentKeys(t *testing.T) {
 		t.Fatal("Second lock on a different key blocked. This shouldn't happen.")
 	}
 }
+
+func TestMutexKVRLock(t *testing.T) {
+	mkv := NewMutexKV()
+
+	mkv.RLock("foo")
+
+	doneCh := make(chan struct{})
+
+	go func() {
+		mkv.RLock("foo")
+		close(doneCh)
+	}()
+
+	select {
+	case <-doneCh:
+		// pass
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Second read lock blocked. This shouldn't happen.")
+	}
+
+	lockCh := make(chan struct{})
+
+	go func() {
+		mkv.Lock("foo")
+		close(lockCh)
+	}()
+
+	select {
+	case <-lockCh:
+		t.Fatal("Write lock was able to be taken while read locked. This shouldn't happen.")
+	case <-time.After(50 * time.Millisecond):
+		// pass
+	}
+
+	mkv.RUnlock("foo")
+	mkv.RUnlock("foo")
+
+	select {
+	case <-lockCh:
+		// pass
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Write lock blocked after read unlock. This shouldn't happen.")
+	}
+}
+
+func readByFirstReader(mkv *MutexKV, locked, release chan struct{}) {
+	mkv.RLock("foo")
+	defer mkv.RUnlock("foo")
+	close(locked)
+	<-release
+}
+
+func readBySecondReader(mkv *MutexKV, locked, release chan struct{}) {
+	mkv.RLock("foo")
+	defer mkv.RUnlock("foo")
+	close(locked)
+	<-release
+}
+
+func TestMutexKVRUnlockHolder(t *testing.T) {
+	mkv := NewMutexKV()
+
+	firstLocked, firstRelease := make(chan struct{}), make(chan struct{})
+	go readByFirstReader(mkv, firstLocked, firstRelease)
+	<-firstLocked
+	secondLocked, secondRelease := make(chan struct{}), make(chan struct{})
+	secondDone := make(chan struct{})
+	go func() {
+		readBySecondReader(mkv, secondLocked, secondRelease)
+		close(secondDone)
+	}()
+	<-secondLocked
+
+	// the second reader is released rather than the earliest one
+	close(secondRelease)
+	<-secondDone
+	holders := mkv.Holders("foo")
+	if !strings.Contains(holders, "readByFirstReader") || strings.Contains(holders, "readBySecondReader") {
+		t.Fatalf("expected the second reader to be released, got the holders: %s", holders)
+	}
+	close(firstRelease)
+}
+
+func TestMutexKVLockContext(t *testing.T) {
+	mkv := NewMutexKV()
+
+	mkv.Lock("foo")
+
+	err := mkv.LockContext(context.Background(), "foo", 50*time.Millisecond)
+	if err == nil {
+		t.Fatal("Second lock was able to be taken. This shouldn't happen.")
+	}
+	if !strings.Contains(err.Error(), "TestMutexKVLockContext") {
+		t.Fatalf("The holder is not reported: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mkv.RLockContext(ctx, "foo", 0); err == nil {
+		t.Fatal("Read lock was able to be taken with a canceled context. This shouldn't happen.")
+	}
+
+	mkv.Unlock("foo")
+
+	if err := mkv.LockContext(context.Background(), "foo", 50*time.Millisecond); err != nil {
+		t.Fatalf("Lock failed after unlock: %s", err)
+	}
+	mkv.Unlock("foo")
+
+	if holders := mkv.Holders("foo"); holders != "none" {
+		t.Fatalf("The unlocked key is still tracked: %s", holders)
+	}
+}
+
+func TestMutexKVLongHeldKeys(t *testing.T) {
+	mkv := NewMutexKV()
+	mkv.warnInterval = 10 * time.Millisecond
+
+	mkv.Lock("foo")
+	go func() {
+		if err := mkv.LockContext(context.Background(), "foo", 50*time.Millisecond); err == nil {
+			mkv.Unlock("foo")
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	warnings, ok := mkv.longHeldKeys()
+	if !ok || len(warnings) != 1 {
+		t.Fatalf("Expect one long-held key, but got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], `"foo"`) || !strings.Contains(warnings[0], "waited for by [") {
+		t.Fatalf("Unexpected warning: %s", warnings[0])
+	}
+
+	mkv.Unlock("foo")
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := mkv.longHeldKeys(); ok {
+		t.Fatal("The watching isn't stopped after all the keys are unlocked.")
+	}
+}
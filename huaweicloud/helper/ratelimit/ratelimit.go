@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter. The bucket is refilled at the rate of tokens per second, and holds at most
+// burst tokens, each request consumes a token and waits until a token is available.
+type Limiter struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter which allows rate requests per second with the bursts of at most burst requests.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or the context is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve consumes a token and returns the waiting time until the token is available.
+func (l *Limiter) reserve() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns the token which is reserved but not used.
+func (l *Limiter) cancel() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// LimiterKV is a key/value store for the rate limiters, the limiter of a key is created when it is used first.
+type LimiterKV struct {
+	lock         sync.Mutex
+	limits       map[string]float64
+	defaultLimit float64
+	store        map[string]*Limiter
+}
+
+// NewLimiterKV returns a LimiterKV with the rate limits of keys, and the default limit is used for the other keys.
+// A limit which is not greater than zero means unlimited.
+func NewLimiterKV(limits map[string]float64, defaultLimit float64) *LimiterKV {
+	return &LimiterKV{
+		limits:       limits,
+		defaultLimit: defaultLimit,
+		store:        make(map[string]*Limiter),
+	}
+}
+
+// Wait blocks until the rate limiter of the key allows a request or the context is done.
+func (m *LimiterKV) Wait(ctx context.Context, key string) error {
+	if m == nil {
+		return nil
+	}
+	return m.get(key).Wait(ctx)
+}
+
+// Returns the rate limiter of the given key, nil means unlimited
+func (m *LimiterKV) get(key string) *Limiter {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	limiter, ok := m.store[key]
+	if !ok {
+		limit, ok := m.limits[key]
+		if !ok {
+			limit = m.defaultLimit
+		}
+		if limit > 0 {
+			limiter = NewLimiter(limit, int(limit))
+		}
+		m.store[key] = limiter
+	}
+	return limiter
+}
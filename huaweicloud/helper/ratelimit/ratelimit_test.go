@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiterWait(t *testing.T) {
+	limiter := NewLimiter(20, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// the first 2 requests are allowed by the burst, and the next 2 requests wait for 50ms respectively
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("the requests are not limited, elapsed %s", elapsed)
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	limiter := NewLimiter(1, 1)
+	_ = limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("the waiting should be canceled by the context")
+	}
+}
+
+func TestLimiterKV(t *testing.T) {
+	mkv := NewLimiterKV(map[string]float64{"iam": 1}, 0)
+
+	if limiter := mkv.get("ecs"); limiter != nil {
+		t.Fatal("the service without limit should be unlimited")
+	}
+
+	_ = mkv.Wait(context.Background(), "iam")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := mkv.Wait(ctx, "iam"); err == nil {
+		t.Fatal("the second request of iam should be limited")
+	}
+}
@@ -0,0 +1,505 @@
+package schemas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/tidwall/gjson"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// PagerType is the pagination type of the read API, see the pagers of HttpHelper.
+type PagerType string
+
+const (
+	PagerMarker   PagerType = "marker"
+	PagerOffset   PagerType = "offset"
+	PagerPageSize PagerType = "page_size"
+	PagerLink     PagerType = "link"
+)
+
+var pathParamRegexp = regexp.MustCompile(`\{(\w+)\}`)
+
+// ResourceSpec declares a resource whose CRUD operations are plain JSON APIs, NewGenericResource builds the
+// schema.Resource from the spec, e.g.
+//
+//	schemas.NewGenericResource(&schemas.ResourceSpec{
+//		Name:    "DCS backup policy",
+//		Service: "dcs",
+//		Schema:  map[string]*schema.Schema{...},
+//		Create: &schemas.Operation{
+//			Method: "POST",
+//			Path:   "v2/{project_id}/instances/{instance_id}/backups",
+//			Body:   map[string]string{"backup_name": "name", "remark": "description"},
+//			IDExp:  "backup_id",
+//			Wait:   &schemas.StatusWait{StatusExp: "status", Target: []string{"succeed"}, Failed: []string{"failed"}},
+//		},
+//		Read: &schemas.Operation{
+//			Method:     "GET",
+//			Path:       "v2/{project_id}/instances/{instance_id}/backups",
+//			Pager:      &schemas.Pager{Type: schemas.PagerOffset, DataPath: "backup_record_response"},
+//			ItemExp:    "backup_record_response[?backup_id=='{id}']|[0]",
+//			Attributes: map[string]string{"name": "backup_name", "status": "status"},
+//		},
+//		Delete: &schemas.Operation{Method: "DELETE", Path: "v2/{project_id}/instances/{instance_id}/backups/{id}"},
+//	})
+type ResourceSpec struct {
+	// Name is the name of the resource used in the error messages, e.g. DCS instance
+	Name string
+	// Service is the catalog name of the service client, e.g. dcs
+	Service string
+	// Schema is the arguments and attributes of the resource, the region is added if it's missing. The arguments
+	// which are not updated by any of the Updates are ForceNew.
+	Schema   map[string]*schema.Schema
+	Timeouts *schema.ResourceTimeout
+	// ImportParts are the arguments before the resource ID in the import ID separated by slashes, e.g. instance_id
+	// for <instance_id>/<id>. The resource is not importable if it's nil.
+	ImportParts []string
+
+	Create  *Operation
+	Read    *Operation
+	Updates []*Operation
+	Delete  *Operation
+}
+
+// Operation is an API call of the resource. The parameters in Path and ItemExp are replaced before the call,
+// {id} is the resource ID, {project_id} and {domain_id} are those of the client, and the others are the arguments of
+// the same names.
+type Operation struct {
+	Method  string
+	Path    string
+	OkCodes []int
+
+	// Query maps the query parameters to the arguments
+	Query map[string]string
+	// Body maps the request body to the arguments, the keys are the paths in the body separated by dots, e.g.
+	// instance.name; BodyFunc builds the parts which can't be mapped directly, and they are merged.
+	Body     map[string]string
+	BodyFunc func(w *ResourceDataWrapper) map[string]any
+
+	// IDExp is the JMESPath of the resource ID in the response of the create operation
+	IDExp string
+
+	// ItemExp is the JMESPath of the resource in the response of the read operation, the whole response is used if
+	// it's empty. The resource is not found if the result is null.
+	ItemExp string
+	// Attributes maps the arguments and attributes to the JMESPath expressions in the item of the read operation
+	Attributes map[string]string
+	// Pager reads the response page by page until the item is found
+	Pager *Pager
+
+	// Arguments are the arguments updated by the update operation, which is called only if any of them changes.
+	// All the arguments are updatable by the operation if it's empty.
+	Arguments []string
+
+	// Wait polls the status of the resource with the read operation after the call
+	Wait *StatusWait
+}
+
+// Pager is the pagination of the read API, the fields are passed to the pager of HttpHelper with the same names.
+type Pager struct {
+	Type     PagerType
+	DataPath string
+
+	// NextExp and MarkerKey are used by the marker pager
+	NextExp   string
+	MarkerKey string
+	// OffsetKey and LimitKey are used by the offset pager, and the default values are offset and limit
+	OffsetKey string
+	LimitKey  string
+	// PageNumKey and PerPageKey are used by the page size pager, and the default values are page and per_page
+	PageNumKey string
+	PerPageKey string
+	// Limit is the page size of the offset and page size pagers
+	Limit int
+	// LinkExp is used by the link pager
+	LinkExp string
+}
+
+// StatusWait polls the status until it's one of the Target. An empty Target of the delete operation means waiting
+// until the resource is not found.
+type StatusWait struct {
+	// StatusExp is the JMESPath of the status in the item of the read operation
+	StatusExp string
+	Target    []string
+	Failed    []string
+
+	// Delay and PollInterval default to 5 and 10 seconds
+	Delay        time.Duration
+	PollInterval time.Duration
+}
+
+type genericResource struct {
+	spec *ResourceSpec
+}
+
+// NewGenericResource builds the schema.Resource from the declarative spec.
+func NewGenericResource(spec *ResourceSpec) *schema.Resource {
+	r := &genericResource{spec: spec}
+	res := &schema.Resource{
+		CreateContext: r.create,
+		ReadContext:   r.read,
+		DeleteContext: r.delete,
+		Timeouts:      spec.Timeouts,
+		Schema:        r.buildSchema(),
+	}
+	if len(spec.Updates) > 0 {
+		res.UpdateContext = r.update
+	}
+	if spec.ImportParts != nil {
+		res.Importer = &schema.ResourceImporter{
+			StateContext: r.importState,
+		}
+	}
+	return res
+}
+
+// buildSchema copies the schema, adds the region and makes the arguments not updated by any operation ForceNew.
+func (r *genericResource) buildSchema() map[string]*schema.Schema {
+	updatable := make(map[string]bool)
+	updateAll := false
+	for _, op := range r.spec.Updates {
+		if len(op.Arguments) == 0 {
+			updateAll = true
+		}
+		for _, arg := range op.Arguments {
+			updatable[arg] = true
+		}
+	}
+
+	result := make(map[string]*schema.Schema, len(r.spec.Schema)+1)
+	for k, v := range r.spec.Schema {
+		s := *v
+		if (s.Required || s.Optional) && !updateAll && !updatable[k] {
+			s.ForceNew = true
+		}
+		result[k] = &s
+	}
+	if _, ok := result["region"]; !ok {
+		result["region"] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		}
+	}
+	return result
+}
+
+func (r *genericResource) newClient(d *schema.ResourceData, meta interface{}) (*golangsdk.ServiceClient, error) {
+	return NewSchemaWrapper(d).NewClient(meta.(*config.Config), r.spec.Service)
+}
+
+func (r *genericResource) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := r.newClient(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	op := r.spec.Create
+	rst, err := r.request(client, d, op, false)
+	if err != nil {
+		return diag.Errorf("error creating %s: %s", r.spec.Name, err)
+	}
+
+	id := utils.PathSearch(op.IDExp, rst.Value(), nil)
+	if id == nil {
+		return diag.Errorf("error creating %s: unable to find the ID from the API response", r.spec.Name)
+	}
+	d.SetId(fmt.Sprint(id))
+
+	if err := r.waitForStatus(ctx, client, d, op.Wait, d.Timeout(schema.TimeoutCreate), false); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be created: %s", r.spec.Name, d.Id(), err)
+	}
+	return r.read(ctx, d, meta)
+}
+
+func (r *genericResource) read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := r.newClient(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	item, err := r.readItem(ctx, client, d)
+	if err != nil {
+		return common.CheckDeletedDiag(d, err, fmt.Sprintf("error retrieving %s", r.spec.Name))
+	}
+
+	mErr := multierror.Append(nil, d.Set("region", meta.(*config.Config).GetRegion(d)))
+	for key, exp := range r.spec.Read.Attributes {
+		mErr = multierror.Append(mErr, d.Set(key, utils.PathSearch(exp, item, nil)))
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting %s fields: %s", r.spec.Name, err)
+	}
+	return nil
+}
+
+func (r *genericResource) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := r.newClient(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, op := range r.spec.Updates {
+		if len(op.Arguments) > 0 && !d.HasChanges(op.Arguments...) {
+			continue
+		}
+
+		if _, err := r.request(client, d, op, true); err != nil {
+			return diag.Errorf("error updating %s (%s): %s", r.spec.Name, d.Id(), err)
+		}
+		if err := r.waitForStatus(ctx, client, d, op.Wait, d.Timeout(schema.TimeoutUpdate), false); err != nil {
+			return diag.Errorf("error waiting for %s (%s) to be updated: %s", r.spec.Name, d.Id(), err)
+		}
+	}
+	return r.read(ctx, d, meta)
+}
+
+func (r *genericResource) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := r.newClient(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	op := r.spec.Delete
+	if _, err := r.request(client, d, op, false); err != nil {
+		return common.CheckDeletedDiag(d, err, fmt.Sprintf("error deleting %s", r.spec.Name))
+	}
+
+	if err := r.waitForStatus(ctx, client, d, op.Wait, d.Timeout(schema.TimeoutDelete), true); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be deleted: %s", r.spec.Name, d.Id(), err)
+	}
+	return nil
+}
+
+func (r *genericResource) importState(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData,
+	error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != len(r.spec.ImportParts)+1 {
+		format := append(append([]string{}, r.spec.ImportParts...), "id")
+		return nil, fmt.Errorf("invalid format specified for import ID, want '<%s>', but got '%s'",
+			strings.Join(format, ">/<"), d.Id())
+	}
+
+	for i, arg := range r.spec.ImportParts {
+		if err := d.Set(arg, parts[i]); err != nil {
+			return nil, err
+		}
+	}
+	d.SetId(parts[len(parts)-1])
+	return []*schema.ResourceData{d}, nil
+}
+
+// request calls the operation, the zero values are sent by the update operations to clear the arguments.
+func (r *genericResource) request(client *golangsdk.ServiceClient, d *schema.ResourceData, op *Operation,
+	keepZero bool) (*gjson.Result, error) {
+	w := NewSchemaWrapper(d)
+	helper := httphelper.New(client).
+		Method(op.Method).
+		URI(r.replaceParams(d, op.Path)).
+		OkCode(op.OkCodes...)
+
+	if query := buildQuery(w, op, keepZero); len(query) > 0 {
+		helper.Query(query)
+	}
+	if body := buildBody(w, op, keepZero); len(body) > 0 {
+		helper.Body(body)
+	}
+	return helper.Request().Result()
+}
+
+func buildQuery(w *ResourceDataWrapper, op *Operation, keepZero bool) map[string]any {
+	query := make(map[string]any, len(op.Query))
+	for param, arg := range op.Query {
+		query[param] = normalizeValue(w.Get(arg, keepZero))
+	}
+	return utils.RemoveNil(query)
+}
+
+func buildBody(w *ResourceDataWrapper, op *Operation, keepZero bool) map[string]any {
+	body := make(map[string]any)
+	for path, arg := range op.Body {
+		keys := strings.Split(path, ".")
+		node := body
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := node[key].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[key] = child
+			}
+			node = child
+		}
+		node[keys[len(keys)-1]] = normalizeValue(w.Get(arg, keepZero))
+	}
+	if op.BodyFunc != nil {
+		for k, v := range op.BodyFunc(w) {
+			body[k] = v
+		}
+	}
+	return utils.RemoveNil(body)
+}
+
+// readItem returns the resource in the response of the read operation, the pages are read until it's found.
+func (r *genericResource) readItem(ctx context.Context, client *golangsdk.ServiceClient,
+	d *schema.ResourceData) (any, error) {
+	op := r.spec.Read
+	itemExp := r.replaceParams(d, op.ItemExp)
+	search := func(body *gjson.Result) any {
+		if itemExp == "" {
+			return body.Value()
+		}
+		return utils.PathSearch(itemExp, body.Value(), nil)
+	}
+
+	helper := httphelper.New(client).
+		Method(op.Method).
+		URI(r.replaceParams(d, op.Path)).
+		OkCode(op.OkCodes...)
+	if query := buildQuery(NewSchemaWrapper(d), op, false); len(query) > 0 {
+		helper.Query(query)
+	}
+
+	var item any
+	if op.Pager == nil {
+		body, err := helper.Request().Result()
+		if err != nil {
+			return nil, err
+		}
+		item = search(body)
+	} else {
+		err := op.Pager.apply(helper).EachPage(ctx, func(page *gjson.Result) (bool, error) {
+			item = search(page)
+			return item == nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if item == nil {
+		return nil, golangsdk.ErrDefault404{}
+	}
+	return item, nil
+}
+
+func (r *genericResource) waitForStatus(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData,
+	wait *StatusWait, timeout time.Duration, deleting bool) error {
+	if wait == nil {
+		return nil
+	}
+
+	delay, pollInterval := wait.Delay, wait.PollInterval
+	if delay == 0 {
+		delay = 5 * time.Second
+	}
+	if pollInterval == 0 {
+		pollInterval = 10 * time.Second
+	}
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      r.statusRefreshFunc(ctx, client, d, wait, deleting),
+		Timeout:      timeout,
+		Delay:        delay,
+		PollInterval: pollInterval,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func (r *genericResource) statusRefreshFunc(ctx context.Context, client *golangsdk.ServiceClient,
+	d *schema.ResourceData, wait *StatusWait, deleting bool) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		item, err := r.readItem(ctx, client, d)
+		if err != nil {
+			if _, ok := err.(golangsdk.ErrDefault404); ok && deleting {
+				return "deleted", "COMPLETED", nil
+			}
+			return nil, "ERROR", err
+		}
+
+		status := fmt.Sprint(utils.PathSearch(wait.StatusExp, item, ""))
+		log.Printf("[DEBUG] the status of %s (%s) is: %s", r.spec.Name, d.Id(), status)
+		if utils.StrSliceContains(wait.Failed, status) {
+			return item, "ERROR", fmt.Errorf("unexpected status: %s", status)
+		}
+		if utils.StrSliceContains(wait.Target, status) {
+			return item, "COMPLETED", nil
+		}
+		return item, "PENDING", nil
+	}
+}
+
+// replaceParams replaces the parameters in the path or JMESPath with the resource ID and the arguments.
+func (r *genericResource) replaceParams(d *schema.ResourceData, s string) string {
+	return pathParamRegexp.ReplaceAllStringFunc(s, func(param string) string {
+		key := strings.Trim(param, "{}")
+		if key == "id" {
+			return d.Id()
+		}
+		if _, ok := r.spec.Schema[key]; ok {
+			return fmt.Sprint(d.Get(key))
+		}
+		// {project_id} and {domain_id} are replaced by HttpHelper
+		return param
+	})
+}
+
+func (p *Pager) apply(helper *httphelper.HttpHelper) *httphelper.HttpHelper {
+	switch p.Type {
+	case PagerMarker:
+		return helper.MarkerPager(p.DataPath, p.NextExp, p.MarkerKey)
+	case PagerOffset:
+		return helper.OffsetPager(p.DataPath, defaultKey(p.OffsetKey, "offset"), defaultKey(p.LimitKey, "limit"),
+			p.Limit)
+	case PagerPageSize:
+		return helper.PageSizePager(p.DataPath, defaultKey(p.PageNumKey, "page"),
+			defaultKey(p.PerPageKey, "per_page"), p.Limit)
+	case PagerLink:
+		return helper.LinkPager(p.DataPath, p.LinkExp)
+	}
+	return helper
+}
+
+func defaultKey(key, defaultValue string) string {
+	if key == "" {
+		return defaultValue
+	}
+	return key
+}
+
+// normalizeValue converts the sets to lists recursively, which can be marshaled to JSON.
+func normalizeValue(v any) any {
+	switch vv := v.(type) {
+	case *schema.Set:
+		return normalizeValue(vv.List())
+	case []any:
+		rst := make([]any, len(vv))
+		for i, item := range vv {
+			rst[i] = normalizeValue(item)
+		}
+		return rst
+	case map[string]any:
+		rst := make(map[string]any, len(vv))
+		for k, item := range vv {
+			rst[k] = normalizeValue(item)
+		}
+		return rst
+	}
+	return v
+}
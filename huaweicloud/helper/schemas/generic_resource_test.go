@@ -0,0 +1,171 @@
+package schemas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// backupServer is a fake API of the backups of instances, a backup is creating at the first read.
+type backupServer struct {
+	sync.Mutex
+	backups  map[string]map[string]any
+	requests []string
+}
+
+func (s *backupServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	s.requests = append(s.requests, req.Method+" "+req.URL.Path)
+
+	var body map[string]any
+	_ = json.NewDecoder(req.Body).Decode(&body)
+	w.Header().Set("Content-Type", "application/json")
+	id := strings.TrimPrefix(req.URL.Path, "/v2/p1/instances/i1/backups/")
+
+	switch {
+	case req.Method == "POST":
+		s.backups["b1"] = map[string]any{"backup_id": "b1", "backup_name": body["backup_name"],
+			"remark": body["remark"], "status": "creating"}
+		_ = json.NewEncoder(w).Encode(map[string]any{"backup_id": "b1"})
+	case req.Method == "GET":
+		list := make([]any, 0)
+		for _, b := range s.backups {
+			list = append(list, b)
+		}
+		if req.URL.Query().Get("offset") != "0" {
+			list = []any{}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"backups": list})
+		for _, b := range s.backups {
+			b["status"] = "succeed"
+		}
+	case req.Method == "PUT" && s.backups[id] != nil:
+		s.backups[id]["remark"] = body["backup"].(map[string]any)["remark"]
+		_, _ = w.Write([]byte("{}"))
+	case req.Method == "DELETE" && s.backups[id] != nil:
+		delete(s.backups, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("{}"))
+	}
+}
+
+func testBackupSpec() *ResourceSpec {
+	wait := &StatusWait{StatusExp: "status", Target: []string{"succeed"}, Failed: []string{"failed"},
+		Delay: time.Millisecond, PollInterval: time.Millisecond}
+	return &ResourceSpec{
+		Name:    "DCS backup",
+		Service: "dcs",
+		Schema: map[string]*schema.Schema{
+			"instance_id": {Type: schema.TypeString, Required: true},
+			"name":        {Type: schema.TypeString, Required: true},
+			"description": {Type: schema.TypeString, Optional: true},
+			"status":      {Type: schema.TypeString, Computed: true},
+		},
+		ImportParts: []string{"instance_id"},
+		Create: &Operation{
+			Method: "POST",
+			Path:   "v2/{project_id}/instances/{instance_id}/backups",
+			Body:   map[string]string{"backup_name": "name", "remark": "description"},
+			IDExp:  "backup_id",
+			Wait:   wait,
+		},
+		Read: &Operation{
+			Method:     "GET",
+			Path:       "v2/{project_id}/instances/{instance_id}/backups",
+			Pager:      &Pager{Type: PagerOffset, DataPath: "backups", Limit: 10},
+			ItemExp:    "backups[?backup_id=='{id}']|[0]",
+			Attributes: map[string]string{"name": "backup_name", "description": "remark", "status": "status"},
+		},
+		Updates: []*Operation{
+			{
+				Method:    "PUT",
+				Path:      "v2/{project_id}/instances/{instance_id}/backups/{id}",
+				Body:      map[string]string{"backup.remark": "description"},
+				Arguments: []string{"description"},
+			},
+		},
+		Delete: &Operation{
+			Method: "DELETE",
+			Path:   "v2/{project_id}/instances/{instance_id}/backups/{id}",
+			Wait:   &StatusWait{Delay: time.Millisecond, PollInterval: time.Millisecond},
+		},
+	}
+}
+
+func TestGenericResource(t *testing.T) {
+	api := &backupServer{backups: make(map[string]map[string]any)}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	cfg := &config.Config{
+		Region:    "cn-north-4",
+		Endpoints: map[string]string{"dcs": server.URL + "/"},
+		HwClient:  &golangsdk.ProviderClient{HTTPClient: *server.Client(), ProjectID: "p1"},
+	}
+
+	res := NewGenericResource(testBackupSpec())
+	assert.NoError(t, res.InternalValidate(nil, true))
+	assert.True(t, res.Schema["name"].ForceNew)
+	assert.False(t, res.Schema["description"].ForceNew)
+	assert.NotNil(t, res.Schema["region"])
+
+	d := res.TestResourceData()
+	assert.NoError(t, d.Set("instance_id", "i1"))
+	assert.NoError(t, d.Set("name", "backup"))
+	assert.NoError(t, d.Set("description", "created"))
+
+	ctx := context.Background()
+	assert.False(t, res.CreateContext(ctx, d, cfg).HasError())
+	assert.Equal(t, "b1", d.Id())
+	assert.Equal(t, "succeed", d.Get("status"))
+	assert.Equal(t, "created", d.Get("description"))
+	assert.Equal(t, "cn-north-4", d.Get("region"))
+
+	// the changes are computed from the configuration
+	d = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"instance_id": "i1",
+		"name":        "backup",
+		"description": "updated",
+	})
+	d.SetId("b1")
+	assert.False(t, res.UpdateContext(ctx, d, cfg).HasError())
+	assert.Equal(t, "updated", api.backups["b1"]["remark"])
+
+	assert.False(t, res.DeleteContext(ctx, d, cfg).HasError())
+	assert.Empty(t, api.backups)
+
+	// the resource is removed from the state if it's not found
+	assert.False(t, res.ReadContext(ctx, d, cfg).HasError())
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, "POST /v2/p1/instances/i1/backups", api.requests[0])
+}
+
+func TestGenericResourceImport(t *testing.T) {
+	res := NewGenericResource(testBackupSpec())
+
+	d := res.TestResourceData()
+	d.SetId("i1/b1")
+	rst, err := res.Importer.StateContext(context.Background(), d, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", rst[0].Id())
+	assert.Equal(t, "i1", rst[0].Get("instance_id"))
+
+	d.SetId("b1")
+	_, err = res.Importer.StateContext(context.Background(), d, nil)
+	assert.EqualError(t, err, "invalid format specified for import ID, want '<instance_id>/<id>', but got 'b1'")
+}
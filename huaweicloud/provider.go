@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/aad"
@@ -104,6 +105,7 @@ import (
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/vpn"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/waf"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/workspace"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
 const (
@@ -213,9 +215,9 @@ func Provider() *schema.Provider {
 			},
 
 			"assume_role": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["assume_role"],
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"agency_name": {
@@ -230,6 +232,23 @@ func Provider() *schema.Provider {
 							Description: descriptions["assume_role_domain_name"],
 							DefaultFunc: schema.EnvDefaultFunc("HW_ASSUME_ROLE_DOMAIN_NAME", nil),
 						},
+						"duration": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  descriptions["assume_role_duration"],
+							ValidateFunc: validation.IntBetween(900, 86400),
+						},
+						"session_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: descriptions["assume_role_session_name"],
+						},
+						"policy": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  descriptions["assume_role_policy"],
+							ValidateFunc: validation.StringIsJSON,
+						},
 					},
 				},
 			},
@@ -389,6 +408,76 @@ func Provider() *schema.Provider {
 				Description: descriptions["max_retries"],
 				DefaultFunc: schema.EnvDefaultFunc("HW_MAX_RETRIES", 5),
 			},
+
+			"max_retry_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  descriptions["max_retry_backoff"],
+				DefaultFunc:  schema.EnvDefaultFunc("HW_MAX_RETRY_BACKOFF", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"retryable_error_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["retryable_error_codes"],
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"rate_limits": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: descriptions["rate_limits"],
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+
+			"default_rate_limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  descriptions["default_rate_limit"],
+				DefaultFunc:  schema.EnvDefaultFunc("HW_DEFAULT_RATE_LIMIT", 20),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"default_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: descriptions["default_tags_tags"],
+						},
+					},
+				},
+				Description: descriptions["default_tags"],
+			},
+
+			"ignore_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: descriptions["ignore_tags_keys"],
+						},
+						"key_prefixes": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: descriptions["ignore_tags_key_prefixes"],
+						},
+					},
+				},
+				Description: descriptions["ignore_tags"],
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
@@ -1281,12 +1370,15 @@ func Provider() *schema.Provider {
 			"huaweicloud_networking_vip":           vpc.ResourceNetworkingVip(),
 			"huaweicloud_networking_vip_associate": vpc.ResourceNetworkingVIPAssociateV2(),
 
-			"huaweicloud_obs_bucket":             obs.ResourceObsBucket(),
-			"huaweicloud_obs_bucket_acl":         obs.ResourceOBSBucketAcl(),
-			"huaweicloud_obs_bucket_object":      obs.ResourceObsBucketObject(),
-			"huaweicloud_obs_bucket_object_acl":  obs.ResourceOBSBucketObjectAcl(),
-			"huaweicloud_obs_bucket_policy":      obs.ResourceObsBucketPolicy(),
-			"huaweicloud_obs_bucket_replication": obs.ResourceObsBucketReplication(),
+			"huaweicloud_obs_bucket":              obs.ResourceObsBucket(),
+			"huaweicloud_obs_bucket_acl":          obs.ResourceOBSBucketAcl(),
+			"huaweicloud_obs_bucket_inventory":    obs.ResourceObsBucketInventory(),
+			"huaweicloud_obs_bucket_notification": obs.ResourceObsBucketNotification(),
+			"huaweicloud_obs_bucket_object":       obs.ResourceObsBucketObject(),
+			"huaweicloud_obs_bucket_object_acl":   obs.ResourceOBSBucketObjectAcl(),
+			"huaweicloud_obs_bucket_policy":       obs.ResourceObsBucketPolicy(),
+			"huaweicloud_obs_bucket_replication":  obs.ResourceObsBucketReplication(),
+			"huaweicloud_obs_bucket_sync":         obs.ResourceObsBucketSync(),
 
 			"huaweicloud_oms_migration_sync_task":  oms.ResourceMigrationSyncTask(),
 			"huaweicloud_oms_migration_task":       oms.ResourceMigrationTask(),
@@ -1640,6 +1732,17 @@ func Provider() *schema.Provider {
 		},
 	}
 
+	// add tags_all to the taggable resources and merge the default tags into them
+	withProviderTags(provider.ResourcesMap)
+	// scope the regional resources to the resource-level project
+	withProjectScope(provider.ResourcesMap)
+	// filter out the ignored tags of data sources
+	withProviderIgnoreTags(provider.DataSourcesMap)
+	// add the generic filter block to the plural data sources
+	withGenericFilter(provider.DataSourcesMap)
+	// trace the API calls with the resources
+	withAPITrace(provider.ResourcesMap, provider.DataSourcesMap)
+
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := provider.TerraformVersion
 		if terraformVersion == "" {
@@ -1700,10 +1803,18 @@ func init() {
 
 		"delegated_project": "The name of delegated project (Identity v3).",
 
+		"assume_role": "The roles to assume in order, the temporary credentials of each role are used to assume the next.",
+
 		"assume_role_agency_name": "The name of agency for assume role.",
 
 		"assume_role_domain_name": "The name of domain for assume role.",
 
+		"assume_role_duration": "The duration in seconds of the temporary credentials for assume role.",
+
+		"assume_role_session_name": "The session name of the temporary credentials for assume role.",
+
+		"assume_role_policy": "The inline policy in JSON format to scope the temporary credentials for assume role.",
+
 		"cloud": "The endpoint of cloud provider, defaults to myhuaweicloud.com",
 
 		"endpoints": "The custom endpoints used to override the default endpoint URL.",
@@ -1716,7 +1827,25 @@ func init() {
 
 		"max_retries": "How many times HTTP connection should be retried until giving up.",
 
+		"max_retry_backoff": "The maximum waiting time in seconds between the retries of an API call.",
+
+		"retryable_error_codes": "The additional error codes of the API responses which should be retried.",
+
+		"rate_limits": "The maximum requests per second of the services, keyed by the service names.",
+
+		"default_rate_limit": "The maximum requests per second of the services without an explicit rate limit.",
+
 		"enterprise_project_id": "enterprise project id",
+
+		"default_tags": "Configuration block with the default tags to apply to all taggable resources.",
+
+		"default_tags_tags": "The tags to apply to all taggable resources, the tags of resource take precedence.",
+
+		"ignore_tags": "Configuration block with the tags to ignore for all resources and data sources.",
+
+		"ignore_tags_keys": "The tag keys to ignore.",
+
+		"ignore_tags_key_prefixes": "The tag key prefixes to ignore.",
 	}
 }
 
@@ -1788,6 +1917,9 @@ func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersi
 		Cloud:               cloud,
 		RegionClient:        isRegional,
 		MaxRetries:          d.Get("max_retries").(int),
+		MaxRetryBackoff:     d.Get("max_retry_backoff").(int),
+		RetryableErrorCodes: utils.ExpandToStringList(d.Get("retryable_error_codes").([]interface{})),
+		DefaultRateLimit:    d.Get("default_rate_limit").(int),
 		EnterpriseProjectID: d.Get("enterprise_project_id").(string),
 		SharedConfigFile:    d.Get("shared_config_file").(string),
 		Profile:             d.Get("profile").(string),
@@ -1808,9 +1940,45 @@ func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersi
 			conf.AssumeRoleDomain = delegatedDomianName
 		}
 	} else {
-		assumeRole := assumeRoleList[0].(map[string]interface{})
-		conf.AssumeRoleAgency = assumeRole["agency_name"].(string)
-		conf.AssumeRoleDomain = assumeRole["domain_name"].(string)
+		conf.AssumeRoleChain = make([]config.AssumeRole, len(assumeRoleList))
+		for i, v := range assumeRoleList {
+			assumeRole := v.(map[string]interface{})
+			conf.AssumeRoleChain[i] = config.AssumeRole{
+				AgencyName:  assumeRole["agency_name"].(string),
+				DomainName:  assumeRole["domain_name"].(string),
+				Duration:    assumeRole["duration"].(int),
+				SessionName: assumeRole["session_name"].(string),
+				Policy:      assumeRole["policy"].(string),
+			}
+		}
+
+		// the last role is the identity to manage the resources
+		lastRole := conf.AssumeRoleChain[len(assumeRoleList)-1]
+		conf.AssumeRoleAgency = lastRole.AgencyName
+		conf.AssumeRoleDomain = lastRole.DomainName
+	}
+
+	// get default tags
+	if defaultTagsList := d.Get("default_tags").([]interface{}); len(defaultTagsList) > 0 && defaultTagsList[0] != nil {
+		defaultTags := defaultTagsList[0].(map[string]interface{})["tags"].(map[string]interface{})
+		conf.DefaultTags = make(map[string]string, len(defaultTags))
+		for k, v := range defaultTags {
+			conf.DefaultTags[k] = v.(string)
+		}
+	}
+
+	// get ignore tags
+	if ignoreTagsList := d.Get("ignore_tags").([]interface{}); len(ignoreTagsList) > 0 && ignoreTagsList[0] != nil {
+		ignoreTags := ignoreTagsList[0].(map[string]interface{})
+		conf.IgnoreTagKeys = utils.ExpandToStringListBySet(ignoreTags["keys"].(*schema.Set))
+		conf.IgnoreTagKeyPrefixes = utils.ExpandToStringListBySet(ignoreTags["key_prefixes"].(*schema.Set))
+	}
+
+	// get rate limits of services
+	rateLimits := d.Get("rate_limits").(map[string]interface{})
+	conf.RateLimits = make(map[string]int, len(rateLimits))
+	for srv, limit := range rateLimits {
+		conf.RateLimits[srv] = limit.(int)
 	}
 
 	// get custom endpoints
@@ -1844,36 +2012,14 @@ func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersi
 
 func flattenProviderEndpoints(d *schema.ResourceData) (map[string]string, error) {
 	endpoints := d.Get("endpoints").(map[string]interface{})
-	epMap := make(map[string]string)
-
+	raw := make(map[string]string, len(endpoints))
 	for key, val := range endpoints {
-		endpoint := strings.TrimSpace(val.(string))
-		// check empty string
-		if endpoint == "" {
-			return nil, fmt.Errorf("the value of customer endpoint %s must be specified", key)
-		}
-
-		// add prefix "https://" and suffix "/"
-		if !strings.HasPrefix(endpoint, "http") {
-			endpoint = fmt.Sprintf("https://%s", endpoint)
-		}
-		if !strings.HasSuffix(endpoint, "/") {
-			endpoint = fmt.Sprintf("%s/", endpoint)
-		}
-		epMap[key] = endpoint
+		raw[key] = val.(string)
 	}
 
-	// unify the endpoint which has multiple versions
-	for key := range endpoints {
-		ep, ok := epMap[key]
-		if !ok {
-			continue
-		}
-
-		multiKeys := config.GetServiceDerivedCatalogKeys(key)
-		for _, k := range multiKeys {
-			epMap[k] = ep
-		}
+	epMap, err := config.NormalizeEndpoints(raw)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("[DEBUG] customer endpoints: %+v", epMap)
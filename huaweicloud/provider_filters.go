@@ -0,0 +1,119 @@
+package huaweicloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/filters"
+)
+
+// withGenericFilter adds the generic "filter" block to the plural data sources which don't declare it, and wraps
+// their read functions so that the filters are applied to the exported list on the client side. The data sources
+// which declare the block themselves can also send the filters to the API as the query parameters.
+func withGenericFilter(dataSources map[string]*schema.Resource) {
+	for _, r := range dataSources {
+		if _, ok := r.Schema["filter"]; ok {
+			continue
+		}
+		listKey := dataSourceListKey(r)
+		if listKey == "" {
+			continue
+		}
+
+		r.Schema["filter"] = filters.Schema()
+		ids, hasIDs := r.Schema["ids"]
+		hasIDs = hasIDs && ids.Computed && !ids.Optional && !ids.Required
+		if origin := r.ReadContext; origin != nil {
+			r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				diags := origin(ctx, d, meta)
+				if diags.HasError() {
+					return diags
+				}
+				if err := filterDataSourceList(d, listKey, hasIDs); err != nil {
+					return append(diags, diag.FromErr(err)...)
+				}
+				return diags
+			}
+		} else if origin := r.Read; origin != nil {
+			r.Read = func(d *schema.ResourceData, meta interface{}) error {
+				if err := origin(d, meta); err != nil {
+					return err
+				}
+				return filterDataSourceList(d, listKey, hasIDs)
+			}
+		}
+	}
+}
+
+// dataSourceListKey returns the name of the only computed list attribute whose elements are objects, which is the
+// list of the items found by a plural data source. An empty string is returned if there is none or more than one.
+func dataSourceListKey(r *schema.Resource) string {
+	result := ""
+	for k, s := range r.Schema {
+		if s.Type != schema.TypeList || !s.Computed || s.Optional || s.Required {
+			continue
+		}
+		if _, ok := s.Elem.(*schema.Resource); !ok {
+			continue
+		}
+		if result != "" {
+			return ""
+		}
+		result = k
+	}
+	return result
+}
+
+func filterDataSourceList(d *schema.ResourceData, listKey string, hasIDs bool) error {
+	blocks := filters.ParseBlocks(d)
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	items, _ := d.Get(listKey).([]interface{})
+	for i, item := range items {
+		items[i] = expandSchemaSets(item)
+	}
+	items, err := filters.FilterItems(blocks, items)
+	if err != nil {
+		return err
+	}
+	if err := d.Set(listKey, items); err != nil || !hasIDs {
+		return err
+	}
+
+	// keep the exported IDs consistent with the filtered items
+	ids := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if id, ok := item.(map[string]interface{})["id"]; ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) != len(items) {
+		return nil
+	}
+	return d.Set("ids", ids)
+}
+
+// expandSchemaSets converts the sets in the value to lists, so that the value can be queried as JSON.
+func expandSchemaSets(v interface{}) interface{} {
+	switch value := v.(type) {
+	case *schema.Set:
+		return expandSchemaSets(value.List())
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, e := range value {
+			result[i] = expandSchemaSets(e)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			result[k] = expandSchemaSets(e)
+		}
+		return result
+	}
+	return v
+}
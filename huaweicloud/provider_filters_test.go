@@ -0,0 +1,104 @@
+package huaweicloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericFilterDataSource(t *testing.T) {
+	dataSource := &schema.Resource{
+		ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			d.SetId("instances")
+			return diag.FromErr(d.Set("instances", []map[string]interface{}{
+				{"id": "1", "name": "web-1", "cidr": "192.168.0.0/24", "tags": map[string]interface{}{"env": "test"},
+					"security_groups": []interface{}{"default"}},
+				{"id": "2", "name": "web-2", "cidr": "172.16.0.0/16", "tags": map[string]interface{}{"env": "prod"},
+					"security_groups": []interface{}{"web", "default"}},
+				{"id": "3", "name": "db-1", "cidr": "192.168.1.0/24", "tags": map[string]interface{}{},
+					"security_groups": []interface{}{"db"}},
+			}))
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"instances": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":   {Type: schema.TypeString, Computed: true},
+						"name": {Type: schema.TypeString, Computed: true},
+						"cidr": {Type: schema.TypeString, Computed: true},
+						"tags": {Type: schema.TypeMap, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"security_groups": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+	withGenericFilter(map[string]*schema.Resource{"huaweicloud_test_instances": dataSource})
+	assert.Contains(t, dataSource.Schema, "filter")
+
+	cases := []struct {
+		name     string
+		filters  []interface{}
+		expected []string
+	}{
+		{"no filter", nil, []string{"1", "2", "3"}},
+		{"regex", []interface{}{
+			map[string]interface{}{"path": "name", "operator": "regex", "values": []interface{}{"^web-"}},
+		}, []string{"1", "2"}},
+		{"cidr and tag", []interface{}{
+			map[string]interface{}{"path": "cidr", "operator": "cidrContains", "values": []interface{}{"192.168.0.10"}},
+			map[string]interface{}{"path": "tags", "operator": "hasTag", "values": []interface{}{"env=test"}},
+		}, []string{"1"}},
+		{"set", []interface{}{
+			map[string]interface{}{"path": "security_groups", "operator": "has", "values": []interface{}{"default"}},
+		}, []string{"1", "2"}},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{"filter": c.filters})
+		assert.NoError(t, d.Set("ids", []string{"1", "2", "3"}), c.name)
+		diags := dataSource.ReadContext(context.Background(), d, nil)
+		assert.False(t, diags.HasError(), c.name)
+
+		ids := make([]string, 0)
+		for _, v := range d.Get("instances").([]interface{}) {
+			ids = append(ids, v.(map[string]interface{})["id"].(string))
+		}
+		assert.Equal(t, c.expected, ids, c.name)
+		assert.Equal(t, len(c.expected), len(d.Get("ids").([]interface{})), c.name)
+	}
+}
+
+func TestGenericFilterCoverage(t *testing.T) {
+	dataSources := Provider().DataSourcesMap
+	for _, name := range []string{
+		"huaweicloud_cce_clusters", "huaweicloud_dcs_instances", "huaweicloud_dds_instances", "huaweicloud_evs_volumes",
+		"huaweicloud_images_images", "huaweicloud_networking_secgroups", "huaweicloud_obs_buckets",
+		"huaweicloud_vpc_subnets",
+		// the data sources which declare the filter block themselves
+		"huaweicloud_compute_instances", "huaweicloud_vpcs", "huaweicloud_rds_instances",
+	} {
+		assert.Contains(t, dataSources[name].Schema, "filter", name)
+	}
+
+	// the data sources which only export the IDs have no list of objects to be filtered
+	assert.NotContains(t, dataSources["huaweicloud_vpc_ids"].Schema, "filter")
+}
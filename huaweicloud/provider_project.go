@@ -0,0 +1,101 @@
+package huaweicloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// withProjectScope adds the optional "project_id" and "project_name" arguments to every regional resource, and wraps
+// the CRUD functions so that the service clients are scoped to the specified project instead of the default project
+// of the region, e.g. the sub-project cn-north-4_prod.
+func withProjectScope(resources map[string]*schema.Resource) {
+	for _, r := range resources {
+		if !isProjectScopedResource(r) {
+			continue
+		}
+
+		r.Schema["project_id"] = &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"project_name"},
+			Description:   "The ID of the IAM project in which to manage the resource.",
+		}
+		r.Schema["project_name"] = &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"project_id"},
+			Description:   "The name of the IAM project in which to manage the resource, e.g. cn-north-4_prod.",
+		}
+
+		r.CreateContext = projectScopeContextFunc(r.CreateContext)
+		r.ReadContext = projectScopeContextFunc(r.ReadContext)
+		r.UpdateContext = projectScopeContextFunc(r.UpdateContext)
+		r.DeleteContext = projectScopeContextFunc(r.DeleteContext)
+		r.Create = projectScopeFunc(r.Create)
+		r.Read = projectScopeFunc(r.Read)
+		r.Update = projectScopeFunc(r.Update)
+		r.Delete = projectScopeFunc(r.Delete)
+	}
+}
+
+// isProjectScopedResource checks whether the resource has the "region" field, and the project arguments are not
+// defined by the resource itself.
+func isProjectScopedResource(r *schema.Resource) bool {
+	if _, ok := r.Schema["region"]; !ok {
+		return false
+	}
+	if _, ok := r.Schema["project_id"]; ok {
+		return false
+	}
+	_, ok := r.Schema["project_name"]
+	return !ok
+}
+
+// projectScopedMeta returns the configuration scoped to the project specified in the resource, an error is returned
+// if the project is not found in the region of the resource.
+func projectScopedMeta(d *schema.ResourceData, meta interface{}) (interface{}, error) {
+	cfg, ok := meta.(*config.Config)
+	if !ok {
+		return meta, nil
+	}
+
+	scoped := cfg.WithProjectScope(d.Get("project_id").(string), d.Get("project_name").(string))
+	if err := scoped.ValidateProjectScope(cfg.GetRegion(d)); err != nil {
+		return nil, err
+	}
+	return scoped, nil
+}
+
+func projectScopeContextFunc(origin func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics,
+) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if origin == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		scoped, err := projectScopedMeta(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		return origin(ctx, d, scoped)
+	}
+}
+
+func projectScopeFunc(origin func(*schema.ResourceData, interface{}) error,
+) func(*schema.ResourceData, interface{}) error {
+	if origin == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		scoped, err := projectScopedMeta(d, meta)
+		if err != nil {
+			return err
+		}
+		return origin(d, scoped)
+	}
+}
@@ -0,0 +1,308 @@
+package huaweicloud
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// withProviderTags adds the computed "tags_all" attribute to every taggable resource, and wraps the CRUD functions
+// so that the default tags configured in the provider are merged into the resource tags and the ignored tags are
+// never saved to the state.
+func withProviderTags(resources map[string]*schema.Resource) {
+	for _, r := range resources {
+		if !isTaggableResource(r) {
+			continue
+		}
+
+		tagsAll := common.TagsComputedSchema()
+		tagsAll.ForceNew = r.Schema["tags"].ForceNew
+		r.Schema["tags_all"] = tagsAll
+
+		r.CustomizeDiff = customizeDiffTagsAll(r.CustomizeDiff)
+		wrapTagsCreate(r)
+		wrapTagsRead(r)
+		wrapTagsUpdate(r)
+	}
+}
+
+// isTaggableResource checks whether the resource has an optional "tags" field in map type.
+func isTaggableResource(r *schema.Resource) bool {
+	if _, ok := r.Schema["tags_all"]; ok {
+		return false
+	}
+
+	tags, ok := r.Schema["tags"]
+	if !ok || tags.Type != schema.TypeMap || !tags.Optional {
+		return false
+	}
+	// the resource must be managed by the context-aware or the legacy CRUD functions
+	return (r.CreateContext != nil || r.Create != nil) && (r.ReadContext != nil || r.Read != nil)
+}
+
+// withProviderIgnoreTags wraps the read functions of the data sources which export the "tags" attribute, either at
+// the top level or in the elements of a list attribute such as "instances.*.tags", so that the ignored tags configured
+// in the provider are filtered out.
+func withProviderIgnoreTags(dataSources map[string]*schema.Resource) {
+	for _, r := range dataSources {
+		tagKeys := dataSourceTagKeys(r)
+		if len(tagKeys) == 0 {
+			continue
+		}
+
+		if origin := r.ReadContext; origin != nil {
+			r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				diags := origin(ctx, d, meta)
+				if diags.HasError() {
+					return diags
+				}
+				if err := filterDataSourceTags(d, meta, tagKeys); err != nil {
+					return append(diags, diag.FromErr(err)...)
+				}
+				return diags
+			}
+		} else if origin := r.Read; origin != nil {
+			r.Read = func(d *schema.ResourceData, meta interface{}) error {
+				if err := origin(d, meta); err != nil {
+					return err
+				}
+				return filterDataSourceTags(d, meta, tagKeys)
+			}
+		}
+	}
+}
+
+func isComputedTagsSchema(s *schema.Schema) bool {
+	return s != nil && s.Type == schema.TypeMap && !s.Optional && !s.Required
+}
+
+// dataSourceTagKeys returns "tags" if the data source exports the tags at the top level, and the names of the list
+// attributes whose elements export the tags.
+func dataSourceTagKeys(r *schema.Resource) []string {
+	result := make([]string, 0)
+	for k, s := range r.Schema {
+		if k == "tags" && isComputedTagsSchema(s) {
+			result = append(result, k)
+			continue
+		}
+		if s.Type != schema.TypeList || !s.Computed || s.Optional || s.Required {
+			continue
+		}
+		if elem, ok := s.Elem.(*schema.Resource); ok && isComputedTagsSchema(elem.Schema["tags"]) {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+func filterDataSourceTags(d *schema.ResourceData, meta interface{}, tagKeys []string) error {
+	if conf, ok := meta.(*config.Config); !ok || len(conf.IgnoreTagKeys) == 0 && len(conf.IgnoreTagKeyPrefixes) == 0 {
+		return nil
+	}
+
+	for _, k := range tagKeys {
+		if k == "tags" {
+			if err := d.Set("tags", ignoreTags(d.Get("tags"), meta)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		elements, _ := d.Get(k).([]interface{})
+		for _, e := range elements {
+			if element, ok := e.(map[string]interface{}); ok {
+				element["tags"] = ignoreTags(element["tags"], meta)
+			}
+		}
+		if len(elements) > 0 {
+			if err := d.Set(k, elements); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getDefaultTags(meta interface{}) map[string]string {
+	if conf, ok := meta.(*config.Config); ok {
+		return conf.DefaultTags
+	}
+	return nil
+}
+
+func ignoreTags(tags interface{}, meta interface{}) map[string]interface{} {
+	tagMap, _ := tags.(map[string]interface{})
+	if conf, ok := meta.(*config.Config); ok {
+		return utils.IgnoreTags(tagMap, conf.IgnoreTagKeys, conf.IgnoreTagKeyPrefixes)
+	}
+	return tagMap
+}
+
+func customizeDiffTagsAll(origin schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if origin != nil {
+			if err := origin(ctx, d, meta); err != nil {
+				return err
+			}
+		}
+
+		if !d.NewValueKnown("tags") {
+			return d.SetNewComputed("tags_all")
+		}
+
+		resourceTags, _ := d.Get("tags").(map[string]interface{})
+		allTags := utils.MergeDefaultTags(getDefaultTags(meta), resourceTags)
+		if oldTags, _ := d.GetChange("tags_all"); reflect.DeepEqual(oldTags, allTags) {
+			return nil
+		}
+
+		return d.SetNew("tags_all", allTags)
+	}
+}
+
+// setAllTags saves the effective tags to "tags" and "tags_all" before calling the origin create or update function,
+// so that the default tags are applied whichever way the resource handles its tags.
+func setAllTags(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	resourceTags, _ := d.Get("tags").(map[string]interface{})
+	allTags := utils.MergeDefaultTags(getDefaultTags(meta), resourceTags)
+
+	if err := d.Set("tags_all", allTags); err != nil {
+		return nil, err
+	}
+	if err := d.Set("tags", allTags); err != nil {
+		return nil, err
+	}
+	return resourceTags, nil
+}
+
+// refreshTagsState splits the tags set by the origin functions into "tags" and "tags_all", the ignored tags will be
+// filtered out.
+func refreshTagsState(d *schema.ResourceData, meta interface{}, resourceTags map[string]interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	allTags := ignoreTags(d.Get("tags"), meta)
+	if err := d.Set("tags_all", allTags); err != nil {
+		return err
+	}
+	return d.Set("tags", utils.RemoveDefaultTags(getDefaultTags(meta), allTags, resourceTags))
+}
+
+// setPriorAllTags saves the effective tags in the state to "tags" before calling the origin read function, so that a
+// resource which refreshes its tags reports the drift of the effective tags, and a resource which doesn't keeps them.
+func setPriorAllTags(d *schema.ResourceData) (map[string]interface{}, error) {
+	resourceTags, _ := d.Get("tags").(map[string]interface{})
+	priorAllTags, _ := d.Get("tags_all").(map[string]interface{})
+	if len(priorAllTags) > 0 {
+		if err := d.Set("tags", priorAllTags); err != nil {
+			return nil, err
+		}
+	}
+	return resourceTags, nil
+}
+
+func wrapTagsCreate(r *schema.Resource) {
+	if origin := r.CreateContext; origin != nil {
+		r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			resourceTags, err := setAllTags(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			diags := origin(ctx, d, meta)
+			if err := refreshTagsState(d, meta, resourceTags); err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+			return diags
+		}
+		return
+	}
+
+	origin := r.Create
+	r.Create = func(d *schema.ResourceData, meta interface{}) error {
+		resourceTags, err := setAllTags(d, meta)
+		if err != nil {
+			return err
+		}
+
+		err = origin(d, meta)
+		if refreshErr := refreshTagsState(d, meta, resourceTags); err == nil {
+			err = refreshErr
+		}
+		return err
+	}
+}
+
+func wrapTagsRead(r *schema.Resource) {
+	if origin := r.ReadContext; origin != nil {
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			resourceTags, err := setPriorAllTags(d)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			diags := origin(ctx, d, meta)
+			if diags.HasError() {
+				return diags
+			}
+			if err := refreshTagsState(d, meta, resourceTags); err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+			return diags
+		}
+		return
+	}
+
+	origin := r.Read
+	r.Read = func(d *schema.ResourceData, meta interface{}) error {
+		resourceTags, err := setPriorAllTags(d)
+		if err != nil {
+			return err
+		}
+
+		if err := origin(d, meta); err != nil {
+			return err
+		}
+		return refreshTagsState(d, meta, resourceTags)
+	}
+}
+
+func wrapTagsUpdate(r *schema.Resource) {
+	if origin := r.UpdateContext; origin != nil {
+		r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			resourceTags, err := setAllTags(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			diags := origin(ctx, d, meta)
+			if err := refreshTagsState(d, meta, resourceTags); err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+			return diags
+		}
+		return
+	}
+
+	if origin := r.Update; origin != nil {
+		r.Update = func(d *schema.ResourceData, meta interface{}) error {
+			resourceTags, err := setAllTags(d, meta)
+			if err != nil {
+				return err
+			}
+
+			err = origin(d, meta)
+			if refreshErr := refreshTagsState(d, meta, resourceTags); err == nil {
+				err = refreshErr
+			}
+			return err
+		}
+	}
+}
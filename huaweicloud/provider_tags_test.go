@@ -0,0 +1,44 @@
+package huaweicloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+func TestIgnoreTagsPerProviderConfig(t *testing.T) {
+	resourceSchema := map[string]*schema.Schema{
+		"tags":     common.TagsSchema(),
+		"tags_all": common.TagsComputedSchema(),
+	}
+	remoteTags := map[string]interface{}{
+		"owner":       "platform",
+		"finops:team": "infra",
+		"env":         "test",
+	}
+
+	// the provider aliases are configured with different ignore_tags
+	finops := &config.Config{IgnoreTagKeyPrefixes: []string{"finops:"}}
+	env := &config.Config{IgnoreTagKeys: []string{"env"}}
+
+	for _, c := range []struct {
+		meta     *config.Config
+		expected map[string]interface{}
+	}{
+		{finops, map[string]interface{}{"owner": "platform", "env": "test"}},
+		{env, map[string]interface{}{"owner": "platform", "finops:team": "infra"}},
+		{finops, map[string]interface{}{"owner": "platform", "env": "test"}},
+	} {
+		d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{})
+		d.SetId("resource-id")
+		assert.NoError(t, d.Set("tags", remoteTags))
+
+		assert.NoError(t, refreshTagsState(d, c.meta, nil))
+		assert.Equal(t, c.expected, d.Get("tags_all"))
+		assert.Equal(t, c.expected, d.Get("tags"))
+	}
+}
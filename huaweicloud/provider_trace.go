@@ -0,0 +1,55 @@
+package huaweicloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// withAPITrace wraps the CRUD functions of the resources and the read functions of the data sources when the API trace
+// is enabled by HW_API_TRACE_FILE, so that the API calls made by them are traced with the resource type and ID.
+func withAPITrace(resources, dataSources map[string]*schema.Resource) {
+	if !config.APITraceEnabled() {
+		return
+	}
+
+	for name, r := range resources {
+		r.CreateContext = traceContextFunc(name, r.CreateContext)
+		r.ReadContext = traceContextFunc(name, r.ReadContext)
+		r.UpdateContext = traceContextFunc(name, r.UpdateContext)
+		r.DeleteContext = traceContextFunc(name, r.DeleteContext)
+		r.Create = traceFunc(name, r.Create)
+		r.Read = traceFunc(name, r.Read)
+		r.Update = traceFunc(name, r.Update)
+		r.Delete = traceFunc(name, r.Delete)
+	}
+	for name, r := range dataSources {
+		r.ReadContext = traceContextFunc(name, r.ReadContext)
+		r.Read = traceFunc(name, r.Read)
+	}
+}
+
+func traceContextFunc(name string, origin func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics,
+) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if origin == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		defer config.TraceOperation(name, d.Id())()
+		return origin(ctx, d, meta)
+	}
+}
+
+func traceFunc(name string, origin func(*schema.ResourceData, interface{}) error,
+) func(*schema.ResourceData, interface{}) error {
+	if origin == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		defer config.TraceOperation(name, d.Id())()
+		return origin(d, meta)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/compute/v2/extensions/keypairs"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/logp"
@@ -50,6 +51,20 @@ func ResourceComputeKeypairV2() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"pgp_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"public_key"},
+			},
+			"encrypted_private_key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -81,6 +96,10 @@ func resourceComputeKeypairV2Create(d *schema.ResourceData, meta interface{}) er
 			return fmtp.Errorf("Unable to generate private key: %s", err)
 		}
 		d.Set("key_file", fp)
+
+		if err := encryption.EncryptToState(d, "encrypted_private_key", kp.PrivateKey, "private key"); err != nil {
+			return fmtp.Errorf("Error saving the encrypted private key: %s", err)
+		}
 	}
 
 	return resourceComputeKeypairV2Read(d, meta)
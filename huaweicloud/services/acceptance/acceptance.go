@@ -3,15 +3,25 @@ package acceptance
 
 import (
 	"fmt"
+	"math/rand"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
+// cassetteRandomSeed is the fixed seed of the random names in cassette mode, so that the requests in replay mode are
+// the same as the recorded ones.
+const cassetteRandomSeed = 20240101
+
+// the environment variables are restored from the cassette before the following variables are initialized
+var cassetteEnabled = initCassette()
+
 var (
 	HW_REGION_NAME        = os.Getenv("HW_REGION_NAME")
 	HW_REGION_NAME_1      = os.Getenv("HW_REGION_NAME_1")
@@ -340,6 +350,52 @@ func init() {
 	}
 }
 
+// initCassette prepares the environment for the cassette mode. The non-sensitive environment variables are saved to
+// the cassette in record mode, and restored with fake credentials in replay mode, so the tests can run without network
+// access and real credentials.
+func initCassette() bool {
+	cassette, err := config.GetCassette()
+	if err != nil {
+		panic(err)
+	}
+	if cassette == nil {
+		return false
+	}
+
+	//nolint:staticcheck
+	rand.Seed(cassetteRandomSeed)
+
+	if cassette.Mode() == config.CassetteModeRecord {
+		for _, env := range os.Environ() {
+			if key, value, ok := strings.Cut(env, "="); ok && strings.HasPrefix(key, "HW_") && !isSensitiveEnv(key) {
+				cassette.SetEnv(key, value)
+			}
+		}
+		cassette.AddSecrets(os.Getenv("HW_ACCESS_KEY"), os.Getenv("HW_SECRET_KEY"), os.Getenv("HW_SECURITY_TOKEN"))
+		return true
+	}
+
+	for key, value := range cassette.Env {
+		if os.Getenv(key) == "" {
+			os.Setenv(key, value)
+		}
+	}
+	if os.Getenv("HW_ACCESS_KEY") == "" || os.Getenv("HW_SECRET_KEY") == "" {
+		os.Setenv("HW_ACCESS_KEY", "replay-access-key")
+		os.Setenv("HW_SECRET_KEY", "replay-secret-key")
+	}
+	return true
+}
+
+func isSensitiveEnv(key string) bool {
+	for _, keyword := range []string{"KEY", "SECRET", "PASSWORD", "PWD", "TOKEN"} {
+		if strings.Contains(key, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
 func preCheckRequiredEnvVars(t *testing.T) {
 	if HW_REGION_NAME == "" {
 		t.Fatal("HW_REGION_NAME must be set for acceptance tests")
@@ -406,6 +462,15 @@ func TestAccPreCheck(t *testing.T) {
 	}
 
 	preCheckRequiredEnvVars(t)
+
+	// save the recorded interactions after each test, so they are kept even if the later tests are interrupted
+	if cassette, _ := config.GetCassette(); cassetteEnabled && cassette != nil {
+		t.Cleanup(func() {
+			if err := cassette.Save(); err != nil {
+				t.Errorf("failed to save the cassette: %s", err)
+			}
+		})
+	}
 }
 
 // lintignore:AT003
@@ -2,6 +2,7 @@ package cce
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -356,6 +357,38 @@ func TestAccCluster_resize(t *testing.T) {
 	})
 }
 
+func TestAccCluster_upgrade(t *testing.T) {
+	var cluster clusters.Clusters
+
+	rName := acceptance.RandomAccResourceNameWithDash()
+	resourceName := "huaweicloud_cce_cluster.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { acceptance.TestAccPreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCluster_upgrade(rName, "v1.25"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckClusterExists(resourceName, &cluster),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestMatchResourceAttr(resourceName, "cluster_version", regexp.MustCompile(`^v1\.25`)),
+				),
+			},
+			{
+				Config: testAccCluster_upgrade(rName, "v1.27"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &cluster.Metadata.Id),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestMatchResourceAttr(resourceName, "cluster_version", regexp.MustCompile(`^v1\.27`)),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCluster_resizePeriod(t *testing.T) {
 	var cluster clusters.Clusters
 
@@ -836,3 +869,22 @@ resource "huaweicloud_cce_cluster" "test" {
 }
 `, common.TestVpc(rName), rName)
 }
+
+func testAccCluster_upgrade(rName, version string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "huaweicloud_cce_cluster" "test" {
+  name                   = "%[2]s"
+  flavor_id              = "cce.s1.small"
+  cluster_version        = "%[3]s"
+  vpc_id                 = huaweicloud_vpc.test.id
+  subnet_id              = huaweicloud_vpc_subnet.test.id
+  container_network_type = "overlay_l2"
+
+  upgrade_strategy {
+    step = 5
+  }
+}
+`, common.TestVpc(rName), rName, version)
+}
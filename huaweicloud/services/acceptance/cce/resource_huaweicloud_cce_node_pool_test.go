@@ -928,3 +928,85 @@ resource "huaweicloud_cce_node_pool" "test" {
 }
 `, testAccNodePool_base(rName), rName)
 }
+
+func TestAccNodePool_rollingUpdate(t *testing.T) {
+	var (
+		nodePool nodepools.NodePool
+		poolID   string
+
+		name         = acceptance.RandomAccResourceNameWithDash()
+		resourceName = "huaweicloud_cce_node_pool.test"
+
+		baseConfig = testAccNodePool_base(name)
+
+		rc = acceptance.InitResourceCheck(
+			resourceName,
+			&nodePool,
+			getNodePoolFunc,
+		)
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { acceptance.TestAccPreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      rc.CheckResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNodePool_rollingUpdate(name, baseConfig, "data.huaweicloud_compute_flavors.test.ids[0]"),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "current_node_count", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "flavor_id",
+						"data.huaweicloud_compute_flavors.test", "ids.0"),
+					resource.TestCheckResourceAttrWith(resourceName, "id", func(value string) error {
+						poolID = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccNodePool_rollingUpdate(name, baseConfig, "data.huaweicloud_compute_flavors.test.ids[1]"),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "current_node_count", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "flavor_id",
+						"data.huaweicloud_compute_flavors.test", "ids.1"),
+					resource.TestCheckResourceAttrPtr(resourceName, "id", &poolID),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodePool_rollingUpdate(name, baseConfig, flavor string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "huaweicloud_cce_node_pool" "test" {
+  cluster_id         = huaweicloud_cce_cluster.test.id
+  name               = "%[2]s"
+  os                 = "EulerOS 2.9"
+  flavor_id          = %[3]s
+  initial_node_count = 2
+  availability_zone  = data.huaweicloud_availability_zones.test.names[0]
+  key_pair           = huaweicloud_kps_keypair.test.name
+  type               = "vm"
+
+  root_volume {
+    size       = 40
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+
+  rolling_update {
+    max_surge       = 1
+    max_unavailable = 1
+  }
+}
+`, baseConfig, name, flavor)
+}
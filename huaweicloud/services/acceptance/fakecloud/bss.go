@@ -0,0 +1,22 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+func init() {
+	Register("bss", registerBSS)
+}
+
+// registerBSS registers the BSS API used by the provider to check the website type when it's configured, the
+// billing APIs are not supported since only pay-per-use resources are provided by the fake cloud.
+func registerBSS(s *Server) {
+	s.AddEndpoints("bss")
+
+	s.Handle(http.MethodGet, "/v2/products/service-types", func(req *Request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"service_types": []interface{}{},
+			"total_count":   0,
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package fakecloud
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	kindServer = "server"
+	kindPort   = "port"
+	kindImage  = "image"
+
+	// ImageID is the ID of the public image provided by the fake cloud, it can be used to create ECS instances.
+	ImageID = "5c6b1ec5-5ffa-4bc0-8f3b-0a45cbee1c0e"
+	// ImageName is the name of the public image provided by the fake cloud.
+	ImageName = "Fake Ubuntu 22.04 server 64bit"
+)
+
+func init() {
+	Register("ecs", registerECS)
+}
+
+// registerECS registers the pay-per-use ECS instance APIs, as well as the ports of the instances and the public
+// images. An instance is created and deleted by jobs, the system volume is created with it.
+func registerECS(s *Server) {
+	s.AddEndpoints("ecs", "ims")
+
+	s.Store.Put(kindImage, ImageID, Object{
+		"id":          ImageID,
+		"name":        ImageName,
+		"status":      "active",
+		"visibility":  "public",
+		"__os_type":   "Linux",
+		"__imagetype": "gold",
+		"min_disk":    40,
+		"disk_format": "zvhd2",
+		"created_at":  Now(),
+	})
+	s.Handle(http.MethodGet, "/v2/cloudimages", func(req *Request) (int, interface{}) {
+		query := req.URL.Query()
+		images := s.Store.List(kindImage, func(o Object) bool {
+			return (query.Get("id") == "" || o["id"] == query.Get("id")) &&
+				(query.Get("name") == "" || o["name"] == query.Get("name"))
+		})
+		return http.StatusOK, map[string]interface{}{"images": images}
+	})
+
+	s.Handle(http.MethodPost, "/v1.1/{project_id}/cloudservers", s.createServer)
+	s.Handle(http.MethodGet, "/v1/{project_id}/cloudservers/{server_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["server_id"]
+		server, ok := s.Store.Get(kindServer, id)
+		if !ok {
+			return NotFound(kindServer, id)
+		}
+		server["tags"] = s.TagStrings(kindServer, id)
+		return http.StatusOK, map[string]interface{}{"server": server}
+	})
+	s.Handle(http.MethodPut, "/v1/{project_id}/cloudservers/{server_id}", func(req *Request) (int, interface{}) {
+		fields := Object{"updated": Now()}
+		for k, v := range req.BodyObject("server") {
+			if k == "user_data" {
+				k = "OS-EXT-SRV-ATTR:user_data"
+			}
+			fields[k] = v
+		}
+		return updateObject(s, kindServer, "server", req.Params["server_id"], fields)
+	})
+	s.Handle(http.MethodPost, "/v1/{project_id}/cloudservers/delete", s.deleteServers)
+	s.Handle(http.MethodGet, "/v1/{project_id}/cloudservers/{server_id}/block_device/{volume_id}",
+		func(req *Request) (int, interface{}) {
+			server, ok := s.Store.Peek(kindServer, req.Params["server_id"])
+			if !ok {
+				return NotFound(kindServer, req.Params["server_id"])
+			}
+
+			attached, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+			for i, item := range attached {
+				attachment := item.(map[string]interface{})
+				if attachment["id"] != req.Params["volume_id"] {
+					continue
+				}
+				volume, _ := s.Store.Peek(kindVolume, req.Params["volume_id"])
+				return http.StatusOK, map[string]interface{}{
+					"volumeAttachment": map[string]interface{}{
+						"id":         attachment["id"],
+						"serverId":   server["id"],
+						"volumeId":   attachment["id"],
+						"device":     attachment["device"],
+						"size":       volume["size"],
+						"bootIndex":  i,
+						"pciAddress": fmt.Sprintf("0000:02:%02d.0", i+1),
+						"bus":        "virtio",
+					},
+				}
+			}
+			return NotFound("block_device", req.Params["volume_id"])
+		})
+	s.HandleTags("/v1/{project_id}/cloudservers", kindServer)
+
+	s.Handle(http.MethodGet, "/v1/{project_id}/ports/{port_id}", func(req *Request) (int, interface{}) {
+		return getObject(s, kindPort, "port", req.Params["port_id"])
+	})
+}
+
+func (s *Server) createServer(req *Request) (int, interface{}) {
+	opts := req.BodyObject("server")
+	extendParam, _ := opts["extendparam"].(map[string]interface{})
+	if _, ok := extendParam["chargingMode"]; ok {
+		return http.StatusBadRequest, ErrorBody("Ecs.0005", "the prePaid instance is not supported by the fake cloud")
+	}
+	if imageRef, _ := opts["imageRef"].(string); imageRef != ImageID {
+		return http.StatusBadRequest, ErrorBody("Ecs.0005", fmt.Sprintf("image %s not found", imageRef))
+	}
+
+	id := NewID()
+	name, _ := opts["name"].(string)
+	availabilityZone := defaultString(opts["availability_zone"], s.Region+"a")
+
+	addresses := make([]interface{}, 0)
+	nics, _ := opts["nics"].([]interface{})
+	for _, item := range nics {
+		nic, _ := item.(map[string]interface{})
+		port, err := s.createPort(id, nic)
+		if err != nil {
+			return http.StatusBadRequest, ErrorBody("Ecs.0005", err.Error())
+		}
+		addresses = append(addresses, map[string]interface{}{
+			"version":                 "4",
+			"addr":                    port["fixed_ips"].([]interface{})[0].(map[string]interface{})["ip_address"],
+			"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+			"OS-EXT-IPS:port_id":      port["id"],
+			"OS-EXT-IPS:type":         "fixed",
+		})
+	}
+
+	securityGroups := make([]interface{}, 0)
+	groups, _ := opts["security_groups"].([]interface{})
+	for _, item := range groups {
+		groupID, _ := item.(map[string]interface{})["id"].(string)
+		group, ok := s.Store.Peek("security_group", groupID)
+		if !ok {
+			return http.StatusBadRequest, ErrorBody("Ecs.0005", fmt.Sprintf("security group %s not found", groupID))
+		}
+		securityGroups = append(securityGroups, map[string]interface{}{"id": groupID, "name": group["name"]})
+	}
+
+	// the system volume is created and attached by the instance creation
+	rootVolume, _ := opts["root_volume"].(map[string]interface{})
+	volume := s.newVolume(map[string]interface{}{
+		"name":              name + "-volume-0000",
+		"size":              defaultValue(rootVolume["size"], 40),
+		"volume_type":       rootVolume["volumetype"],
+		"availability_zone": availabilityZone,
+		"iops":              rootVolume["iops"],
+		"throughput":        rootVolume["throughput"],
+	})
+	volume["status"] = "in-use"
+	volume["bootable"] = "true"
+	volume["attachments"] = []interface{}{
+		map[string]interface{}{
+			"attachment_id": NewID(),
+			"id":            volume["id"],
+			"volume_id":     volume["id"],
+			"server_id":     id,
+			"device":        "/dev/vda",
+			"attached_at":   Now(),
+		},
+	}
+	s.Store.Put(kindVolume, volume["id"].(string), volume)
+
+	if tags, ok := opts["server_tags"].([]interface{}); ok {
+		s.SetTags(kindServer, id, tags)
+	}
+	flavorRef, _ := opts["flavorRef"].(string)
+	vpcID, _ := opts["vpcid"].(string)
+	server := Object{
+		"id":          id,
+		"name":        name,
+		"description": defaultValue(opts["description"], ""),
+		"status":      "BUILD",
+		"tenant_id":   s.ProjectID,
+		"key_name":    defaultValue(opts["key_name"], ""),
+		"image":       map[string]interface{}{"id": ImageID},
+		"flavor": map[string]interface{}{
+			"id":   flavorRef,
+			"name": flavorRef,
+		},
+		"metadata": map[string]interface{}{
+			"charging_mode": "0",
+			"vpc_id":        vpcID,
+			"image_name":    ImageName,
+		},
+		"addresses": map[string]interface{}{
+			vpcID: addresses,
+		},
+		"security_groups": securityGroups,
+		"os-extended-volumes:volumes_attached": []interface{}{
+			map[string]interface{}{
+				"id":                    volume["id"],
+				"bootIndex":             "0",
+				"device":                "/dev/vda",
+				"delete_on_termination": "true",
+			},
+		},
+		"enterprise_project_id":       defaultString(extendParam["enterprise_project_id"], "0"),
+		"OS-EXT-AZ:availability_zone": availabilityZone,
+		"OS-EXT-SRV-ATTR:hostname":    strings.ToLower(name),
+		"OS-EXT-SRV-ATTR:user_data":   defaultValue(opts["user_data"], ""),
+		"auto_terminate_time":         defaultValue(opts["auto_terminate_time"], ""),
+		"created":                     Now(),
+		"updated":                     Now(),
+	}
+	s.Store.Put(kindServer, id, server, "BUILD", "ACTIVE")
+
+	return http.StatusOK, map[string]interface{}{
+		"job_id":    s.newServerJob("createServer", id),
+		"serverIds": []string{id},
+	}
+}
+
+func (s *Server) deleteServers(req *Request) (int, interface{}) {
+	servers, _ := req.Body["servers"].([]interface{})
+	if len(servers) != 1 {
+		return http.StatusBadRequest, ErrorBody("Ecs.0005", "only one instance can be deleted by the fake cloud")
+	}
+	id, _ := servers[0].(map[string]interface{})["id"].(string)
+	server, ok := s.Store.Peek(kindServer, id)
+	if !ok {
+		return NotFound(kindServer, id)
+	}
+
+	deleteVolume, _ := req.Body["delete_volume"].(bool)
+	attached, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+	for _, item := range attached {
+		attachment := item.(map[string]interface{})
+		volumeID := attachment["id"].(string)
+		if deleteVolume || attachment["bootIndex"] == "0" {
+			s.Store.Delete(kindVolume, volumeID)
+		} else {
+			s.Store.Update(kindVolume, volumeID, Object{"status": "available", "attachments": []interface{}{}})
+		}
+	}
+	for _, port := range s.Store.List(kindPort, func(o Object) bool { return o["device_id"] == id }) {
+		s.Store.Delete(kindPort, port["id"].(string))
+	}
+
+	s.Store.Delete(kindServer, id, "DELETED")
+	return http.StatusOK, map[string]interface{}{
+		"job_id": s.newServerJob("deleteServer", id),
+	}
+}
+
+// newServerJob creates the job of an ECS instance, the instance ID is returned in the entities of the sub-job.
+func (s *Server) newServerJob(jobType, serverID string) string {
+	return s.NewJob(jobType, map[string]interface{}{
+		"sub_jobs_total": 1,
+		"sub_jobs": []interface{}{
+			map[string]interface{}{
+				"status":   "SUCCESS",
+				"job_id":   NewID(),
+				"job_type": jobType,
+				"entities": map[string]interface{}{"server_id": serverID},
+			},
+		},
+	})
+}
+
+// createPort creates the port of the instance in the subnet, the IP address is allocated if not specified.
+func (s *Server) createPort(serverID string, nic map[string]interface{}) (Object, error) {
+	subnetID, _ := nic["subnet_id"].(string)
+	subnet, ok := s.Store.Peek("subnet", subnetID)
+	if !ok {
+		return nil, fmt.Errorf("subnet %s not found", subnetID)
+	}
+
+	address, _ := nic["ip_address"].(string)
+	if address == "" {
+		_, ipNet, err := net.ParseCIDR(subnet["cidr"].(string))
+		if err != nil {
+			return nil, err
+		}
+		used := s.Store.List(kindPort, func(o Object) bool { return o["network_id"] == subnetID })
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(ipNet.IP.To4())+uint32(len(used))+10)
+		address = ip.String()
+	}
+
+	id := NewID()
+	port := Object{
+		"id":         id,
+		"name":       "",
+		"network_id": subnetID,
+		"fixed_ips": []interface{}{
+			map[string]interface{}{"subnet_id": subnetID, "ip_address": address},
+		},
+		"mac_address":           fmt.Sprintf("fa:16:3e:%s:%s:%s", id[0:2], id[2:4], id[4:6]),
+		"device_id":             serverID,
+		"device_owner":          "compute:" + s.Region + "a",
+		"tenant_id":             s.ProjectID,
+		"allowed_address_pairs": []interface{}{},
+		"status":                "ACTIVE",
+		"admin_state_up":        true,
+	}
+	s.Store.Put(kindPort, id, port)
+	return port, nil
+}
@@ -0,0 +1,66 @@
+package fakecloud
+
+import (
+	"testing"
+)
+
+func TestFakeCloudComputeInstance(t *testing.T) {
+	PreCheck(t)
+	t.Parallel()
+
+	s := NewServer(t)
+	p := s.Provider(t, nil)
+
+	vpc := NewResourceTest(t, p, "huaweicloud_vpc")
+	vpc.Apply(map[string]interface{}{
+		"name": "vpc-test",
+		"cidr": "192.168.0.0/16",
+	})
+	subnet := NewResourceTest(t, p, "huaweicloud_vpc_subnet")
+	subnet.Apply(map[string]interface{}{
+		"name":       "subnet-test",
+		"cidr":       "192.168.0.0/24",
+		"gateway_ip": "192.168.0.1",
+		"vpc_id":     vpc.State.ID,
+	})
+	secgroup := NewResourceTest(t, p, "huaweicloud_networking_secgroup")
+	secgroup.Apply(map[string]interface{}{
+		"name": "secgroup-test",
+	})
+
+	config := map[string]interface{}{
+		"name":               "ecs-test",
+		"image_id":           ImageID,
+		"flavor_id":          "s6.small.1",
+		"security_group_ids": []interface{}{secgroup.State.ID},
+		"availability_zone":  s.Region + "a",
+		"system_disk_type":   "SSD",
+		"network": []interface{}{
+			map[string]interface{}{"uuid": subnet.State.ID},
+		},
+		"tags": map[string]interface{}{
+			"foo": "bar",
+		},
+	}
+	instance := NewResourceTest(t, p, "huaweicloud_compute_instance")
+	instance.Apply(config)
+	assertAttr(t, instance, "status", "ACTIVE")
+	assertAttr(t, instance, "image_name", ImageName)
+	assertAttr(t, instance, "network.0.fixed_ip_v4", "192.168.0.10")
+	assertAttr(t, instance, "system_disk_size", "40")
+	assertAttr(t, instance, "tags.foo", "bar")
+
+	config["name"] = "ecs-test-update"
+	config["tags"] = map[string]interface{}{
+		"key": "value",
+	}
+	instance.Apply(config)
+	assertAttr(t, instance, "name", "ecs-test-update")
+	assertAttr(t, instance, "tags.%", "1")
+	assertAttr(t, instance, "tags.key", "value")
+
+	instance.Destroy()
+	secgroup.Destroy()
+	subnet.Destroy()
+	vpc.Destroy()
+}
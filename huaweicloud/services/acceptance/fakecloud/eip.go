@@ -0,0 +1,162 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+const (
+	kindPublicIP  = "publicip"
+	kindBandwidth = "bandwidth"
+)
+
+func init() {
+	Register("eip", registerEIP)
+}
+
+// registerEIP registers the pay-per-use EIP and bandwidth APIs. An EIP becomes DOWN (unbound) after being read once,
+// and it's removed after being read once more since it's released.
+func registerEIP(s *Server) {
+	var addressCount int32
+
+	s.Handle(http.MethodPost, "/v1/{project_id}/publicips", func(req *Request) (int, interface{}) {
+		return s.createPublicIP(req, atomic.AddInt32(&addressCount, 1))
+	})
+	s.Handle(http.MethodGet, "/v1/{project_id}/publicips/{publicip_id}", func(req *Request) (int, interface{}) {
+		return s.getPublicIP(req.Params["publicip_id"], true)
+	})
+	s.Handle(http.MethodPut, "/v1/{project_id}/publicips/{publicip_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["publicip_id"]
+		if _, ok := s.Store.Update(kindPublicIP, id, req.BodyObject("publicip")); !ok {
+			return NotFound(kindPublicIP, id)
+		}
+		return s.getPublicIP(id, false)
+	})
+	s.Handle(http.MethodDelete, "/v1/{project_id}/publicips/{publicip_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["publicip_id"]
+		publicIP, ok := s.Store.Peek(kindPublicIP, id)
+		if !ok {
+			return NotFound(kindPublicIP, id)
+		}
+		if portID, _ := publicIP["port_id"].(string); portID != "" {
+			return http.StatusConflict, ErrorBody("VPC.0503", "the EIP is bound to a port")
+		}
+
+		s.Store.Delete(kindPublicIP, id, "PENDING_DELETE")
+		if bandwidthID, _ := publicIP["bandwidth_id"].(string); bandwidthID != "" {
+			s.Store.Delete(kindBandwidth, bandwidthID)
+		}
+		return http.StatusNoContent, nil
+	})
+	s.Handle(http.MethodGet, "/v3/{project_id}/eip/publicips/{publicip_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["publicip_id"]
+		publicIP, ok := s.Store.Peek(kindPublicIP, id)
+		if !ok {
+			return NotFound(kindPublicIP, id)
+		}
+		bandwidth, _ := s.Store.Peek(kindBandwidth, publicIP["bandwidth_id"].(string))
+		publicIP["publicip_pool_name"] = publicIP["type"]
+		publicIP["bandwidth"] = bandwidth
+		publicIP["tags"] = s.TagStrings(kindPublicIP, id)
+		publicIP["vnic"] = map[string]interface{}{
+			"port_id":            publicIP["port_id"],
+			"private_ip_address": publicIP["private_ip_address"],
+		}
+		return http.StatusOK, map[string]interface{}{"publicip": publicIP}
+	})
+	s.HandleTags("/v2.0/{project_id}/publicips", kindPublicIP)
+
+	s.Handle(http.MethodGet, "/v1/{project_id}/bandwidths/{bandwidth_id}", func(req *Request) (int, interface{}) {
+		return getObject(s, kindBandwidth, "bandwidth", req.Params["bandwidth_id"])
+	})
+	s.Handle(http.MethodPut, "/v1/{project_id}/bandwidths/{bandwidth_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["bandwidth_id"]
+		fields := req.BodyObject("bandwidth")
+		fields["updated_at"] = Now()
+		bandwidth, ok := s.Store.Update(kindBandwidth, id, fields)
+		if !ok {
+			return NotFound(kindBandwidth, id)
+		}
+
+		// the bandwidth size is also returned by the EIP API
+		for _, publicIP := range s.Store.List(kindPublicIP, func(o Object) bool { return o["bandwidth_id"] == id }) {
+			s.Store.Update(kindPublicIP, publicIP["id"].(string), Object{
+				"bandwidth_name": bandwidth["name"],
+				"bandwidth_size": bandwidth["size"],
+			})
+		}
+		return http.StatusOK, map[string]interface{}{"bandwidth": bandwidth}
+	})
+}
+
+func (s *Server) createPublicIP(req *Request, index int32) (int, interface{}) {
+	ipOpts := req.BodyObject("publicip")
+	bandwidthOpts := req.BodyObject("bandwidth")
+	if _, ok := req.Body["extendParam"]; ok {
+		return http.StatusBadRequest, ErrorBody("VPC.0001", "the prePaid EIP is not supported by the fake cloud")
+	}
+
+	bandwidthID, _ := bandwidthOpts["id"].(string)
+	if bandwidthID == "" {
+		bandwidthID = NewID()
+		s.Store.Put(kindBandwidth, bandwidthID, Object{
+			"id":                    bandwidthID,
+			"name":                  bandwidthOpts["name"],
+			"size":                  bandwidthOpts["size"],
+			"share_type":            bandwidthOpts["share_type"],
+			"charge_mode":           defaultString(bandwidthOpts["charge_mode"], "bandwidth"),
+			"bandwidth_type":        "bgp",
+			"tenant_id":             s.ProjectID,
+			"enterprise_project_id": defaultString(req.Body["enterprise_project_id"], "0"),
+			"status":                "NORMAL",
+			"created_at":            Now(),
+			"updated_at":            Now(),
+		})
+	} else if _, ok := s.Store.Peek(kindBandwidth, bandwidthID); !ok {
+		return NotFound(kindBandwidth, bandwidthID)
+	}
+	bandwidth, _ := s.Store.Peek(kindBandwidth, bandwidthID)
+
+	address, _ := ipOpts["ip_address"].(string)
+	if address == "" {
+		address = fmt.Sprintf("100.85.%d.%d", index/250, index%250+1)
+	}
+
+	publicIP := Object{
+		"id":                    NewID(),
+		"type":                  ipOpts["type"],
+		"alias":                 defaultValue(ipOpts["alias"], ""),
+		"ip_version":            defaultValue(ipOpts["ip_version"], 4),
+		"public_ip_address":     address,
+		"bandwidth_id":          bandwidthID,
+		"bandwidth_name":        bandwidth["name"],
+		"bandwidth_size":        bandwidth["size"],
+		"bandwidth_share_type":  bandwidth["share_type"],
+		"enterprise_project_id": defaultString(req.Body["enterprise_project_id"], "0"),
+		"tenant_id":             s.ProjectID,
+		"create_time":           Now(),
+		"created_at":            Now(),
+		"updated_at":            Now(),
+		"status":                "PENDING_CREATE",
+	}
+	s.Store.Put(kindPublicIP, publicIP["id"].(string), publicIP, "PENDING_CREATE", "DOWN")
+	return http.StatusOK, map[string]interface{}{"publicip": publicIP}
+}
+
+// getPublicIP returns the EIP with the tags, the status is changed only when it's required.
+func (s *Server) getPublicIP(id string, next bool) (int, interface{}) {
+	var publicIP Object
+	var ok bool
+	if next {
+		publicIP, ok = s.Store.Get(kindPublicIP, id)
+	} else {
+		publicIP, ok = s.Store.Peek(kindPublicIP, id)
+	}
+	if !ok {
+		return NotFound(kindPublicIP, id)
+	}
+
+	publicIP["tags"] = s.TagStrings(kindPublicIP, id)
+	return http.StatusOK, map[string]interface{}{"publicip": publicIP}
+}
@@ -0,0 +1,55 @@
+package fakecloud
+
+import (
+	"testing"
+)
+
+func TestFakeCloudEIP(t *testing.T) {
+	PreCheck(t)
+	t.Parallel()
+
+	s := NewServer(t)
+	p := s.Provider(t, nil)
+
+	eip := NewResourceTest(t, p, "huaweicloud_vpc_eip")
+	eip.Apply(map[string]interface{}{
+		"name": "eip-test",
+		"publicip": []interface{}{
+			map[string]interface{}{"type": "5_bgp"},
+		},
+		"bandwidth": []interface{}{
+			map[string]interface{}{
+				"share_type":  "PER",
+				"name":        "bandwidth-test",
+				"size":        5,
+				"charge_mode": "traffic",
+			},
+		},
+		"tags": map[string]interface{}{
+			"foo": "bar",
+		},
+	})
+	assertAttr(t, eip, "status", "UNBOUND")
+	assertAttr(t, eip, "bandwidth.0.size", "5")
+	assertAttr(t, eip, "tags.foo", "bar")
+
+	eip.Apply(map[string]interface{}{
+		"name": "eip-test-update",
+		"publicip": []interface{}{
+			map[string]interface{}{"type": "5_bgp"},
+		},
+		"bandwidth": []interface{}{
+			map[string]interface{}{
+				"share_type":  "PER",
+				"name":        "bandwidth-test",
+				"size":        10,
+				"charge_mode": "traffic",
+			},
+		},
+	})
+	assertAttr(t, eip, "name", "eip-test-update")
+	assertAttr(t, eip, "bandwidth.0.size", "10")
+	assertAttr(t, eip, "tags.%", "0")
+
+	eip.Destroy()
+}
@@ -0,0 +1,121 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+const kindVolume = "volume"
+
+func init() {
+	Register("evs", registerEVS)
+}
+
+// registerEVS registers the pay-per-use EVS volume APIs. A volume is creating in the first read and becomes available
+// after that, and the creation and expansion are finished by the jobs.
+func registerEVS(s *Server) {
+	s.AddEndpoints("evs")
+
+	s.Handle(http.MethodPost, "/v2.1/{project_id}/cloudvolumes", func(req *Request) (int, interface{}) {
+		if _, ok := req.Body["bssParam"]; ok {
+			return http.StatusBadRequest, ErrorBody("EVS.2024", "the prePaid volume is not supported by the fake cloud")
+		}
+
+		opts := req.BodyObject("volume")
+		volume := s.newVolume(opts)
+		id := volume["id"].(string)
+		if tags, ok := opts["tags"].(map[string]interface{}); ok {
+			s.SetTags(kindVolume, id, tagListFromMap(tags))
+		}
+		s.Store.Put(kindVolume, id, volume, "creating", "available")
+
+		return http.StatusAccepted, map[string]interface{}{
+			"job_id":     s.NewJob("createVolume", map[string]interface{}{"volume_id": id}),
+			"volume_ids": []string{id},
+		}
+	})
+	s.Handle(http.MethodGet, "/v2/{project_id}/cloudvolumes/{volume_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["volume_id"]
+		volume, ok := s.Store.Get(kindVolume, id)
+		if !ok {
+			return NotFound(kindVolume, id)
+		}
+
+		tags := make(map[string]interface{})
+		for _, item := range s.GetTags(kindVolume, id) {
+			tag := item.(map[string]interface{})
+			tags[tag["key"].(string)] = tag["value"]
+		}
+		volume["tags"] = tags
+		return http.StatusOK, map[string]interface{}{"volume": volume}
+	})
+	s.Handle(http.MethodPut, "/v2/{project_id}/cloudvolumes/{volume_id}", func(req *Request) (int, interface{}) {
+		fields := req.BodyObject("volume")
+		fields["updated_at"] = Now()
+		return updateObject(s, kindVolume, "volume", req.Params["volume_id"], fields)
+	})
+	s.Handle(http.MethodPost, "/v2.1/{project_id}/cloudvolumes/{volume_id}/action", func(req *Request) (int, interface{}) {
+		id := req.Params["volume_id"]
+		volume, ok := s.Store.Peek(kindVolume, id)
+		if !ok {
+			return NotFound(kindVolume, id)
+		}
+
+		extend := req.BodyObject("os-extend")
+		newSize, _ := extend["new_size"].(float64)
+		if size, _ := volume["size"].(float64); newSize <= size {
+			return http.StatusBadRequest, ErrorBody("EVS.2023", "the new size must be greater than the current size")
+		}
+		status := volume["status"].(string)
+		s.Store.Update(kindVolume, id, Object{"size": newSize}, "extending", status)
+
+		return http.StatusAccepted, map[string]interface{}{
+			"job_id": s.NewJob("extendVolume", map[string]interface{}{"volume_id": id}),
+		}
+	})
+	s.Handle(http.MethodDelete, "/v2/{project_id}/cloudvolumes/{volume_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["volume_id"]
+		volume, ok := s.Store.Peek(kindVolume, id)
+		if !ok {
+			return NotFound(kindVolume, id)
+		}
+		if attachments, _ := volume["attachments"].([]interface{}); len(attachments) > 0 {
+			return http.StatusBadRequest, ErrorBody("EVS.2003", "the volume is in use")
+		}
+
+		s.Store.Delete(kindVolume, id, "deleting")
+		return http.StatusOK, nil
+	})
+	s.HandleTags("/v2/{project_id}/cloudvolumes", kindVolume)
+}
+
+// newVolume builds a volume from the creation options, the volume isn't saved into the store.
+func (s *Server) newVolume(opts map[string]interface{}) Object {
+	return Object{
+		"id":                    NewID(),
+		"name":                  defaultValue(opts["name"], ""),
+		"description":           defaultValue(opts["description"], ""),
+		"size":                  defaultValue(opts["size"], 10),
+		"volume_type":           opts["volume_type"],
+		"availability_zone":     opts["availability_zone"],
+		"multiattach":           defaultValue(opts["multiattach"], false),
+		"bootable":              "false",
+		"encrypted":             false,
+		"enterprise_project_id": defaultString(opts["enterprise_project_id"], "0"),
+		"attachments":           []interface{}{},
+		"metadata":              map[string]interface{}{},
+		"iops":                  map[string]interface{}{"total_val": defaultValue(opts["iops"], 0)},
+		"throughput":            map[string]interface{}{"total_val": defaultValue(opts["throughput"], 0)},
+		"wwn":                   NewID(),
+		"status":                "creating",
+		"created_at":            Now(),
+		"updated_at":            Now(),
+	}
+}
+
+func tagListFromMap(tags map[string]interface{}) []interface{} {
+	result := make([]interface{}, 0, len(tags))
+	for k, v := range tags {
+		result = append(result, map[string]interface{}{"key": k, "value": v})
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package fakecloud
+
+import (
+	"testing"
+)
+
+func TestFakeCloudEVSVolume(t *testing.T) {
+	PreCheck(t)
+	t.Parallel()
+
+	s := NewServer(t)
+	p := s.Provider(t, nil)
+
+	volume := NewResourceTest(t, p, "huaweicloud_evs_volume")
+	volume.Apply(map[string]interface{}{
+		"name":              "volume-test",
+		"availability_zone": s.Region + "a",
+		"volume_type":       "SSD",
+		"size":              20,
+		"tags": map[string]interface{}{
+			"foo": "bar",
+		},
+	})
+	assertAttr(t, volume, "tags.foo", "bar")
+
+	volume.Apply(map[string]interface{}{
+		"name":              "volume-test-update",
+		"availability_zone": s.Region + "a",
+		"volume_type":       "SSD",
+		"size":              40,
+		"tags": map[string]interface{}{
+			"foo": "baz",
+		},
+	})
+	assertAttr(t, volume, "name", "volume-test-update")
+	assertAttr(t, volume, "size", "40")
+	assertAttr(t, volume, "tags.foo", "baz")
+
+	imported := volume.Import(volume.State.ID)
+	if imported.Attributes["volume_type"] != "SSD" {
+		t.Fatalf("unexpected type of the imported volume: %s", imported.Attributes["volume_type"])
+	}
+
+	volume.Destroy()
+}
@@ -0,0 +1,111 @@
+package fakecloud
+
+import (
+	"net/http"
+	"time"
+)
+
+func init() {
+	Register("iam", registerIAM)
+}
+
+// registerIAM registers the IAM APIs which are called when configuring the provider, including token issuance and
+// the queries of projects, domains and users.
+func registerIAM(s *Server) {
+	s.AddEndpoints("iam")
+
+	s.Handle(http.MethodPost, "/v3/auth/tokens", s.createToken)
+	s.Handle(http.MethodGet, "/v3/projects", s.listProjects)
+	s.Handle(http.MethodGet, "/v3/auth/projects", s.listProjects)
+	s.Handle(http.MethodGet, "/v3/auth/domains", func(_ *Request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"domains": []interface{}{s.domain()},
+		}
+	})
+	s.Handle(http.MethodGet, "/v3/users", func(req *Request) (int, interface{}) {
+		users := []interface{}{}
+		if name := req.URL.Query().Get("name"); name == "" || name == UserName {
+			users = append(users, map[string]interface{}{
+				"id":        s.userID(),
+				"name":      UserName,
+				"domain_id": s.DomainID,
+				"enabled":   true,
+			})
+		}
+		return http.StatusOK, map[string]interface{}{
+			"users": users,
+		}
+	})
+}
+
+func (s *Server) domain() map[string]interface{} {
+	return map[string]interface{}{
+		"id":      s.DomainID,
+		"name":    DomainName,
+		"enabled": true,
+	}
+}
+
+func (s *Server) userID() string {
+	return "user" + s.DomainID
+}
+
+func (s *Server) project() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        s.ProjectID,
+		"name":      s.Region,
+		"domain_id": s.DomainID,
+		"enabled":   true,
+	}
+}
+
+// createToken issues a token for the password authentication, the token is accepted by the other APIs.
+func (s *Server) createToken(req *Request) (int, interface{}) {
+	auth := req.BodyObject("auth")
+	identity, _ := auth["identity"].(map[string]interface{})
+	password, _ := identity["password"].(map[string]interface{})
+	user, _ := password["user"].(map[string]interface{})
+	if user["name"] != UserName || user["password"] != Password {
+		return http.StatusUnauthorized, ErrorBody("IAM.0001", "The username or password is incorrect")
+	}
+
+	token := map[string]interface{}{
+		"expires_at": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+		"issued_at":  time.Now().UTC().Format(time.RFC3339),
+		"methods":    []string{"password"},
+		"user": map[string]interface{}{
+			"id":     s.userID(),
+			"name":   UserName,
+			"domain": s.domain(),
+		},
+		"catalog": []interface{}{},
+	}
+
+	// the token is scoped to the project or the domain
+	if scope, ok := auth["scope"].(map[string]interface{}); ok {
+		if _, ok := scope["project"]; ok {
+			token["project"] = map[string]interface{}{
+				"id":     s.ProjectID,
+				"name":   s.Region,
+				"domain": s.domain(),
+			}
+		} else {
+			token["domain"] = s.domain()
+		}
+	}
+
+	req.SetHeader("X-Subject-Token", s.IssueToken())
+	return http.StatusCreated, map[string]interface{}{
+		"token": token,
+	}
+}
+
+func (s *Server) listProjects(req *Request) (int, interface{}) {
+	projects := []interface{}{}
+	if name := req.URL.Query().Get("name"); name == "" || name == s.Region {
+		projects = append(projects, s.project())
+	}
+	return http.StatusOK, map[string]interface{}{
+		"projects": projects,
+	}
+}
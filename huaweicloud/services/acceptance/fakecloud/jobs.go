@@ -0,0 +1,40 @@
+package fakecloud
+
+import (
+	"net/http"
+)
+
+const kindJob = "job"
+
+func init() {
+	Register("jobs", registerJobs)
+}
+
+// registerJobs registers the job API shared by ECS and EVS, both services use the same path.
+func registerJobs(s *Server) {
+	s.Handle(http.MethodGet, "/v1/{project_id}/jobs/{job_id}", func(req *Request) (int, interface{}) {
+		id := req.Params["job_id"]
+		job, ok := s.Store.Get(kindJob, id)
+		if !ok {
+			return NotFound(kindJob, id)
+		}
+		if job["status"] == "SUCCESS" {
+			job["end_time"] = Now()
+		}
+		return http.StatusOK, job
+	})
+}
+
+// NewJob creates an asynchronous job which is RUNNING in the first query and becomes SUCCESS after that, the
+// entities are returned as the job result. The job ID is returned.
+func (s *Server) NewJob(jobType string, entities map[string]interface{}) string {
+	id := NewID()
+	s.Store.Put(kindJob, id, Object{
+		"job_id":     id,
+		"job_type":   jobType,
+		"status":     "INIT",
+		"entities":   entities,
+		"begin_time": Now(),
+	}, "RUNNING", "SUCCESS")
+	return id
+}
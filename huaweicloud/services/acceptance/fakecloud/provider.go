@@ -0,0 +1,183 @@
+package fakecloud
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+)
+
+// PreCheck skips the test unless HW_FAKECLOUD is set, the tests take minutes since the resources wait for the
+// statuses with fixed intervals.
+func PreCheck(t *testing.T) {
+	if os.Getenv("HW_FAKECLOUD") == "" {
+		t.Skip("HW_FAKECLOUD must be set for the tests with the fake cloud")
+	}
+}
+
+// ProviderConfig returns the provider configuration to access the fake server with AK/SK.
+func (s *Server) ProviderConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"region":      s.Region,
+		"access_key":  AccessKey,
+		"secret_key":  SecretKey,
+		"project_id":  s.ProjectID,
+		"auth_url":    s.URL + "/v3",
+		"endpoints":   s.Endpoints(),
+		"max_retries": 0,
+	}
+}
+
+// Provider returns a HuaweiCloud provider which is configured with the configuration, the default configuration
+// returned by ProviderConfig is used if it is nil.
+func (s *Server) Provider(t *testing.T, config map[string]interface{}) *schema.Provider {
+	t.Helper()
+
+	if config == nil {
+		config = s.ProviderConfig()
+	}
+
+	p := huaweicloud.Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Fatalf("failed to configure the provider: %+v", diags)
+	}
+	return p
+}
+
+// ResourceTest runs the lifecycle of a resource in process, the same as Terraform does: the plan is calculated with
+// the configuration and the state, then it is applied and the state is refreshed.
+type ResourceTest struct {
+	t            *testing.T
+	provider     *schema.Provider
+	resourceType string
+	resource     *schema.Resource
+
+	// State is the current state of the resource, it is nil if the resource is not created
+	State *terraform.InstanceState
+}
+
+// NewResourceTest returns a ResourceTest of the resource type in the provider.
+func NewResourceTest(t *testing.T, provider *schema.Provider, resourceType string) *ResourceTest {
+	t.Helper()
+
+	r, ok := provider.ResourcesMap[resourceType]
+	if !ok {
+		t.Fatalf("the resource type %s is not found in the provider", resourceType)
+	}
+	return &ResourceTest{
+		t:            t,
+		provider:     provider,
+		resourceType: resourceType,
+		resource:     r,
+	}
+}
+
+// Apply creates or updates the resource with the configuration, then refreshes the state and checks the plan is empty.
+func (rt *ResourceTest) Apply(config map[string]interface{}) *terraform.InstanceState {
+	rt.t.Helper()
+
+	ctx := context.Background()
+	meta := rt.provider.Meta()
+	resourceConfig := terraform.NewResourceConfigRaw(config)
+	if diags := rt.resource.Validate(resourceConfig); diags.HasError() {
+		rt.t.Fatalf("invalid configuration of %s: %+v", rt.resourceType, diags)
+	}
+
+	diff, err := rt.resource.Diff(ctx, rt.State, resourceConfig, meta)
+	if err != nil {
+		rt.t.Fatalf("failed to plan %s: %s", rt.resourceType, err)
+	}
+
+	state, diags := rt.resource.Apply(ctx, rt.State, diff, meta)
+	if state != nil {
+		rt.State = state
+	}
+	if diags.HasError() {
+		rt.t.Fatalf("failed to apply %s: %+v", rt.resourceType, diags)
+	}
+
+	rt.Refresh()
+
+	// the configuration should be consistent with the state after applying
+	diff, err = rt.resource.Diff(ctx, rt.State, resourceConfig, meta)
+	if err != nil {
+		rt.t.Fatalf("failed to plan %s after applying: %s", rt.resourceType, err)
+	}
+	if !diff.Empty() {
+		rt.t.Fatalf("the plan of %s is not empty after applying: %s", rt.resourceType, diff.GoString())
+	}
+	return rt.State
+}
+
+// Refresh reads the resource and updates the state, the state will be nil if the resource doesn't exist.
+func (rt *ResourceTest) Refresh() *terraform.InstanceState {
+	rt.t.Helper()
+
+	if rt.State == nil {
+		return nil
+	}
+
+	state, diags := rt.resource.RefreshWithoutUpgrade(context.Background(), rt.State, rt.provider.Meta())
+	if diags.HasError() {
+		rt.t.Fatalf("failed to refresh %s: %+v", rt.resourceType, diags)
+	}
+	rt.State = state
+	return state
+}
+
+// Import imports the resource with the ID and refreshes it, the state of the ResourceTest is not changed.
+func (rt *ResourceTest) Import(id string) *terraform.InstanceState {
+	rt.t.Helper()
+
+	ctx := context.Background()
+	states, err := rt.provider.ImportState(ctx, &terraform.InstanceInfo{Type: rt.resourceType}, id)
+	if err != nil {
+		rt.t.Fatalf("failed to import %s: %s", rt.resourceType, err)
+	}
+	if len(states) != 1 {
+		rt.t.Fatalf("expected 1 state imported for %s, but got %d", rt.resourceType, len(states))
+	}
+
+	state, diags := rt.resource.RefreshWithoutUpgrade(ctx, states[0], rt.provider.Meta())
+	if diags.HasError() {
+		rt.t.Fatalf("failed to refresh the imported %s: %+v", rt.resourceType, diags)
+	}
+	if state == nil {
+		rt.t.Fatalf("the imported %s (%s) does not exist", rt.resourceType, id)
+	}
+	return state
+}
+
+// Destroy deletes the resource, and checks it doesn't exist after refreshing.
+func (rt *ResourceTest) Destroy() {
+	rt.t.Helper()
+
+	if rt.State == nil {
+		return
+	}
+
+	diff := &terraform.InstanceDiff{Destroy: true}
+	state, diags := rt.resource.Apply(context.Background(), rt.State, diff, rt.provider.Meta())
+	if diags.HasError() {
+		rt.t.Fatalf("failed to destroy %s: %+v", rt.resourceType, diags)
+	}
+	if state != nil {
+		rt.t.Fatalf("the state of %s is not empty after destroying", rt.resourceType)
+	}
+
+	if rt.Refresh() != nil {
+		rt.t.Fatalf("%s (%s) still exists after destroying", rt.resourceType, rt.State.ID)
+	}
+}
+
+// Attr returns the attribute value in the state.
+func (rt *ResourceTest) Attr(key string) string {
+	if rt.State == nil {
+		return ""
+	}
+	return rt.State.Attributes[key]
+}
@@ -0,0 +1,284 @@
+// Package fakecloud provides an in-process fake HuaweiCloud API server, which is used to unit test the resources
+// without a real account. The provider reaches the server through the custom "endpoints" and "auth_url".
+//
+// The built-in services keep the resources in memory, and the statuses of them are changed step by step like the
+// real cloud, so the refresh functions of the resources can be tested. More services can be added by Register.
+package fakecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+const (
+	// DefaultRegion is the region name of the fake cloud
+	DefaultRegion = "cn-fake-1"
+	// AccessKey and SecretKey are the credentials accepted by the fake cloud, the signature is not verified
+	AccessKey = "FAKEACCESSKEY"
+	SecretKey = "FAKESECRETKEY"
+	// UserName, Password and DomainName are the user accepted by the IAM token API
+	UserName   = "fake-user"
+	Password   = "Fake@Password1"
+	DomainName = "fake-domain"
+)
+
+// HandlerFunc handles the API request, and returns the status code and the response body which will be encoded in
+// JSON format. No body will be written if the body is nil.
+type HandlerFunc func(req *Request) (int, interface{})
+
+// Request is the API request received by the fake server.
+type Request struct {
+	*http.Request
+	// Params is the path parameters, e.g. {"project_id": "xxx"} for the pattern "/v1/{project_id}/vpcs"
+	Params map[string]string
+	// Body is the request body decoded in JSON format
+	Body map[string]interface{}
+
+	writer http.ResponseWriter
+}
+
+// SetHeader sets the header of the response.
+func (r *Request) SetHeader(key, value string) {
+	r.writer.Header().Set(key, value)
+}
+
+// BodyObject returns the JSON object of the specified key in the request body, e.g. "vpc" of {"vpc": {...}}.
+func (r *Request) BodyObject(key string) map[string]interface{} {
+	if v, ok := r.Body[key].(map[string]interface{}); ok {
+		return v
+	}
+	return map[string]interface{}{}
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  HandlerFunc
+}
+
+// Server is the fake HuaweiCloud API server.
+type Server struct {
+	*httptest.Server
+
+	Region    string
+	ProjectID string
+	DomainID  string
+	Store     *Store
+
+	lock   sync.RWMutex
+	routes []route
+	tokens map[string]bool
+	// endpointKeys is the service keys of the provider "endpoints" which are served by the server
+	endpointKeys []string
+}
+
+// Service registers the handlers of a cloud service to the server.
+type Service func(s *Server)
+
+var (
+	servicesLock sync.Mutex
+	services     = make(map[string]Service)
+)
+
+// Register adds a service which will be registered to every new fake server.
+func Register(name string, service Service) {
+	servicesLock.Lock()
+	defer servicesLock.Unlock()
+	services[name] = service
+}
+
+// NewServer starts a fake server with all registered services, the server is closed when the test finishes.
+func NewServer(t *testing.T) *Server {
+	s := &Server{
+		Region:    DefaultRegion,
+		ProjectID: strings.ReplaceAll(NewID(), "-", ""),
+		DomainID:  strings.ReplaceAll(NewID(), "-", ""),
+		Store:     NewStore(),
+		tokens:    make(map[string]bool),
+	}
+
+	servicesLock.Lock()
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		services[name](s)
+	}
+	servicesLock.Unlock()
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle registers the handler for the method and the path pattern, the path parameters are specified in braces,
+// e.g. "/v1/{project_id}/vpcs/{vpc_id}". The handler registered later takes precedence.
+func (s *Server) Handle(method, pattern string, handler HandlerFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: splitPath(pattern),
+		handler:  handler,
+	})
+}
+
+// Endpoint returns the endpoint of the fake server, which ends with a slash.
+func (s *Server) Endpoint() string {
+	return s.URL + "/"
+}
+
+// AddEndpoints declares the services served by the server, which are the keys of the provider "endpoints".
+func (s *Server) AddEndpoints(keys ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.endpointKeys = append(s.endpointKeys, keys...)
+}
+
+// Endpoints returns the custom endpoints of the provider, all services point to the fake server.
+func (s *Server) Endpoints() map[string]interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	endpoints := make(map[string]interface{}, len(s.endpointKeys))
+	for _, key := range s.endpointKeys {
+		endpoints[key] = s.Endpoint()
+	}
+	return endpoints
+}
+
+// IssueToken returns a new token which is accepted by the fake server.
+func (s *Server) IssueToken() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	token := "fake-token-" + NewID()
+	s.tokens[token] = true
+	return token
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Authorization"), "SDK-HMAC-SHA256") {
+		return strings.Contains(r.Header.Get("Authorization"), "Access="+AccessKey)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.tokens[r.Header.Get("X-Auth-Token")]
+}
+
+func (s *Server) match(method, path string) (HandlerFunc, map[string]string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	segments := splitPath(path)
+	for i := len(s.routes) - 1; i >= 0; i-- {
+		r := s.routes[i]
+		if r.method != method || len(r.segments) != len(segments) {
+			continue
+		}
+
+		params := make(map[string]string)
+		matched := true
+		for j, seg := range r.segments {
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				params[strings.Trim(seg, "{}")] = segments[j]
+			} else if seg != segments[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r.handler, params
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, params := s.match(r.Method, r.URL.Path)
+	if handler == nil {
+		log.Printf("[WARN] fakecloud: no handler found for %s %s", r.Method, r.URL.Path)
+		writeJSON(w, http.StatusNotFound, ErrorBody("APIGW.0101", "The API does not exist or has not been published"))
+		return
+	}
+
+	// the token API is the only one which can be called without authorization
+	if !(r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/auth/tokens")) && !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, ErrorBody("APIGW.0301", "Incorrect IAM authentication information"))
+		return
+	}
+
+	req := &Request{
+		Request: r,
+		Params:  params,
+		writer:  w,
+	}
+	if body, err := io.ReadAll(r.Body); err == nil && len(body) > 0 {
+		if err := json.Unmarshal(body, &req.Body); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorBody("Common.0001", "invalid JSON body: "+err.Error()))
+			return
+		}
+	}
+
+	// the project ID in the path must be the one of the fake cloud
+	if projectID, ok := params["project_id"]; ok && projectID != s.ProjectID {
+		writeJSON(w, http.StatusForbidden, ErrorBody("Common.0002", "invalid project ID "+projectID))
+		return
+	}
+
+	status, body := handler(req)
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("[WARN] fakecloud: failed to write the response: %s", err)
+	}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// ErrorBody returns the common error response body of HuaweiCloud APIs.
+func ErrorBody(code, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"error_code": code,
+		"error_msg":  message,
+	}
+}
+
+// NotFound returns the 404 response of the resource.
+func NotFound(kind, id string) (int, interface{}) {
+	return http.StatusNotFound, ErrorBody("Common.0404", fmt.Sprintf("the %s %s does not exist", kind, id))
+}
+
+// NewID returns a random UUID.
+func NewID() string {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
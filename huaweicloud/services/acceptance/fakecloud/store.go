@@ -0,0 +1,162 @@
+package fakecloud
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// Object is a resource saved in the store, which is the JSON object returned by the API.
+type Object map[string]interface{}
+
+type entry struct {
+	object Object
+	// pending is the status values which will be set to the object one by one when it is read
+	pending []string
+	// deleting means the object will be removed after the pending statuses are used up
+	deleting bool
+	seq      int
+}
+
+// Store keeps the resources of the fake cloud in memory, the resources are grouped by kinds.
+type Store struct {
+	lock  sync.Mutex
+	kinds map[string]map[string]*entry
+	seq   int
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{
+		kinds: make(map[string]map[string]*entry),
+	}
+}
+
+// Put saves the object with the ID, and the object will change to the statuses one by one in the following reads.
+// The "status" field is used to save the status, the existing object with the same ID will be replaced.
+func (s *Store) Put(kind, id string, object Object, statuses ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	objects, ok := s.kinds[kind]
+	if !ok {
+		objects = make(map[string]*entry)
+		s.kinds[kind] = objects
+	}
+
+	s.seq++
+	objects[id] = &entry{
+		object:  copyObject(object),
+		pending: statuses,
+		seq:     s.seq,
+	}
+}
+
+// Get reads the object and moves it to the next status, false will be returned if the object doesn't exist.
+// The returned object is a copy, so changing it doesn't affect the store.
+func (s *Store) Get(kind, id string) (Object, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.kinds[kind][id]
+	if !ok {
+		return nil, false
+	}
+
+	if len(e.pending) > 0 {
+		e.object["status"] = e.pending[0]
+		e.pending = e.pending[1:]
+	} else if e.deleting {
+		delete(s.kinds[kind], id)
+		return nil, false
+	}
+	return copyObject(e.object), true
+}
+
+// Peek reads the object without changing its status, false will be returned if the object doesn't exist.
+func (s *Store) Peek(kind, id string) (Object, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.kinds[kind][id]
+	if !ok {
+		return nil, false
+	}
+	return copyObject(e.object), true
+}
+
+// Update changes the fields of the object, and the object will change to the statuses in the following reads.
+func (s *Store) Update(kind, id string, fields Object, statuses ...string) (Object, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.kinds[kind][id]
+	if !ok || e.deleting {
+		return nil, false
+	}
+
+	for k, v := range copyObject(fields) {
+		e.object[k] = v
+	}
+	if len(statuses) > 0 {
+		e.pending = statuses
+	}
+	return copyObject(e.object), true
+}
+
+// Delete removes the object after it is read with the statuses one by one, it is removed at once if no status
+// is specified. False will be returned if the object doesn't exist.
+func (s *Store) Delete(kind, id string, statuses ...string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.kinds[kind][id]
+	if !ok {
+		return false
+	}
+
+	if len(statuses) == 0 {
+		delete(s.kinds[kind], id)
+		return true
+	}
+	e.pending = statuses
+	e.deleting = true
+	return true
+}
+
+// List returns the copies of all objects of the kind which match the filter, the objects are sorted by the creation
+// order. The statuses of the objects are not changed by listing.
+func (s *Store) List(kind string, filter func(Object) bool) []Object {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entries := make([]*entry, 0, len(s.kinds[kind]))
+	for _, e := range s.kinds[kind] {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+
+	result := make([]Object, 0, len(entries))
+	for _, e := range entries {
+		if filter == nil || filter(e.object) {
+			result = append(result, copyObject(e.object))
+		}
+	}
+	return result
+}
+
+// copyObject returns a deep copy of the object by JSON, so that the nested fields are not shared.
+func copyObject(object Object) Object {
+	data, err := json.Marshal(object)
+	if err != nil {
+		panic(err)
+	}
+
+	var result Object
+	if err := json.Unmarshal(data, &result); err != nil {
+		panic(err)
+	}
+	return result
+}
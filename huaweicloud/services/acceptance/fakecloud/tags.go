@@ -0,0 +1,113 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// HandleTags registers the tag APIs of a resource type in the path prefix, e.g. "/v2.0/{project_id}/vpcs", the
+// resources are queried from the store by the kind.
+func (s *Server) HandleTags(prefix, kind string) {
+	tagKind := "tags/" + kind
+
+	getTags := func(id string) map[string]interface{} {
+		if tags, ok := s.Store.Peek(tagKind, id); ok {
+			return tags
+		}
+		return map[string]interface{}{}
+	}
+
+	s.Handle(http.MethodGet, prefix+"/{resource_id}/tags", func(req *Request) (int, interface{}) {
+		id := req.Params["resource_id"]
+		if _, ok := s.Store.Peek(kind, id); !ok {
+			return NotFound(kind, id)
+		}
+		return http.StatusOK, map[string]interface{}{
+			"tags": tagList(getTags(id)),
+		}
+	})
+
+	s.Handle(http.MethodPost, prefix+"/{resource_id}/tags/action", func(req *Request) (int, interface{}) {
+		id := req.Params["resource_id"]
+		if _, ok := s.Store.Peek(kind, id); !ok {
+			return NotFound(kind, id)
+		}
+
+		tags := getTags(id)
+		items, _ := req.Body["tags"].([]interface{})
+		for _, item := range items {
+			tag, _ := item.(map[string]interface{})
+			key, _ := tag["key"].(string)
+			switch req.Body["action"] {
+			case "create":
+				tags[key] = tag["value"]
+			case "delete":
+				delete(tags, key)
+			default:
+				return http.StatusBadRequest, ErrorBody("Common.0003", "invalid tag action")
+			}
+		}
+		s.Store.Put(tagKind, id, tags)
+		return http.StatusNoContent, nil
+	})
+
+	s.Handle(http.MethodDelete, prefix+"/{resource_id}/tags/{key}", func(req *Request) (int, interface{}) {
+		id := req.Params["resource_id"]
+		tags := getTags(id)
+		delete(tags, req.Params["key"])
+		s.Store.Put(tagKind, id, tags)
+		return http.StatusNoContent, nil
+	})
+}
+
+// SetTags saves the tags of the resource, which is used when the tags are specified in the creation request.
+func (s *Server) SetTags(kind, id string, tags []interface{}) {
+	tagMap := make(Object)
+	for _, item := range tags {
+		if tag, ok := item.(map[string]interface{}); ok {
+			key, _ := tag["key"].(string)
+			tagMap[key] = tag["value"]
+		}
+	}
+	s.Store.Put("tags/"+kind, id, tagMap)
+}
+
+// GetTags returns the tags of the resource in the list format.
+func (s *Server) GetTags(kind, id string) []interface{} {
+	tags, _ := s.Store.Peek("tags/"+kind, id)
+	return tagList(tags)
+}
+
+// TagStrings returns the tags of the resource in the format of "key=value", which is used by ECS and EIP.
+func (s *Server) TagStrings(kind, id string) []string {
+	result := []string{}
+	for _, item := range s.GetTags(kind, id) {
+		tag := item.(map[string]interface{})
+		result = append(result, fmt.Sprintf("%s=%v", tag["key"], tag["value"]))
+	}
+	return result
+}
+
+func tagList(tags map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, map[string]interface{}{
+			"key":   k,
+			"value": tags[k],
+		})
+	}
+	return result
+}
+
+// Now returns the current time in the format used by the APIs, e.g. 2024-01-01T00:00:00Z.
+func Now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,329 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+)
+
+const (
+	kindVpc                = "vpc"
+	kindSubnet             = "subnet"
+	kindSecurityGroup      = "security_group"
+	kindSecurityGroupRules = "security_group_rule"
+)
+
+func init() {
+	Register("vpc", registerVPC)
+}
+
+// registerVPC registers the VPC, subnet and security group APIs. A VPC becomes OK and a subnet becomes ACTIVE after
+// being read once, and a VPC can't be deleted until all subnets in it are deleted.
+func registerVPC(s *Server) {
+	s.AddEndpoints("vpc", "dns")
+
+	// the private DNS servers are used as the default DNS of the subnets
+	s.Handle(http.MethodGet, "/v2/nameservers", func(req *Request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"nameservers": []interface{}{
+				map[string]interface{}{
+					"type":   "private",
+					"region": req.URL.Query().Get("region"),
+					"ns_records": []interface{}{
+						map[string]interface{}{"address": "100.125.1.250", "priority": 1},
+						map[string]interface{}{"address": "100.125.64.250", "priority": 2},
+					},
+				},
+			},
+		}
+	})
+
+	// VPCs
+	s.Handle(http.MethodPost, "/v1/{project_id}/vpcs", s.createVpc)
+	s.Handle(http.MethodGet, "/v1/{project_id}/vpcs", func(req *Request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{
+			"vpcs": s.Store.List(kindVpc, nil),
+		}
+	})
+	s.Handle(http.MethodGet, "/v1/{project_id}/vpcs/{vpc_id}", func(req *Request) (int, interface{}) {
+		return getObject(s, kindVpc, "vpc", req.Params["vpc_id"])
+	})
+	s.Handle(http.MethodPut, "/v1/{project_id}/vpcs/{vpc_id}", func(req *Request) (int, interface{}) {
+		return updateObject(s, kindVpc, "vpc", req.Params["vpc_id"], req.BodyObject("vpc"))
+	})
+	s.Handle(http.MethodDelete, "/v1/{project_id}/vpcs/{vpc_id}", s.deleteVpc)
+	s.Handle(http.MethodGet, "/v3/{project_id}/vpc/vpcs/{vpc_id}", func(req *Request) (int, interface{}) {
+		vpc, ok := s.Store.Peek(kindVpc, req.Params["vpc_id"])
+		if !ok {
+			return NotFound(kindVpc, req.Params["vpc_id"])
+		}
+		return http.StatusOK, map[string]interface{}{"vpc": vpc}
+	})
+	s.Handle(http.MethodPut, "/v3/{project_id}/vpc/vpcs/{vpc_id}/add-extend-cidr", func(req *Request) (int, interface{}) {
+		return s.updateExtendCidrs(req, true)
+	})
+	s.Handle(http.MethodPut, "/v3/{project_id}/vpc/vpcs/{vpc_id}/remove-extend-cidr", func(req *Request) (int, interface{}) {
+		return s.updateExtendCidrs(req, false)
+	})
+	s.HandleTags("/v2.0/{project_id}/vpcs", kindVpc)
+
+	// subnets
+	s.Handle(http.MethodPost, "/v1/{project_id}/subnets", s.createSubnet)
+	s.Handle(http.MethodGet, "/v1/{project_id}/subnets", func(req *Request) (int, interface{}) {
+		vpcID := req.URL.Query().Get("vpc_id")
+		return http.StatusOK, map[string]interface{}{
+			"subnets": s.Store.List(kindSubnet, func(o Object) bool {
+				return vpcID == "" || o["vpc_id"] == vpcID
+			}),
+		}
+	})
+	s.Handle(http.MethodGet, "/v1/{project_id}/subnets/{subnet_id}", func(req *Request) (int, interface{}) {
+		return getObject(s, kindSubnet, "subnet", req.Params["subnet_id"])
+	})
+	s.Handle(http.MethodPut, "/v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}", func(req *Request) (int, interface{}) {
+		return updateObject(s, kindSubnet, "subnet", req.Params["subnet_id"], req.BodyObject("subnet"))
+	})
+	s.Handle(http.MethodDelete, "/v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}", func(req *Request) (int, interface{}) {
+		if !s.Store.Delete(kindSubnet, req.Params["subnet_id"]) {
+			return NotFound(kindSubnet, req.Params["subnet_id"])
+		}
+		return http.StatusNoContent, nil
+	})
+	s.HandleTags("/v2.0/{project_id}/subnets", kindSubnet)
+
+	// security groups
+	s.Handle(http.MethodPost, "/v3/{project_id}/vpc/security-groups", s.createSecurityGroup)
+	s.Handle(http.MethodGet, "/v3/{project_id}/vpc/security-groups/{security_group_id}", func(req *Request) (int, interface{}) {
+		return s.getSecurityGroup(req.Params["security_group_id"], true)
+	})
+	s.Handle(http.MethodPut, "/v3/{project_id}/vpc/security-groups/{security_group_id}", func(req *Request) (int, interface{}) {
+		fields := req.BodyObject("security_group")
+		fields["updated_at"] = Now()
+		if _, ok := s.Store.Update(kindSecurityGroup, req.Params["security_group_id"], fields); !ok {
+			return NotFound(kindSecurityGroup, req.Params["security_group_id"])
+		}
+		return s.getSecurityGroup(req.Params["security_group_id"], true)
+	})
+	s.Handle(http.MethodGet, "/v1/{project_id}/security-groups/{security_group_id}", func(req *Request) (int, interface{}) {
+		return s.getSecurityGroup(req.Params["security_group_id"], false)
+	})
+	s.Handle(http.MethodDelete, "/v1/{project_id}/security-groups/{security_group_id}", s.deleteSecurityGroup)
+	s.Handle(http.MethodDelete, "/v3/{project_id}/vpc/security-groups/{security_group_id}", s.deleteSecurityGroup)
+	deleteRule := func(req *Request) (int, interface{}) {
+		if !s.Store.Delete(kindSecurityGroupRules, req.Params["rule_id"]) {
+			return NotFound(kindSecurityGroupRules, req.Params["rule_id"])
+		}
+		return http.StatusNoContent, nil
+	}
+	s.Handle(http.MethodDelete, "/v1/{project_id}/security-group-rules/{rule_id}", deleteRule)
+	s.Handle(http.MethodDelete, "/v3/{project_id}/vpc/security-group-rules/{rule_id}", deleteRule)
+}
+
+// getObject returns the object wrapped by the key, e.g. {"vpc": {...}}, and the object moves to the next status.
+func getObject(s *Server, kind, key, id string) (int, interface{}) {
+	object, ok := s.Store.Get(kind, id)
+	if !ok {
+		return NotFound(kind, id)
+	}
+	return http.StatusOK, map[string]interface{}{key: object}
+}
+
+// updateObject updates the fields of the object and returns it wrapped by the key.
+func updateObject(s *Server, kind, key, id string, fields Object) (int, interface{}) {
+	object, ok := s.Store.Update(kind, id, fields)
+	if !ok {
+		return NotFound(kind, id)
+	}
+	return http.StatusOK, map[string]interface{}{key: object}
+}
+
+func (s *Server) createVpc(req *Request) (int, interface{}) {
+	opts := req.BodyObject("vpc")
+	cidr, _ := opts["cidr"].(string)
+	if _, _, err := net.ParseCIDR(cidr); cidr != "" && err != nil {
+		return http.StatusBadRequest, ErrorBody("VPC.0002", fmt.Sprintf("invalid CIDR %s", cidr))
+	}
+
+	vpc := Object{
+		"id":                    NewID(),
+		"name":                  opts["name"],
+		"description":           opts["description"],
+		"cidr":                  cidr,
+		"enterprise_project_id": defaultString(opts["enterprise_project_id"], "0"),
+		"status":                "CREATING",
+		"routes":                []interface{}{},
+		"extend_cidrs":          []interface{}{},
+		"project_id":            s.ProjectID,
+		"created_at":            Now(),
+		"updated_at":            Now(),
+	}
+	s.Store.Put(kindVpc, vpc["id"].(string), vpc, "CREATING", "OK")
+	return http.StatusOK, map[string]interface{}{"vpc": vpc}
+}
+
+func (s *Server) deleteVpc(req *Request) (int, interface{}) {
+	vpcID := req.Params["vpc_id"]
+	if _, ok := s.Store.Peek(kindVpc, vpcID); !ok {
+		return NotFound(kindVpc, vpcID)
+	}
+
+	subnets := s.Store.List(kindSubnet, func(o Object) bool {
+		return o["vpc_id"] == vpcID
+	})
+	if len(subnets) > 0 {
+		return http.StatusConflict, ErrorBody("VPC.0106", "the VPC still has subnets")
+	}
+
+	s.Store.Delete(kindVpc, vpcID)
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) updateExtendCidrs(req *Request, add bool) (int, interface{}) {
+	vpcID := req.Params["vpc_id"]
+	vpc, ok := s.Store.Peek(kindVpc, vpcID)
+	if !ok {
+		return NotFound(kindVpc, vpcID)
+	}
+
+	current := make(map[string]bool)
+	for _, v := range vpc["extend_cidrs"].([]interface{}) {
+		current[v.(string)] = true
+	}
+	opts := req.BodyObject("vpc")
+	cidrs, _ := opts["extend_cidrs"].([]interface{})
+	for _, v := range cidrs {
+		current[v.(string)] = add
+	}
+
+	extendCidrs := []interface{}{}
+	for _, v := range vpc["extend_cidrs"].([]interface{}) {
+		if current[v.(string)] {
+			extendCidrs = append(extendCidrs, v)
+			delete(current, v.(string))
+		}
+	}
+	for _, v := range cidrs {
+		if current[v.(string)] {
+			extendCidrs = append(extendCidrs, v)
+			delete(current, v.(string))
+		}
+	}
+
+	vpc, _ = s.Store.Update(kindVpc, vpcID, Object{"extend_cidrs": extendCidrs})
+	return http.StatusOK, map[string]interface{}{"vpc": vpc}
+}
+
+func (s *Server) createSubnet(req *Request) (int, interface{}) {
+	opts := req.BodyObject("subnet")
+	vpcID, _ := opts["vpc_id"].(string)
+	if _, ok := s.Store.Peek(kindVpc, vpcID); !ok {
+		return NotFound(kindVpc, vpcID)
+	}
+
+	subnet := Object{
+		"id":                NewID(),
+		"name":              opts["name"],
+		"description":       opts["description"],
+		"cidr":              opts["cidr"],
+		"gateway_ip":        opts["gateway_ip"],
+		"vpc_id":            vpcID,
+		"dhcp_enable":       opts["dhcp_enable"],
+		"ipv6_enable":       defaultValue(opts["ipv6_enable"], false),
+		"primary_dns":       opts["primary_dns"],
+		"secondary_dns":     opts["secondary_dns"],
+		"dnsList":           defaultValue(opts["dnsList"], []interface{}{}),
+		"availability_zone": opts["availability_zone"],
+		"extra_dhcp_opts":   defaultValue(opts["extra_dhcp_opts"], []interface{}{}),
+		"neutron_subnet_id": NewID(),
+		"status":            "UNKNOWN",
+	}
+	s.Store.Put(kindSubnet, subnet["id"].(string), subnet, "UNKNOWN", "ACTIVE")
+	return http.StatusOK, map[string]interface{}{"subnet": subnet}
+}
+
+func (s *Server) createSecurityGroup(req *Request) (int, interface{}) {
+	opts := req.BodyObject("security_group")
+	group := Object{
+		"id":                    NewID(),
+		"name":                  opts["name"],
+		"description":           defaultValue(opts["description"], ""),
+		"enterprise_project_id": defaultString(opts["enterprise_project_id"], "0"),
+		"project_id":            s.ProjectID,
+		"created_at":            Now(),
+		"updated_at":            Now(),
+	}
+	groupID := group["id"].(string)
+	s.Store.Put(kindSecurityGroup, groupID, group)
+
+	// the default rules allow all outbound traffic and the inbound traffic from the same security group
+	for _, ethertype := range []string{"IPv4", "IPv6"} {
+		for _, direction := range []string{"ingress", "egress"} {
+			rule := Object{
+				"id":                NewID(),
+				"security_group_id": groupID,
+				"direction":         direction,
+				"ethertype":         ethertype,
+				"protocol":          "",
+				"description":       "",
+				"action":            "allow",
+				"priority":          100,
+				"created_at":        Now(),
+				"updated_at":        Now(),
+			}
+			if direction == "ingress" {
+				rule["remote_group_id"] = groupID
+			}
+			s.Store.Put(kindSecurityGroupRules, rule["id"].(string), rule)
+		}
+	}
+
+	return s.getSecurityGroup(groupID, true)
+}
+
+// getSecurityGroup returns the security group with rules in the v1 or v3 format.
+func (s *Server) getSecurityGroup(id string, v3 bool) (int, interface{}) {
+	group, ok := s.Store.Peek(kindSecurityGroup, id)
+	if !ok {
+		return NotFound(kindSecurityGroup, id)
+	}
+
+	rules := s.Store.List(kindSecurityGroupRules, func(o Object) bool {
+		return o["security_group_id"] == id
+	})
+	if !v3 {
+		for _, rule := range rules {
+			delete(rule, "action")
+			delete(rule, "priority")
+		}
+	}
+	group["security_group_rules"] = rules
+	return http.StatusOK, map[string]interface{}{"security_group": group}
+}
+
+func (s *Server) deleteSecurityGroup(req *Request) (int, interface{}) {
+	id := req.Params["security_group_id"]
+	if !s.Store.Delete(kindSecurityGroup, id) {
+		return NotFound(kindSecurityGroup, id)
+	}
+
+	for _, rule := range s.Store.List(kindSecurityGroupRules, func(o Object) bool {
+		return o["security_group_id"] == id
+	}) {
+		s.Store.Delete(kindSecurityGroupRules, rule["id"].(string))
+	}
+	return http.StatusNoContent, nil
+}
+
+func defaultValue(v, defaultValue interface{}) interface{} {
+	if v == nil {
+		return defaultValue
+	}
+	return v
+}
+
+func defaultString(v interface{}, defaultValue string) interface{} {
+	if s, ok := v.(string); ok && s != "" {
+		return s
+	}
+	return defaultValue
+}
@@ -0,0 +1,80 @@
+package fakecloud
+
+import (
+	"testing"
+)
+
+func TestFakeCloudVpc(t *testing.T) {
+	PreCheck(t)
+	t.Parallel()
+
+	s := NewServer(t)
+	p := s.Provider(t, nil)
+
+	vpc := NewResourceTest(t, p, "huaweicloud_vpc")
+	vpc.Apply(map[string]interface{}{
+		"name": "vpc-test",
+		"cidr": "192.168.0.0/16",
+		"tags": map[string]interface{}{
+			"foo": "bar",
+		},
+	})
+	assertAttr(t, vpc, "status", "OK")
+	assertAttr(t, vpc, "tags.foo", "bar")
+
+	vpc.Apply(map[string]interface{}{
+		"name":        "vpc-test-update",
+		"cidr":        "192.168.0.0/16",
+		"description": "updated by fakecloud",
+		"tags": map[string]interface{}{
+			"key": "value",
+		},
+	})
+	assertAttr(t, vpc, "name", "vpc-test-update")
+	assertAttr(t, vpc, "tags.%", "1")
+	assertAttr(t, vpc, "tags.key", "value")
+
+	subnet := NewResourceTest(t, p, "huaweicloud_vpc_subnet")
+	subnet.Apply(map[string]interface{}{
+		"name":        "subnet-test",
+		"cidr":        "192.168.0.0/24",
+		"gateway_ip":  "192.168.0.1",
+		"vpc_id":      vpc.State.ID,
+		"primary_dns": "100.125.1.250",
+	})
+	assertAttr(t, subnet, "vpc_id", vpc.State.ID)
+
+	imported := subnet.Import(subnet.State.ID)
+	if imported.Attributes["cidr"] != "192.168.0.0/24" {
+		t.Fatalf("unexpected CIDR of the imported subnet: %s", imported.Attributes["cidr"])
+	}
+
+	subnet.Destroy()
+	vpc.Destroy()
+}
+
+func TestFakeCloudSecurityGroup(t *testing.T) {
+	PreCheck(t)
+	t.Parallel()
+
+	s := NewServer(t)
+	p := s.Provider(t, nil)
+
+	secgroup := NewResourceTest(t, p, "huaweicloud_networking_secgroup")
+	secgroup.Apply(map[string]interface{}{
+		"name":                 "secgroup-test",
+		"description":          "created by fakecloud",
+		"delete_default_rules": true,
+	})
+	assertAttr(t, secgroup, "description", "created by fakecloud")
+	assertAttr(t, secgroup, "rules.#", "0")
+
+	secgroup.Destroy()
+}
+
+func assertAttr(t *testing.T, rt *ResourceTest, key, expected string) {
+	t.Helper()
+	if actual := rt.Attr(key); actual != expected {
+		t.Fatalf("expected %s to be %q, but got %q", key, expected, actual)
+	}
+}
@@ -0,0 +1,108 @@
+package obs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+func TestAccObsBucketInventory_basic(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket_inventory.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketInventory_basic(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "configuration_id", "daily-report"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "frequency", "Daily"),
+					resource.TestCheckResourceAttr(resourceName, "filter_prefix", "archive/"),
+					resource.TestCheckResourceAttr(resourceName, "included_object_versions", "Current"),
+					resource.TestCheckResourceAttr(resourceName, "optional_fields.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "destination.0.bucket",
+						"huaweicloud_obs_bucket.report", "bucket"),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.prefix", "inventory/"),
+					resource.TestCheckResourceAttr(resourceName, "destination.0.format", "CSV"),
+				),
+			},
+			{
+				Config: testAccObsBucketInventory_update(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "frequency", "Weekly"),
+					resource.TestCheckResourceAttr(resourceName, "filter_prefix", ""),
+					resource.TestCheckResourceAttr(resourceName, "included_object_versions", "All"),
+					resource.TestCheckResourceAttr(resourceName, "optional_fields.#", "0"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccObsBucketInventory_base(randInt int) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "test" {
+  bucket = "tf-test-bucket-%[1]d"
+}
+
+resource "huaweicloud_obs_bucket" "report" {
+  bucket        = "tf-test-report-%[1]d"
+  force_destroy = true
+}
+`, randInt)
+}
+
+func testAccObsBucketInventory_basic(randInt int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_inventory" "test" {
+  bucket           = huaweicloud_obs_bucket.test.bucket
+  configuration_id = "daily-report"
+  frequency        = "Daily"
+  filter_prefix    = "archive/"
+  optional_fields  = ["Size", "StorageClass"]
+
+  destination {
+    bucket = huaweicloud_obs_bucket.report.bucket
+    prefix = "inventory/"
+  }
+}
+`, testAccObsBucketInventory_base(randInt))
+}
+
+func testAccObsBucketInventory_update(randInt int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_inventory" "test" {
+  bucket                   = huaweicloud_obs_bucket.test.bucket
+  configuration_id         = "daily-report"
+  frequency                = "Weekly"
+  enabled                  = false
+  included_object_versions = "All"
+
+  destination {
+    bucket = huaweicloud_obs_bucket.report.bucket
+    prefix = "inventory/"
+  }
+}
+`, testAccObsBucketInventory_base(randInt))
+}
@@ -0,0 +1,100 @@
+package obs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+func TestAccObsBucketNotification_basic(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket_notification.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketNotification_basic(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "topic.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "topic.0.topic_urn",
+						"huaweicloud_smn_topic.test", "topic_urn"),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.events.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.events.0", "ObjectCreated:*"),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.filter_prefix", "ingest/"),
+					resource.TestCheckResourceAttrSet(resourceName, "topic.0.id"),
+				),
+			},
+			{
+				Config: testAccObsBucketNotification_update(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "topic.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.id", "created-events"),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.events.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.filter_prefix", ""),
+					resource.TestCheckResourceAttr(resourceName, "topic.0.filter_suffix", ".csv"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccObsBucketNotification_base(randInt int) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "test" {
+  bucket = "tf-test-bucket-%[1]d"
+}
+
+resource "huaweicloud_smn_topic" "test" {
+  name                     = "tf-test-topic-%[1]d"
+  services_publish_allowed = "obs"
+}
+`, randInt)
+}
+
+func testAccObsBucketNotification_basic(randInt int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_notification" "test" {
+  bucket = huaweicloud_obs_bucket.test.bucket
+
+  topic {
+    topic_urn     = huaweicloud_smn_topic.test.topic_urn
+    events        = ["ObjectCreated:*"]
+    filter_prefix = "ingest/"
+  }
+}
+`, testAccObsBucketNotification_base(randInt))
+}
+
+func testAccObsBucketNotification_update(randInt int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_notification" "test" {
+  bucket = huaweicloud_obs_bucket.test.bucket
+
+  topic {
+    id            = "created-events"
+    topic_urn     = huaweicloud_smn_topic.test.topic_urn
+    events        = ["ObjectCreated:Put", "ObjectCreated:CompleteMultipartUpload"]
+    filter_suffix = ".csv"
+  }
+}
+`, testAccObsBucketNotification_base(randInt))
+}
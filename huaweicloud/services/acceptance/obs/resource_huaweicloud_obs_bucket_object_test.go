@@ -1,6 +1,7 @@
 package obs
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"testing"
@@ -93,6 +94,97 @@ func TestAccObsBucketObject_content(t *testing.T) {
 	})
 }
 
+func TestAccObsBucketObject_multipart(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket_object.object"
+
+	tmpFile, err := os.CreateTemp("", "tf-acc-obs-obj-multipart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	// write 3 MB data to the tempfile, which is uploaded in 3 parts
+	err = os.WriteFile(tmpFile.Name(), bytes.Repeat([]byte("a"), 3*1024*1024), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketObjectConfig_multipart(rInt, tmpFile.Name(), "v1", "max-age=60"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckObsBucketObjectExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "size", "3145728"),
+					resource.TestCheckResourceAttr(resourceName, "cache_control", "max-age=60"),
+					resource.TestCheckResourceAttr(resourceName, "content_disposition", "attachment"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.owner", "terraform"),
+					resource.TestCheckResourceAttr(resourceName, "source_hash", "v1"),
+				),
+			},
+			{
+				PreConfig: func() {
+					err := os.WriteFile(tmpFile.Name(), bytes.Repeat([]byte("b"), 2*1024*1024), 0600)
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccObsBucketObjectConfig_multipart(rInt, tmpFile.Name(), "v2", "no-cache"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "size", "2097152"),
+					resource.TestCheckResourceAttr(resourceName, "cache_control", "no-cache"),
+					resource.TestCheckResourceAttr(resourceName, "source_hash", "v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccObsBucketObject_metadata(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket_object.object"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketObjectConfig_metadata(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckObsBucketObjectExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "cache_control", "max-age=3600"),
+					resource.TestCheckResourceAttr(resourceName, "content_language", "en-US"),
+					resource.TestCheckResourceAttr(resourceName, "website_redirect", "/index.html"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.owner", "terraform"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.build-id", "42"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccObsBucketObjecImportStateIdFunc(),
+				ImportStateVerifyIgnore: []string{
+					"content",
+				},
+			},
+		},
+	})
+}
+
 func testAccCheckObsBucketObjectDestroy(s *terraform.State) error {
 	conf := acceptance.TestAccProvider.Meta().(*config.Config)
 	obsClient, err := conf.ObjectStorageClient(acceptance.HW_REGION_NAME)
@@ -238,3 +330,48 @@ encryption   = true
 }
 `, randInt, source)
 }
+
+func testAccObsBucketObjectConfig_multipart(randInt int, source, sourceHash, cacheControl string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "object_bucket" {
+  bucket = "tf-acc-test-bucket-%d"
+}
+
+resource "huaweicloud_obs_bucket_object" "object" {
+  bucket              = huaweicloud_obs_bucket.object_bucket.bucket
+  key                 = "test-key"
+  source              = "%s"
+  source_hash         = "%s"
+  part_size           = 1
+  concurrency         = 2
+  cache_control       = "%s"
+  content_disposition = "attachment"
+
+  metadata = {
+    owner = "terraform"
+  }
+}
+`, randInt, source, sourceHash, cacheControl)
+}
+
+func testAccObsBucketObjectConfig_metadata(randInt int) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "object_bucket" {
+  bucket = "tf-acc-test-bucket-%d"
+}
+
+resource "huaweicloud_obs_bucket_object" "object" {
+  bucket           = huaweicloud_obs_bucket.object_bucket.bucket
+  key              = "test-key"
+  content          = "some_bucket_content"
+  cache_control    = "max-age=3600"
+  content_language = "en-US"
+  website_redirect = "/index.html"
+
+  metadata = {
+    owner    = "terraform"
+    build-id = "42"
+  }
+}
+`, randInt)
+}
@@ -0,0 +1,155 @@
+package obs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+func TestAccObsBucketSync_basic(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket_sync.test"
+
+	sourceDir := t.TempDir()
+	writeFile := func(name, content string) {
+		filePath := filepath.Join(sourceDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("index.html", "<html>hello</html>")
+	writeFile("css/site.css", "body {}")
+	writeFile("model/weights.bin", "weights")
+	writeFile("tmp/cache.tmp", "cache")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketSyncDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketSync_basic(rInt, sourceDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "files.%", "3"),
+					resource.TestCheckResourceAttrSet(resourceName, "files.site/index.html"),
+					resource.TestCheckResourceAttrSet(resourceName, "files.site/css/site.css"),
+					resource.TestCheckResourceAttrSet(resourceName, "files.site/model/weights.bin"),
+					testAccCheckObsObjectContentType(resourceName, "site/index.html", "text/html"),
+					testAccCheckObsObjectContentType(resourceName, "site/model/weights.bin",
+						"application/octet-stream"),
+				),
+			},
+			{
+				PreConfig: func() {
+					writeFile("index.html", "<html>updated</html>")
+					if err := os.Remove(filepath.Join(sourceDir, "css", "site.css")); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccObsBucketSync_basic(rInt, sourceDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "files.%", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "files.site/index.html"),
+					resource.TestCheckNoResourceAttr(resourceName, "files.site/css/site.css"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckObsObjectContentType(n, key, contentType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conf := acceptance.TestAccProvider.Meta().(*config.Config)
+		obsClient, err := conf.ObjectStorageClient(acceptance.HW_REGION_NAME)
+		if err != nil {
+			return fmt.Errorf("Error creating OBS client: %s", err)
+		}
+
+		bucket := rs.Primary.Attributes["bucket"]
+		resp, err := obsClient.GetObjectMetadata(&obs.GetObjectMetadataInput{
+			Bucket: bucket,
+			Key:    key,
+		})
+		if err != nil {
+			return fmt.Errorf("error fetching object %s in bucket %s: %s", key, bucket, err)
+		}
+		if resp.ContentType != contentType {
+			return fmt.Errorf("the content type of %s is %s, expected %s", key, resp.ContentType, contentType)
+		}
+		return nil
+	}
+}
+
+func testAccCheckObsBucketSyncDestroy(s *terraform.State) error {
+	conf := acceptance.TestAccProvider.Meta().(*config.Config)
+	obsClient, err := conf.ObjectStorageClient(acceptance.HW_REGION_NAME)
+	if err != nil {
+		return fmt.Errorf("Error creating OBS client: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "huaweicloud_obs_bucket_sync" {
+			continue
+		}
+
+		bucket := rs.Primary.Attributes["bucket"]
+		input := &obs.ListObjectsInput{}
+		input.Bucket = bucket
+		input.Prefix = rs.Primary.Attributes["prefix"]
+
+		resp, err := obsClient.ListObjects(input)
+		if err != nil {
+			if obsError, ok := err.(obs.ObsError); ok && obsError.Code == "NoSuchBucket" {
+				return nil
+			}
+			return fmt.Errorf("Error listing objects of OBS bucket %s: %s", bucket, err)
+		}
+		if len(resp.Contents) > 0 {
+			return fmt.Errorf("the synced objects still exist in bucket %s", bucket)
+		}
+	}
+
+	return nil
+}
+
+func testAccObsBucketSync_basic(randInt int, sourceDir string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "test" {
+  bucket = "tf-acc-test-bucket-%d"
+}
+
+resource "huaweicloud_obs_bucket_sync" "test" {
+  bucket        = huaweicloud_obs_bucket.test.bucket
+  prefix        = "site"
+  source_dir    = "%s"
+  exclude       = ["tmp"]
+  delete_stale  = true
+  cache_control = "max-age=300"
+
+  content_types = {
+    bin = "application/octet-stream"
+  }
+}
+`, randInt, sourceDir)
+}
@@ -467,6 +467,48 @@ func TestAccObsBucket_userDomainNames(t *testing.T) {
 	})
 }
 
+func TestAccObsBucket_objectLock(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket.bucket"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketConfigWithObjectLock(rInt, "days = 1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckObsBucketExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "object_lock_enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "versioning", "true"),
+					resource.TestCheckResourceAttr(resourceName, "object_lock_retention.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "object_lock_retention.0.days", "1"),
+				),
+			},
+			{
+				Config: testAccObsBucketConfigWithObjectLock(rInt, "years = 1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "object_lock_retention.0.days", "0"),
+					resource.TestCheckResourceAttr(resourceName, "object_lock_retention.0.years", "1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"acl",
+					"force_destroy",
+				},
+			},
+		},
+	})
+}
+
 func testAccCheckObsBucketDestroy(s *terraform.State) error {
 	conf := acceptance.TestAccProvider.Meta().(*config.Config)
 	obsClient, err := conf.ObjectStorageClient(acceptance.HW_REGION_NAME)
@@ -885,3 +927,17 @@ resource "huaweicloud_obs_bucket" "bucket" {
 }
 `, randInt)
 }
+
+func testAccObsBucketConfigWithObjectLock(randInt int, retention string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "bucket" {
+  bucket              = "tf-test-bucket-%d"
+  versioning          = true
+  object_lock_enabled = true
+
+  object_lock_retention {
+    %s
+  }
+}
+`, randInt, retention)
+}
@@ -2,6 +2,7 @@ package rds
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -371,6 +372,73 @@ func TestAccRdsInstance_restore_pg(t *testing.T) {
 	})
 }
 
+func TestAccRdsInstance_restore_time(t *testing.T) {
+	var instance instances.RdsInstanceResponse
+	name := acceptance.RandomAccResourceName()
+	resourceType := "huaweicloud_rds_instance"
+	resourceName := "huaweicloud_rds_instance.test_backup"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { acceptance.TestAccPreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckRdsInstanceDestroy(resourceType),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRdsInstance_restore_time_invalid(name),
+				ExpectError: regexp.MustCompile("is not in the restorable time windows|no restorable time window"),
+			},
+			{
+				Config: testAccRdsInstance_restore_time(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRdsInstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "db.0.version", "8.0"),
+					resource.TestCheckResourceAttrSet(resourceName, "restore.0.restore_time"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRdsInstance_upgrade_pg(t *testing.T) {
+	var instance instances.RdsInstanceResponse
+	name := acceptance.RandomAccResourceName()
+	resourceType := "huaweicloud_rds_instance"
+	resourceName := "huaweicloud_rds_instance.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { acceptance.TestAccPreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckRdsInstanceDestroy(resourceType),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRdsInstance_upgrade_pg(name, "12", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRdsInstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "db.0.version", "12"),
+				),
+			},
+			{
+				Config:      testAccRdsInstance_upgrade_pg(name, "11", true),
+				ExpectError: regexp.MustCompile("can not be downgraded"),
+			},
+			{
+				Config:      testAccRdsInstance_upgrade_pg(name, "14", false),
+				ExpectError: regexp.MustCompile("allow_new_instance must be true"),
+			},
+			{
+				Config: testAccRdsInstance_upgrade_pg(name, "14", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRdsInstanceExists(resourceName, &instance),
+					resource.TestCheckResourceAttr(resourceName, "db.0.version", "14"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_check_report.0.target_version", "14"),
+					resource.TestCheckResourceAttr(resourceName, "upgrade_check_report.0.result", "success"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckRdsInstanceDestroy(rsType string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		config := acceptance.TestAccProvider.Meta().(*config.Config)
@@ -1202,3 +1270,106 @@ resource "huaweicloud_rds_instance" "test_backup" {
 }
 `, testBackup_pg_basic(name), name, pwd)
 }
+
+func testAccRdsInstance_restore_time_invalid(name string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "huaweicloud_rds_instance" "test_backup" {
+  name              = "%[2]s"
+  flavor            = data.huaweicloud_rds_flavors.test.flavors[0].name
+  security_group_id = data.huaweicloud_networking_secgroup.test.id
+  subnet_id         = data.huaweicloud_vpc_subnet.test.id
+  vpc_id            = data.huaweicloud_vpc.test.id
+  availability_zone = slice(sort(data.huaweicloud_rds_flavors.test.flavors[0].availability_zones), 0, 1)
+
+  restore {
+    instance_id  = huaweicloud_rds_backup.test.instance_id
+    restore_time = "2000-01-01T00:00:00Z"
+  }
+
+  db {
+    password = "Huangwei!120521"
+    type     = "MySQL"
+    version  = "8.0"
+  }
+
+  volume {
+    type = "CLOUDSSD"
+    size = 50
+  }
+}
+`, testBackup_mysql_basic(name), name)
+}
+
+// the end time of the backup is in the format of "yyyy-mm-ddThh:mm:ss+hhmm", which is converted to RFC3339
+func testAccRdsInstance_restore_time(name string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "huaweicloud_rds_instance" "test_backup" {
+  name              = "%[2]s"
+  flavor            = data.huaweicloud_rds_flavors.test.flavors[0].name
+  security_group_id = data.huaweicloud_networking_secgroup.test.id
+  subnet_id         = data.huaweicloud_vpc_subnet.test.id
+  vpc_id            = data.huaweicloud_vpc.test.id
+  availability_zone = slice(sort(data.huaweicloud_rds_flavors.test.flavors[0].availability_zones), 0, 1)
+
+  restore {
+    instance_id  = huaweicloud_rds_backup.test.instance_id
+    restore_time = replace(huaweicloud_rds_backup.test.end_time, "/([+-]\\d{2})(\\d{2})$/", "$1:$2")
+  }
+
+  db {
+    password = "Huangwei!120521"
+    type     = "MySQL"
+    version  = "8.0"
+  }
+
+  volume {
+    type = "CLOUDSSD"
+    size = 50
+  }
+}
+`, testBackup_mysql_basic(name), name)
+}
+
+func testAccRdsInstance_upgrade_pg(name, version string, allowNewInstance bool) string {
+	return fmt.Sprintf(`
+%[1]s
+
+data "huaweicloud_rds_flavors" "test" {
+  db_type       = "PostgreSQL"
+  db_version    = "12"
+  instance_mode = "single"
+  group_type    = "dedicated"
+}
+
+resource "huaweicloud_rds_instance" "test" {
+  name              = "%[2]s"
+  flavor            = data.huaweicloud_rds_flavors.test.flavors[0].name
+  availability_zone = slice(sort(data.huaweicloud_rds_flavors.test.flavors[0].availability_zones), 0, 1)
+  security_group_id = data.huaweicloud_networking_secgroup.test.id
+  subnet_id         = data.huaweicloud_vpc_subnet.test.id
+  vpc_id            = data.huaweicloud_vpc.test.id
+
+  db {
+    password = "Huangwei!120521"
+    type     = "PostgreSQL"
+    version  = "%[3]s"
+  }
+
+  volume {
+    type = "CLOUDSSD"
+    size = 50
+  }
+
+  major_version_upgrade {
+    allow_new_instance         = %[4]t
+    is_change_private_ip       = true
+    statistics_collection_mode = "before_change_private_ip"
+    delete_original_instance   = true
+  }
+}
+`, testAccRdsInstance_base(), name, version, allowNewInstance)
+}
@@ -0,0 +1,68 @@
+package sweep
+
+import (
+	"strings"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+const cceClustersPath = "api/v3/projects/{project_id}/clusters"
+
+func init() {
+	// the ID of the node resource is in the format of {cluster_id}/{node_id}
+	Register(&Sweeper{
+		Name:    "huaweicloud_cce_node",
+		Service: "cce",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			clusters, err := listCCEClusters(client)
+			if err != nil {
+				return nil, err
+			}
+
+			var resources []Resource
+			for _, cluster := range clusters {
+				helper := httphelper.New(client).URI(cceClustersPath + "/" + cluster.ID + "/nodes")
+				nodes, err := ListJSON(helper, "items", "metadata.name", "metadata.uid")
+				if err != nil {
+					return nil, err
+				}
+				for _, node := range nodes {
+					node.ID = cluster.ID + "/" + node.ID
+					resources = append(resources, node)
+				}
+			}
+			return resources, nil
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", cceNodePath(r.ID), nil)
+		},
+		Exists: func(client *golangsdk.ServiceClient, r Resource) (bool, error) {
+			return ExistsByGet(cceNodePath(r.ID))(client, r)
+		},
+	})
+
+	Register(&Sweeper{
+		Name:         "huaweicloud_cce_cluster",
+		Dependencies: []string{"huaweicloud_cce_node"},
+		Service:      "cce",
+		List:         listCCEClusters,
+		// the storage and network interfaces created by the cluster are deleted with it
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			path := cceClustersPath + "/" + r.ID + "?delete_efs=true&delete_eni=true&delete_evs=true&delete_obs=true" +
+				"&delete_sfs=true"
+			return Request(client, "DELETE", path, nil)
+		},
+		Exists: ExistsByGet(cceClustersPath + "/{id}"),
+	})
+}
+
+func listCCEClusters(client *golangsdk.ServiceClient) ([]Resource, error) {
+	return ListJSON(httphelper.New(client).URI(cceClustersPath), "items", "metadata.name", "metadata.uid")
+}
+
+func cceNodePath(id string) string {
+	parts := strings.SplitN(id, "/", 2)
+	return cceClustersPath + "/" + parts[0] + "/nodes/" + parts[len(parts)-1]
+}
@@ -0,0 +1,23 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(&Sweeper{
+		Name:    "huaweicloud_dcs_instance",
+		Service: "dcs",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v2/{project_id}/instances").
+				OffsetPager("instances", "offset", "limit", 100)
+			return ListJSON(helper, "instances", "name", "instance_id")
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v2/{project_id}/instances/"+r.ID, nil)
+		},
+		Exists: ExistsByGet("v2/{project_id}/instances/{id}"),
+	})
+}
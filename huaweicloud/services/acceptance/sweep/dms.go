@@ -0,0 +1,30 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(newDMSSweeper("huaweicloud_dms_kafka_instance", "kafka"))
+	Register(newDMSSweeper("huaweicloud_dms_rabbitmq_instance", "rabbitmq"))
+}
+
+// newDMSSweeper returns the sweeper of the DMS instances, the Kafka and RabbitMQ instances are listed by the engine.
+func newDMSSweeper(name, engine string) *Sweeper {
+	return &Sweeper{
+		Name:    name,
+		Service: "dmsv2",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v2/{project_id}/instances").
+				Query(map[string]any{"engine": engine}).
+				OffsetPager("instances", "offset", "limit", 50)
+			return ListJSON(helper, "instances", "name", "instance_id")
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v2/{project_id}/instances/"+r.ID, nil)
+		},
+		Exists: ExistsByGet("v2/{project_id}/instances/{id}"),
+	}
+}
@@ -0,0 +1,29 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(&Sweeper{
+		Name:    "huaweicloud_compute_instance",
+		Service: "ecs",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v1/{project_id}/cloudservers/detail").
+				PageSizePager("servers", "offset", "limit", 100)
+			return ListJSON(helper, "servers", "name", "id")
+		},
+		// the EIPs and data volumes are released with the instance
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "POST", "v1/{project_id}/cloudservers/delete", map[string]interface{}{
+				"servers":         []map[string]interface{}{{"id": r.ID}},
+				"delete_publicip": true,
+				"delete_volume":   true,
+			})
+		},
+		// the instance can be queried in DELETED status for a while after it's deleted
+		Exists: ExistsByStatus("v1/{project_id}/cloudservers/{id}", "server.status", "DELETED"),
+	})
+}
@@ -0,0 +1,36 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(&Sweeper{
+		Name:         "huaweicloud_vpc_eip",
+		Dependencies: []string{"huaweicloud_compute_instance", "huaweicloud_elb_loadbalancer"},
+		Service:      "vpc",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v1/{project_id}/publicips").
+				MarkerPager("publicips", "publicips[-1].id", "marker")
+			resources, err := ListJSON(helper, "publicips", "alias", "id")
+			if err != nil {
+				return nil, err
+			}
+
+			// the EIP name is optional, and the bandwidth name is always set
+			for i, r := range resources {
+				if r.Name == "" {
+					resources[i].Name = r.Data.Get("bandwidth_name").String()
+				}
+			}
+			return resources, nil
+		},
+		// the dedicated bandwidth is released with the EIP
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v1/{project_id}/publicips/"+r.ID, nil)
+		},
+		Exists: ExistsByGet("v1/{project_id}/publicips/{id}"),
+	})
+}
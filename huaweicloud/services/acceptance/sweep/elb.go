@@ -0,0 +1,24 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(&Sweeper{
+		Name:    "huaweicloud_elb_loadbalancer",
+		Service: "elbv3",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v3/{project_id}/elb/loadbalancers").
+				MarkerPager("loadbalancers", "page_info.next_marker", "marker")
+			return ListJSON(helper, "loadbalancers", "name", "id")
+		},
+		// the listeners, pools and members are deleted with the load balancer
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v3/{project_id}/elb/loadbalancers/"+r.ID+"/force-elb", nil)
+		},
+		Exists: ExistsByGet("v3/{project_id}/elb/loadbalancers/{id}"),
+	})
+}
@@ -0,0 +1,24 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(&Sweeper{
+		Name:         "huaweicloud_evs_volume",
+		Dependencies: []string{"huaweicloud_compute_instance"},
+		Service:      "evs",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v2/{project_id}/cloudvolumes/detail").
+				OffsetPager("volumes", "offset", "limit", 100)
+			return ListJSON(helper, "volumes", "name", "id")
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v2/{project_id}/cloudvolumes/"+r.ID+"?cascade=true", nil)
+		},
+		Exists: ExistsByGet("v2/{project_id}/cloudvolumes/{id}"),
+	})
+}
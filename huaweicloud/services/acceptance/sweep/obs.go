@@ -0,0 +1,85 @@
+package sweep
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+)
+
+func init() {
+	resource.AddTestSweepers("huaweicloud_obs_bucket", &resource.Sweeper{
+		Name: "huaweicloud_obs_bucket",
+		F:    sweepOBSBuckets,
+	})
+}
+
+// sweepOBSBuckets deletes the buckets in the region, the buckets must be emptied before being deleted.
+func sweepOBSBuckets(region string) error {
+	cfg, err := SharedConfigForRegion(region)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.ObjectStorageClient(region)
+	if err != nil {
+		return fmt.Errorf("error creating OBS client: %s", err)
+	}
+
+	output, err := client.ListBuckets(&obs.ListBucketsInput{QueryLocation: true})
+	if err != nil {
+		return fmt.Errorf("error listing OBS buckets: %s", err)
+	}
+
+	var mErr *multierror.Error
+	for _, bucket := range output.Buckets {
+		if bucket.Location != region || !IsSweepable(bucket.Name) {
+			continue
+		}
+		if DryRun() {
+			log.Printf("[INFO] [dry-run] huaweicloud_obs_bucket %s would be deleted in region %s", bucket.Name, region)
+			continue
+		}
+
+		log.Printf("[INFO] deleting huaweicloud_obs_bucket %s in region %s", bucket.Name, region)
+		if err := emptyOBSBucket(client, bucket.Name); err != nil {
+			mErr = multierror.Append(mErr, err)
+			continue
+		}
+		if _, err := client.DeleteBucket(bucket.Name); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("error deleting OBS bucket %s: %s", bucket.Name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// emptyOBSBucket deletes all the object versions and delete markers in the bucket, 1000 at a time.
+func emptyOBSBucket(client *obs.ObsClient, bucket string) error {
+	for {
+		versions, err := client.ListVersions(&obs.ListVersionsInput{Bucket: bucket})
+		if err != nil {
+			return fmt.Errorf("error listing the objects of OBS bucket %s: %s", bucket, err)
+		}
+
+		objects := make([]obs.ObjectToDelete, 0, len(versions.Versions)+len(versions.DeleteMarkers))
+		for _, v := range versions.Versions {
+			objects = append(objects, obs.ObjectToDelete{Key: v.Key, VersionId: v.VersionId})
+		}
+		for _, v := range versions.DeleteMarkers {
+			objects = append(objects, obs.ObjectToDelete{Key: v.Key, VersionId: v.VersionId})
+		}
+		if len(objects) == 0 {
+			return nil
+		}
+
+		output, err := client.DeleteObjects(&obs.DeleteObjectsInput{Bucket: bucket, Objects: objects})
+		if err != nil {
+			return fmt.Errorf("error deleting the objects of OBS bucket %s: %s", bucket, err)
+		}
+		if len(output.Errors) > 0 {
+			return fmt.Errorf("error deleting the objects of OBS bucket %s: %#v", bucket, output.Errors)
+		}
+	}
+}
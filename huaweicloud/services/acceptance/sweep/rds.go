@@ -0,0 +1,31 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	Register(&Sweeper{
+		Name:    "huaweicloud_rds_instance",
+		Service: "rds",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v3/{project_id}/instances").
+				OffsetPager("instances", "offset", "limit", 100)
+			return ListJSON(helper, "instances", "name", "id")
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v3/{project_id}/instances/"+r.ID, nil)
+		},
+		// there is no API to query a single instance, so it's queried by the list API
+		Exists: func(client *golangsdk.ServiceClient, r Resource) (bool, error) {
+			result, err := httphelper.New(client).Method("GET").URI("v3/{project_id}/instances").
+				Query(map[string]any{"id": r.ID}).Request().Result()
+			if err != nil {
+				return false, err
+			}
+			return len(result.Get("instances").Array()) > 0, nil
+		},
+	})
+}
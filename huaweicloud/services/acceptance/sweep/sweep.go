@@ -0,0 +1,243 @@
+// Package sweep registers the sweepers which destroy the resources left by the failed acceptance tests, the sweepers
+// of all services are registered in one package so that the dependencies between them can be resolved.
+//
+// The sweepers only delete the resources whose names are generated by acceptance.RandomAccResourceName and
+// acceptance.RandomAccResourceNameWithDash, and the resources are only listed if HW_SWEEP_DRY_RUN is set to true.
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/tidwall/gjson"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+const (
+	// dryRunEnv makes the sweepers list the resources to be deleted without deleting them
+	dryRunEnv = "HW_SWEEP_DRY_RUN"
+
+	deleteTimeout = 30 * time.Minute
+	pollInterval  = 10 * time.Second
+)
+
+// namePrefixes are the prefixes of the names generated by the acceptance tests
+var namePrefixes = []string{"tf_test_", "tf-test-"}
+
+var (
+	sharedConfigs    = make(map[string]*config.Config)
+	sharedConfigLock sync.Mutex
+)
+
+// Resource is a resource found by a sweeper, Data is the raw object returned by the list API.
+type Resource struct {
+	ID   string
+	Name string
+	Data gjson.Result
+}
+
+// Sweeper describes how to list and delete the resources of a type with the service client.
+type Sweeper struct {
+	// Name is the resource type, e.g. huaweicloud_compute_instance
+	Name string
+	// Dependencies is the sweepers which must be run before this one
+	Dependencies []string
+	// Service is the catalog name used to create the client
+	Service string
+
+	List   func(client *golangsdk.ServiceClient) ([]Resource, error)
+	Delete func(client *golangsdk.ServiceClient, r Resource) error
+	// Exists checks whether the resource is still there after the deletion, the deletion is not waited if it's nil
+	Exists func(client *golangsdk.ServiceClient, r Resource) (bool, error)
+}
+
+// Register registers the sweeper to the sweeper runner of the plugin SDK.
+func Register(s *Sweeper) {
+	resource.AddTestSweepers(s.Name, &resource.Sweeper{
+		Name:         s.Name,
+		Dependencies: s.Dependencies,
+		F:            s.sweep,
+	})
+}
+
+func (s *Sweeper) sweep(region string) error {
+	cfg, err := SharedConfigForRegion(region)
+	if err != nil {
+		return err
+	}
+	client, err := cfg.NewServiceClient(s.Service, region)
+	if err != nil {
+		return fmt.Errorf("error creating %s client: %s", s.Service, err)
+	}
+
+	resources, err := s.List(client)
+	if err != nil {
+		return fmt.Errorf("error listing %s: %s", s.Name, err)
+	}
+
+	var mErr *multierror.Error
+	for _, r := range resources {
+		if !IsSweepable(r.Name) {
+			continue
+		}
+		if DryRun() {
+			log.Printf("[INFO] [dry-run] %s %s (%s) would be deleted in region %s", s.Name, r.Name, r.ID, region)
+			continue
+		}
+
+		log.Printf("[INFO] deleting %s %s (%s) in region %s", s.Name, r.Name, r.ID, region)
+		if err := s.Delete(client, r); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("error deleting %s %s (%s): %s", s.Name, r.Name, r.ID, err))
+			continue
+		}
+		if s.Exists != nil {
+			err := WaitForDeleted(func() (bool, error) { return s.Exists(client, r) })
+			if err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("error waiting for %s %s (%s) to be deleted: %s",
+					s.Name, r.Name, r.ID, err))
+			}
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SharedConfigForRegion returns the provider configuration of the region, which is configured by the same
+// environment variables as the acceptance tests.
+func SharedConfigForRegion(region string) (*config.Config, error) {
+	sharedConfigLock.Lock()
+	defer sharedConfigLock.Unlock()
+
+	if cfg, ok := sharedConfigs[region]; ok {
+		return cfg, nil
+	}
+
+	provider := huaweicloud.Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"region": region,
+	}))
+	if diags.HasError() {
+		return nil, fmt.Errorf("error configuring the provider for region %s: %v", region, diags)
+	}
+
+	cfg := provider.Meta().(*config.Config)
+	sharedConfigs[region] = cfg
+	return cfg, nil
+}
+
+// IsSweepable returns whether the resource is created by the acceptance tests according to its name.
+func IsSweepable(name string) bool {
+	for _, prefix := range namePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DryRun returns whether the sweepers only list the resources to be deleted.
+func DryRun() bool {
+	dryRun, _ := strconv.ParseBool(os.Getenv(dryRunEnv))
+	return dryRun
+}
+
+// WaitForDeleted polls the exists function until the resource is deleted.
+func WaitForDeleted(exists func() (bool, error)) error {
+	deadline := time.Now().Add(deleteTimeout)
+	for {
+		found, err := exists()
+		if err != nil {
+			return err
+		}
+		if !found {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout after %s", deleteTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// ListJSON lists the objects with the pager of httphelper, the objects are returned with the name and ID found by the
+// paths of gjson.
+func ListJSON(helper *httphelper.HttpHelper, dataPath, namePath, idPath string) ([]Resource, error) {
+	result, err := helper.Method("GET").Request().Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := result.Get(dataPath).Array()
+	resources := make([]Resource, 0, len(items))
+	for _, item := range items {
+		resources = append(resources, Resource{
+			ID:   item.Get(idPath).String(),
+			Name: item.Get(namePath).String(),
+			Data: item,
+		})
+	}
+	return resources, nil
+}
+
+// Request sends the request to the path relative to the client endpoint, the response body is ignored and the
+// resource which has been deleted is not an error.
+func Request(client *golangsdk.ServiceClient, method, path string, body map[string]interface{}) error {
+	opts := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		MoreHeaders:      map[string]string{"Content-Type": "application/json"},
+	}
+	if body != nil {
+		opts.JSONBody = body
+	}
+
+	url := client.Endpoint + strings.ReplaceAll(strings.TrimLeft(path, "/"), "{project_id}", client.ProjectID)
+	_, err := client.Request(method, url, &opts)
+	if _, ok := err.(golangsdk.ErrDefault404); ok {
+		return nil
+	}
+	return err
+}
+
+// ExistsByGet returns an Exists function which checks the resource by the path, the placeholder {id} is replaced with
+// the resource ID.
+func ExistsByGet(path string) func(client *golangsdk.ServiceClient, r Resource) (bool, error) {
+	return ExistsByStatus(path, "")
+}
+
+// ExistsByStatus is similar to ExistsByGet, and the resource is also deleted if its status found by the gjson path is
+// one of the deleted statuses.
+func ExistsByStatus(path, statusPath string, deletedStatuses ...string) func(client *golangsdk.ServiceClient,
+	r Resource) (bool, error) {
+	return func(client *golangsdk.ServiceClient, r Resource) (bool, error) {
+		result, err := httphelper.New(client).Method("GET").URI(strings.ReplaceAll(path, "{id}", r.ID)).Request().Result()
+		if _, ok := err.(golangsdk.ErrDefault404); ok {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if statusPath != "" {
+			status := result.Get(statusPath).String()
+			for _, deleted := range deletedStatuses {
+				if status == deleted {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+	}
+}
@@ -0,0 +1,27 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestMain runs the sweepers if the -sweep flag is specified, e.g. make sweep SWEEP=cn-north-4
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func TestIsSweepable(t *testing.T) {
+	cases := map[string]bool{
+		"tf_test_abcde": true,
+		"tf-test-abcde": true,
+		"tf_test":       false,
+		"my-vpc":        false,
+		"":              false,
+	}
+	for name, expected := range cases {
+		if IsSweepable(name) != expected {
+			t.Errorf("expected IsSweepable(%q) to be %v", name, expected)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package sweep
+
+import (
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/httphelper"
+)
+
+func init() {
+	// the subnets can be deleted after all the ports in them are released
+	Register(&Sweeper{
+		Name: "huaweicloud_vpc_subnet",
+		Dependencies: []string{
+			"huaweicloud_compute_instance",
+			"huaweicloud_elb_loadbalancer",
+			"huaweicloud_rds_instance",
+			"huaweicloud_cce_cluster",
+			"huaweicloud_dcs_instance",
+			"huaweicloud_dms_kafka_instance",
+			"huaweicloud_dms_rabbitmq_instance",
+		},
+		Service: "vpc",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v1/{project_id}/subnets").
+				MarkerPager("subnets", "subnets[-1].id", "marker")
+			return ListJSON(helper, "subnets", "name", "id")
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			path := "v1/{project_id}/vpcs/" + r.Data.Get("vpc_id").String() + "/subnets/" + r.ID
+			return Request(client, "DELETE", path, nil)
+		},
+		Exists: ExistsByGet("v1/{project_id}/subnets/{id}"),
+	})
+
+	Register(&Sweeper{
+		Name:         "huaweicloud_vpc",
+		Dependencies: []string{"huaweicloud_vpc_subnet"},
+		Service:      "vpc",
+		List: func(client *golangsdk.ServiceClient) ([]Resource, error) {
+			helper := httphelper.New(client).URI("v1/{project_id}/vpcs").
+				MarkerPager("vpcs", "vpcs[-1].id", "marker")
+			return ListJSON(helper, "vpcs", "name", "id")
+		},
+		Delete: func(client *golangsdk.ServiceClient, r Resource) error {
+			return Request(client, "DELETE", "v1/{project_id}/vpcs/"+r.ID, nil)
+		},
+		Exists: ExistsByGet("v1/{project_id}/vpcs/{id}"),
+	})
+}
@@ -615,7 +615,7 @@ func updateInstanceTags(client *golangsdk.ServiceClient, d *schema.ResourceData)
 	var (
 		err              error
 		instanceId       = d.Id()
-		oldRaws, newRaws = d.GetChange("tags")
+		oldRaws, newRaws = utils.GetTagsChange(d)
 		rmTags           = oldRaws.(map[string]interface{})
 		addTags          = newRaws.(map[string]interface{})
 	)
@@ -752,7 +752,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta in
 			return diag.FromErr(err)
 		}
 	}
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		if err = updateInstanceTags(client, d); err != nil {
 			return diag.FromErr(err)
 		}
@@ -497,7 +497,7 @@ func resourceBmsInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		err = utils.UpdateResourceTags(bmsClient, d, "baremetalservers", instanceId)
 		if err != nil {
 			return diag.Errorf("error updating tags of bms server: %s", err)
@@ -268,7 +268,7 @@ func resourceCloudConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		err := updateResourceTags(updateCloudConnectionClient, d, conf.DomainID)
 		if err != nil {
 			return diag.Errorf("error updating CloudConnection tags: %s", err)
@@ -346,7 +346,7 @@ func deleteResourceTags(client *golangsdk.ServiceClient, id, domainID string, ta
 }
 
 func updateResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData, domainID string) error {
-	oRaw, nRaw := d.GetChange("tags")
+	oRaw, nRaw := utils.GetTagsChange(d)
 
 	// remove old tags
 	if oMap := oRaw.(map[string]interface{}); len(oMap) > 0 {
@@ -295,7 +295,7 @@ func resourceGlobalConnectionBandwidthUpdate(ctx context.Context, d *schema.Reso
 		}
 	}
 
-	if d.HasChanges("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := updateTags(client, d)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of global connection bandwidth (%s): %s", d.Id(), tagErr)
@@ -306,7 +306,7 @@ func resourceGlobalConnectionBandwidthUpdate(ctx context.Context, d *schema.Reso
 }
 
 func updateTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
-	oRaw, nRaw := d.GetChange("tags")
+	oRaw, nRaw := utils.GetTagsChange(d)
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
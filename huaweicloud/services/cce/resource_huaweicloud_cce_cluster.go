@@ -82,9 +82,11 @@ func ResourceCluster() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: clusterVersionCustomizeDiff,
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
-			Update: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(3 * time.Hour),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
@@ -109,9 +111,9 @@ func ResourceCluster() *schema.Resource {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Computed:         true,
-				ForceNew:         true,
 				DiffSuppressFunc: utils.SuppressVersionDiffs,
 			},
+			"upgrade_strategy": clusterUpgradeStrategySchema,
 			"cluster_type": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -987,6 +989,16 @@ func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 	}
 
+	if d.HasChange("cluster_version") {
+		hcClient, err := cfg.HcCceV3Client(region)
+		if err != nil {
+			return diag.Errorf("error creating CCE v3 client: %s", err)
+		}
+		if err := resourceClusterUpgrade(ctx, hcClient, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if d.HasChange("flavor_id") {
 		err := resourceClusterResize(ctx, cfg, d, cceClient)
 		if err != nil {
@@ -1029,9 +1041,9 @@ func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		// remove old tags and set new tags
-		oldTags, newTags := d.GetChange("tags")
+		oldTags, newTags := utils.GetTagsChange(d)
 		oldTagsRaw := oldTags.(map[string]interface{})
 		if len(oldTagsRaw) > 0 {
 			taglist := utils.ExpandResourceTags(oldTagsRaw)
@@ -0,0 +1,415 @@
+package cce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	v3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cce/v3"
+	cce "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cce/v3/model"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{cluster_id}/operation/precheck
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/operation/precheck/tasks/{task_id}
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{cluster_id}/operation/upgrade
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/operation/upgrade/tasks/{task_id}
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{cluster_id}/operation/postcheck
+
+// the in-place rolling upgrade is the only strategy supported by the CCE upgrade API
+const upgradeStrategyInPlace = "inPlaceRollingUpdate"
+
+var clusterUpgradeStrategySchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      upgradeStrategyInPlace,
+				ValidateFunc: validation.StringInSlice([]string{upgradeStrategyInPlace}, false),
+			},
+			"step": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 40),
+			},
+			"node_pool_order": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"addons": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"template_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"values": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringIsJSON,
+						},
+					},
+				},
+			},
+			"skip_post_check": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	},
+}
+
+// compareClusterVersion compares the numeric parts of the cluster versions, e.g. v1.25.3-r0, and returns -1, 0 or 1
+// if the first one is lower than, equal to or higher than the second one. Only the common parts are compared, so that
+// v1.25 equals to v1.25.3-r0.
+func compareClusterVersion(a, b string) int {
+	partsA := splitClusterVersion(a)
+	partsB := splitClusterVersion(b)
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		if partsA[i] < partsB[i] {
+			return -1
+		}
+		if partsA[i] > partsB[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// clusterVersionCustomizeDiff rejects the downgrade of cluster_version during the plan, the CCE clusters can only be
+// upgraded in place.
+func clusterVersionCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("cluster_version") {
+		return nil
+	}
+
+	oldVersion, newVersion := d.GetChange("cluster_version")
+	if oldVersion.(string) == "" || newVersion.(string) == "" {
+		return nil
+	}
+	if compareClusterVersion(newVersion.(string), oldVersion.(string)) < 0 {
+		return fmt.Errorf("the CCE cluster can not be downgraded from %s to %s", oldVersion, newVersion)
+	}
+	return nil
+}
+
+func splitClusterVersion(version string) []int {
+	parts := regexp.MustCompile(`[\.\-]+`).Split(strings.TrimPrefix(strings.ToLower(version), "v"), -1)
+	result := make([]int, 0, len(parts))
+	for _, p := range parts {
+		num, err := strconv.Atoi(strings.TrimPrefix(p, "r"))
+		if err != nil {
+			break
+		}
+		result = append(result, num)
+	}
+	return result
+}
+
+func buildClusterUpgradeAction(d *schema.ResourceData, targetVersion string) (*cce.ClusterUpgradeAction, error) {
+	action := cce.ClusterUpgradeAction{
+		TargetVersion: targetVersion,
+		Strategy: &cce.UpgradeStrategy{
+			Type: upgradeStrategyInPlace,
+		},
+	}
+
+	strategyRaw := d.Get("upgrade_strategy").([]interface{})
+	if len(strategyRaw) == 0 || strategyRaw[0] == nil {
+		return &action, nil
+	}
+	strategy := strategyRaw[0].(map[string]interface{})
+
+	action.Strategy.Type = strategy["type"].(string)
+	if step := strategy["step"].(int); step > 0 {
+		action.Strategy.InPlaceRollingUpdate = &cce.InPlaceRollingUpdate{
+			UserDefinedStep: utils.Int32(int32(step)),
+		}
+	}
+
+	if orderRaw := strategy["node_pool_order"].(map[string]interface{}); len(orderRaw) > 0 {
+		action.NodePoolOrder = make(map[string]int32, len(orderRaw))
+		for pool, priority := range orderRaw {
+			action.NodePoolOrder[pool] = int32(priority.(int))
+		}
+	}
+
+	addonsRaw := strategy["addons"].([]interface{})
+	if len(addonsRaw) > 0 {
+		addons := make([]cce.UpgradeAddonConfig, 0, len(addonsRaw))
+		for _, v := range addonsRaw {
+			addon := v.(map[string]interface{})
+			addonConfig := cce.UpgradeAddonConfig{
+				AddonTemplateName: addon["template_name"].(string),
+				Operation:         "patch",
+				Version:           addon["version"].(string),
+			}
+			if values := addon["values"].(string); values != "" {
+				var valuesObj interface{}
+				if err := json.Unmarshal([]byte(values), &valuesObj); err != nil {
+					return nil, fmt.Errorf("error parsing the values of the add-on %s: %s",
+						addonConfig.AddonTemplateName, err)
+				}
+				addonConfig.Values = &valuesObj
+			}
+			addons = append(addons, addonConfig)
+		}
+		action.Addons = &addons
+	}
+	return &action, nil
+}
+
+func skipClusterPostCheck(d *schema.ResourceData) bool {
+	strategyRaw := d.Get("upgrade_strategy").([]interface{})
+	if len(strategyRaw) == 0 || strategyRaw[0] == nil {
+		return false
+	}
+	return strategyRaw[0].(map[string]interface{})["skip_post_check"].(bool)
+}
+
+// resourceClusterUpgrade upgrades the cluster in place: the pre-check must pass before the upgrade task is created,
+// the task is polled until it succeeds, and the post-check is performed at last unless it's skipped.
+func resourceClusterUpgrade(ctx context.Context, client *v3.CceClient, d *schema.ResourceData) error {
+	clusterID := d.Id()
+	oldVersion, newVersion := d.GetChange("cluster_version")
+	currentVersion := oldVersion.(string)
+	targetVersion := newVersion.(string)
+
+	action, err := buildClusterUpgradeAction(d, targetVersion)
+	if err != nil {
+		return err
+	}
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if err := clusterUpgradePreCheck(ctx, client, clusterID, currentVersion, targetVersion, timeout); err != nil {
+		return err
+	}
+
+	upgradeOpts := cce.UpgradeClusterRequest{
+		ClusterId: clusterID,
+		Body: &cce.UpgradeClusterRequestBody{
+			Metadata: &cce.UpgradeClusterRequestMetadata{
+				ApiVersion: "v3",
+				Kind:       "UpgradeTask",
+			},
+			Spec: &cce.UpgradeSpec{
+				ClusterUpgradeAction: action,
+			},
+		},
+	}
+	log.Printf("[DEBUG] Upgrading CCE cluster (%s) from %s to %s", clusterID, currentVersion, targetVersion)
+	resp, err := client.UpgradeCluster(&upgradeOpts)
+	if err != nil {
+		return fmt.Errorf("error upgrading CCE cluster (%s) to %s: %s", clusterID, targetVersion, err)
+	}
+	if resp.Metadata == nil || resp.Metadata.Uid == nil {
+		return fmt.Errorf("error upgrading CCE cluster (%s): the upgrade task ID is not found", clusterID)
+	}
+	taskID := *resp.Metadata.Uid
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      clusterUpgradeTaskRefreshFunc(client, clusterID, taskID),
+		Timeout:      timeout,
+		Delay:        30 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the upgrade task (%s) of CCE cluster (%s) to complete: %s",
+			taskID, clusterID, err)
+	}
+
+	if skipClusterPostCheck(d) {
+		return nil
+	}
+	return clusterUpgradePostCheck(client, clusterID, currentVersion, targetVersion)
+}
+
+func clusterUpgradePreCheck(ctx context.Context, client *v3.CceClient, clusterID, currentVersion, targetVersion string,
+	timeout time.Duration) error {
+	preCheckOpts := cce.CreatePreCheckRequest{
+		ClusterId: clusterID,
+		Body: &cce.PrecheckClusterRequestBody{
+			ApiVersion: "v3",
+			Kind:       "PreCheckTask",
+			Spec: &cce.PrecheckSpec{
+				ClusterID:      utils.String(clusterID),
+				ClusterVersion: utils.String(currentVersion),
+				TargetVersion:  utils.String(targetVersion),
+			},
+		},
+	}
+	resp, err := client.CreatePreCheck(&preCheckOpts)
+	if err != nil {
+		return fmt.Errorf("error creating the upgrade pre-check of CCE cluster (%s): %s", clusterID, err)
+	}
+	if resp.Metadata == nil || resp.Metadata.Uid == nil {
+		return fmt.Errorf("error creating the upgrade pre-check of CCE cluster (%s): the task ID is not found", clusterID)
+	}
+	taskID := *resp.Metadata.Uid
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      clusterPreCheckRefreshFunc(client, clusterID, taskID),
+		Timeout:      timeout,
+		Delay:        10 * time.Second,
+		PollInterval: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("the upgrade pre-check (%s) of CCE cluster (%s) failed: %s", taskID, clusterID, err)
+	}
+	return nil
+}
+
+func clusterPreCheckRefreshFunc(client *v3.CceClient, clusterID, taskID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := client.ShowPreCheck(&cce.ShowPreCheckRequest{ClusterId: clusterID, TaskId: taskID})
+		if err != nil {
+			return nil, "ERROR", err
+		}
+		if resp.Status == nil || resp.Status.Phase == nil {
+			return resp, "PENDING", nil
+		}
+
+		phase := *resp.Status.Phase
+		log.Printf("[DEBUG] The upgrade pre-check (%s) of CCE cluster (%s) is %s", taskID, clusterID, phase)
+		switch phase {
+		case "Success":
+			for _, warning := range preCheckFailedItems(resp.Status, true) {
+				log.Printf("[WARN] The upgrade pre-check of CCE cluster (%s) reports: %s", clusterID, warning)
+			}
+			return resp, "COMPLETED", nil
+		case "Failed", "Error":
+			msg := fmt.Sprintf("status: %s", phase)
+			if resp.Status.Message != nil && *resp.Status.Message != "" {
+				msg = fmt.Sprintf("%s, message: %s", msg, *resp.Status.Message)
+			}
+			if items := preCheckFailedItems(resp.Status, false); len(items) > 0 {
+				msg = fmt.Sprintf("%s, the failed check items:\n  %s", msg, strings.Join(items, "\n  "))
+			}
+			return resp, "ERROR", fmt.Errorf("%s", msg)
+		}
+		return resp, "PENDING", nil
+	}
+}
+
+// preCheckFailedItems returns the descriptions of the check items which are not passed, the items of the warning
+// level are returned only if warnings is true, otherwise the fatal ones and the failed ones are returned.
+func preCheckFailedItems(status *cce.PrecheckStatus, warnings bool) []string {
+	result := make([]string, 0)
+	collect := func(scope string, items *[]cce.PreCheckItemStatus) {
+		if items == nil {
+			return
+		}
+		for _, item := range *items {
+			level := utils.StringValue(item.Level)
+			phase := utils.StringValue(item.Phase)
+			if phase == "Success" || phase == "" && level == "Info" {
+				continue
+			}
+			if warnings != (level == "Warning") {
+				continue
+			}
+			result = append(result, fmt.Sprintf("[%s] %s (%s, %s): %s", scope, utils.StringValue(item.Name),
+				level, phase, utils.StringValue(item.Message)))
+		}
+	}
+
+	if status.ClusterCheckStatus != nil {
+		collect("cluster", status.ClusterCheckStatus.ItemsStatus)
+	}
+	if status.AddonCheckStatus != nil {
+		collect("addon", status.AddonCheckStatus.ItemsStatus)
+	}
+	if status.NodeCheckStatus != nil && status.NodeCheckStatus.NodeStageStatus != nil {
+		for _, node := range *status.NodeCheckStatus.NodeStageStatus {
+			scope := "node"
+			if node.NodeInfo != nil {
+				scope = fmt.Sprintf("node %s", utils.StringValue(node.NodeInfo.Name))
+			}
+			collect(scope, node.ItemsStatus)
+		}
+	}
+	return result
+}
+
+func clusterUpgradeTaskRefreshFunc(client *v3.CceClient, clusterID, taskID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := client.ShowUpgradeClusterTask(&cce.ShowUpgradeClusterTaskRequest{
+			ClusterId: clusterID,
+			TaskId:    taskID,
+		})
+		if err != nil {
+			return nil, "ERROR", err
+		}
+		if resp.Status == nil || resp.Status.Phase == nil {
+			return resp, "PENDING", nil
+		}
+
+		phase := *resp.Status.Phase
+		progress := utils.StringValue(resp.Status.Progress)
+		log.Printf("[DEBUG] The upgrade task (%s) of CCE cluster (%s) is %s, progress: %s",
+			taskID, clusterID, phase, progress)
+		switch phase {
+		case "Success":
+			return resp, "COMPLETED", nil
+		case "Failed":
+			return resp, "ERROR", fmt.Errorf("the upgrade task failed at the progress %s, please check the "+
+				"upgrade records of the cluster on the console", progress)
+		case "Pause":
+			return resp, "ERROR", fmt.Errorf("the upgrade task has been paused at the progress %s, it needs to be "+
+				"continued or retried on the console", progress)
+		}
+		return resp, "PENDING", nil
+	}
+}
+
+func clusterUpgradePostCheck(client *v3.CceClient, clusterID, currentVersion, targetVersion string) error {
+	postCheckOpts := cce.CreatePostCheckRequest{
+		ClusterId: clusterID,
+		Body: &cce.PostcheckClusterRequestBody{
+			ApiVersion: "v3",
+			Kind:       "PostCheckTask",
+			Spec: &cce.PostcheckSpec{
+				ClusterID:      utils.String(clusterID),
+				ClusterVersion: utils.String(currentVersion),
+				TargetVersion:  utils.String(targetVersion),
+			},
+		},
+	}
+	resp, err := client.CreatePostCheck(&postCheckOpts)
+	if err != nil {
+		return fmt.Errorf("error creating the upgrade post-check of CCE cluster (%s): %s", clusterID, err)
+	}
+	if resp.Status != nil && resp.Status.Phase != nil {
+		phase := *resp.Status.Phase
+		if phase == "Failed" || phase == "Error" {
+			return fmt.Errorf("the upgrade post-check of CCE cluster (%s) failed with status %s, the cluster has "+
+				"been upgraded to %s, please check it on the console", clusterID, phase, targetVersion)
+		}
+	}
+	return nil
+}
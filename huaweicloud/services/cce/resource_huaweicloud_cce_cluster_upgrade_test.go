@@ -0,0 +1,59 @@
+package cce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareClusterVersion(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1.25", "v1.23", 1},
+		{"v1.23", "v1.25", -1},
+		{"v1.25", "v1.25.3-r0", 0},
+		{"v1.25.3-r0", "v1.25.3-r1", -1},
+		{"v1.27.3-r10", "v1.27.3-r9", 1},
+		{"v1.28", "v1.9", 1},
+		{"V1.25", "v1.25", 0},
+		{"v1.25.3-r0", "v1.25.5-r0", -1},
+		{"v2.0", "v1.30.1-r0", 1},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, compareClusterVersion(c.a, c.b), "compare %s with %s", c.a, c.b)
+	}
+}
+
+func TestClusterVersionCustomizeDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "cluster-id",
+		Attributes: map[string]string{
+			"id":              "cluster-id",
+			"name":            "test",
+			"flavor_id":       "cce.s1.small",
+			"cluster_version": "v1.25",
+		},
+	}
+	buildConfig := func(version string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":            "test",
+			"flavor_id":       "cce.s1.small",
+			"cluster_version": version,
+		})
+	}
+
+	_, err := ResourceCluster().Diff(context.Background(), state, buildConfig("v1.23"), nil)
+	assert.ErrorContains(t, err, "can not be downgraded from v1.25 to v1.23")
+
+	diff, err := ResourceCluster().Diff(context.Background(), state, buildConfig("v1.27"), nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, diff) && assert.Contains(t, diff.Attributes, "cluster_version") {
+		assert.Equal(t, "v1.27", diff.Attributes["cluster_version"].New)
+		assert.False(t, diff.Attributes["cluster_version"].RequiresNew)
+	}
+}
@@ -701,7 +701,7 @@ func resourceNodeUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	serverId := d.Get("server_id").(string)
 
 	// update node tags with ECS API
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(computeClient, d, "cloudservers", serverId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of cce node %s: %s", d.Id(), tagErr)
@@ -488,7 +488,7 @@ func buildNodeAttachUpdateOpts(d *schema.ResourceData) (*nodes.ResetOpts, error)
 func resourceNodeAttachUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cfg := meta.(*config.Config)
 
-	if d.HasChanges("name", "tags", "key_pair", "password") {
+	if d.HasChanges("name", "tags", "tags_all", "key_pair", "password") {
 		return resourceNodeUpdate(ctx, d, cfg)
 	}
 
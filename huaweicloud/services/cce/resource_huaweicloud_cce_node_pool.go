@@ -41,9 +41,12 @@ func ResourceNodePool() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
+		CustomizeDiff: nodePoolRollingCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -67,7 +70,6 @@ func ResourceNodePool() *schema.Resource {
 			"flavor_id": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"type": {
 				Type:     schema.TypeString,
@@ -80,33 +82,29 @@ func ResourceNodePool() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
-			"root_volume":  resourceNodeRootVolume(),
-			"data_volumes": resourceNodeDataVolume(),
+			"root_volume":  withoutForceNew(resourceNodeRootVolume()),
+			"data_volumes": withoutForceNew(resourceNodeDataVolume()),
 			"availability_zone": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "random",
 			},
 			"os": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 			},
 			"key_pair": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ForceNew:     true,
 				ExactlyOneOf: []string{"password", "key_pair"},
 			},
 			"password": {
 				Type:      schema.TypeString,
 				Optional:  true,
-				ForceNew:  true,
 				Sensitive: true,
 			},
-			"storage": resourceNodeStorageSchema(),
+			"storage": withoutForceNew(resourceNodeStorageSchema()),
 			"taints": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -136,19 +134,17 @@ func ResourceNodePool() *schema.Resource {
 			"runtime": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 				ValidateFunc: validation.StringInSlice([]string{
 					"docker", "containerd",
 				}, false),
 			},
-			"extend_params": resourceNodeExtendParamsSchema([]string{
+			"extend_params": withoutForceNew(resourceNodeExtendParamsSchema([]string{
 				"max_pods", "preinstall", "postinstall", "extend_param",
-			}),
+			})),
 			"subnet_id": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 			"scall_enable": {
 				Type:     schema.TypeBool,
@@ -190,6 +186,7 @@ func ResourceNodePool() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"rolling_update": nodePoolRollingUpdateSchema,
 			"initialized_conditions": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -213,28 +210,24 @@ func ResourceNodePool() *schema.Resource {
 			"max_pods": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				ForceNew:    true,
 				Computed:    true,
 				Description: "schema: Deprecated; This parameter can be configured in the 'extend_params' parameter.",
 			},
 			"preinstall": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
 				StateFunc:   utils.DecodeHashAndHexEncode,
 				Description: "schema: Deprecated; This parameter can be configured in the 'extend_params' parameter.",
 			},
 			"postinstall": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
 				StateFunc:   utils.DecodeHashAndHexEncode,
 				Description: "schema: Deprecated; This parameter can be configured in the 'extend_params' parameter.",
 			},
 			"extend_param": {
 				Type:        schema.TypeMap,
 				Optional:    true,
-				ForceNew:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "schema: Deprecated; This parameter has been replaced by the 'extend_params' parameter.",
 			},
@@ -473,6 +466,12 @@ func resourceNodePoolUpdate(ctx context.Context, d *schema.ResourceData, meta in
 		return diag.Errorf("error creating CCE v3 client: %s", err)
 	}
 
+	if d.HasChanges(nodePoolRollingKeys...) {
+		if err := resourceNodePoolRollingUpdate(ctx, d, cceClient); err != nil {
+			return diag.Errorf("error updating the nodes of CCE node pool in rolling: %s", err)
+		}
+	}
+
 	updateOpts, err := buildNodePoolUpdateOpts(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,571 @@
+package cce
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodepools"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/{node_id}
+// @API CCE DELETE /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/{node_id}
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/operation/drain
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/operation/drain/tasks/{task_id}
+
+// nodePoolAnnotation is the annotation of the node pool to which the node belongs
+const nodePoolAnnotation = "kubernetes.io/node-pool.id"
+
+// nodePoolRollingKeys are the arguments of the node template which can't be updated in place, the nodes are
+// replaced in rolling if they are changed and rolling_update is specified, otherwise the node pool is recreated.
+// Only the changes of the node specification, see nodeSpecIdentity, can be told from the nodes, so the other ones are
+// replaced in rolling only if they are changed together with the node specification.
+var nodePoolRollingKeys = []string{
+	"flavor_id", "availability_zone", "os", "key_pair", "password", "runtime", "subnet_id", "root_volume",
+	"data_volumes", "storage", "extend_params", "max_pods", "preinstall", "postinstall", "extend_param",
+}
+
+var nodePoolRollingUpdateSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"max_surge": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_unavailable": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"drain": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	},
+}
+
+type rollingUpdateOpts struct {
+	MaxSurge       int
+	MaxUnavailable int
+	Drain          bool
+}
+
+func buildRollingUpdateOpts(rawList []interface{}) *rollingUpdateOpts {
+	if len(rawList) == 0 || rawList[0] == nil {
+		return nil
+	}
+	raw := rawList[0].(map[string]interface{})
+	return &rollingUpdateOpts{
+		MaxSurge:       raw["max_surge"].(int),
+		MaxUnavailable: raw["max_unavailable"].(int),
+		Drain:          raw["drain"].(bool),
+	}
+}
+
+// nodeSpecIdentity is the part of the node template which is reported by the nodes, it's used to tell the nodes
+// created from the old node template from the new ones. The empty values are chosen by the service.
+type nodeSpecIdentity struct {
+	Flavor   string
+	Az       string
+	Os       string
+	Runtime  string
+	SubnetID string
+	// the size and the type of the root volume and the data volumes
+	Volumes []string
+}
+
+func buildNodeVolumeIdentity(size int, volumeType string) string {
+	return fmt.Sprintf("%d/%s", size, strings.ToUpper(volumeType))
+}
+
+func buildNodeSpecIdentity(spec *nodes.Spec) nodeSpecIdentity {
+	identity := nodeSpecIdentity{
+		Flavor:   spec.Flavor,
+		Az:       spec.Az,
+		Os:       spec.Os,
+		SubnetID: spec.NodeNicSpec.PrimaryNic.SubnetId,
+		Volumes:  []string{buildNodeVolumeIdentity(spec.RootVolume.Size, spec.RootVolume.VolumeType)},
+	}
+	if spec.RunTime != nil {
+		identity.Runtime = spec.RunTime.Name
+	}
+	for _, volume := range spec.DataVolumes {
+		identity.Volumes = append(identity.Volumes, buildNodeVolumeIdentity(volume.Size, volume.VolumeType))
+	}
+	return identity
+}
+
+// buildNodeSpecIdentityFromDiff builds the node specification of the old node template if old is true, otherwise the
+// new one.
+func buildNodeSpecIdentityFromDiff(d *schema.ResourceDiff, old bool) nodeSpecIdentity {
+	get := func(key string) interface{} {
+		oldValue, newValue := d.GetChange(key)
+		if old {
+			return oldValue
+		}
+		return newValue
+	}
+
+	identity := nodeSpecIdentity{
+		Flavor:   get("flavor_id").(string),
+		Az:       get("availability_zone").(string),
+		Os:       get("os").(string),
+		Runtime:  get("runtime").(string),
+		SubnetID: get("subnet_id").(string),
+	}
+	for _, key := range []string{"root_volume", "data_volumes"} {
+		for _, v := range get(key).([]interface{}) {
+			if volume, ok := v.(map[string]interface{}); ok {
+				identity.Volumes = append(identity.Volumes,
+					buildNodeVolumeIdentity(volume["size"].(int), volume["volumetype"].(string)))
+			}
+		}
+	}
+	return identity
+}
+
+// matches returns whether the node specification matches the template, the empty values and the random availability
+// zone of the template match any node.
+func (template nodeSpecIdentity) matches(node nodeSpecIdentity) bool {
+	matchValue := func(templateValue, nodeValue string) bool {
+		return templateValue == "" || templateValue == nodeValue
+	}
+
+	if template.Flavor != node.Flavor || !matchValue(template.Os, node.Os) ||
+		!matchValue(template.Runtime, node.Runtime) || !matchValue(template.SubnetID, node.SubnetID) {
+		return false
+	}
+	if template.Az != "random" && !matchValue(template.Az, node.Az) {
+		return false
+	}
+	if len(template.Volumes) != len(node.Volumes) {
+		return false
+	}
+	for i, volume := range template.Volumes {
+		if volume != node.Volumes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutForceNew clears the ForceNew of the schema and its nested schemas, so that the changes can be handled by the
+// rolling update.
+func withoutForceNew(s *schema.Schema) *schema.Schema {
+	s.ForceNew = false
+	if elem, ok := s.Elem.(*schema.Resource); ok {
+		for _, v := range elem.Schema {
+			withoutForceNew(v)
+		}
+	}
+	return s
+}
+
+// nodePoolRollingCustomizeDiff recreates the node pool when the node template is changed without rolling_update, or
+// the nodes created from the new node template can't be told from the old ones.
+func nodePoolRollingCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	changed := make([]string, 0)
+	for _, key := range nodePoolRollingKeys {
+		if d.HasChange(key) {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	opts := buildRollingUpdateOpts(d.Get("rolling_update").([]interface{}))
+	if opts == nil || buildNodeSpecIdentityFromDiff(d, false).matches(buildNodeSpecIdentityFromDiff(d, true)) {
+		for _, key := range changed {
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if opts.MaxSurge == 0 && opts.MaxUnavailable == 0 {
+		return fmt.Errorf("max_surge and max_unavailable of rolling_update can not be both 0")
+	}
+	if d.Get("charging_mode").(string) == "prePaid" {
+		return fmt.Errorf("the rolling update is not supported by the prePaid node pool, the changes of %s "+
+			"require rolling_update to be removed", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// nodePoolRollingBatch returns the node count to which the node pool is scaled up and the count of the old nodes
+// removed in the next batch: the total nodes can't exceed the expected count plus max_surge, and the available nodes
+// can't be less than the expected count minus max_unavailable.
+func nodePoolRollingBatch(opts *rollingUpdateOpts, expected, oldCount, newCount int) (scaleCount, removeCount int) {
+	surgeCount := expected + opts.MaxSurge - oldCount
+	if surgeCount > expected {
+		surgeCount = expected
+	}
+	if surgeCount > newCount {
+		newCount = surgeCount
+	}
+
+	removeCount = newCount + oldCount - (expected - opts.MaxUnavailable)
+	if removeCount > oldCount {
+		removeCount = oldCount
+	}
+	if removeCount < 0 {
+		removeCount = 0
+	}
+	return newCount + oldCount, removeCount
+}
+
+// resourceNodePoolRollingUpdate replaces the nodes of the node pool after updating its node template: the node pool is
+// scaled up by max_surge nodes built from the new node template, then at most max_unavailable nodes below the node
+// count before the update are drained and deleted, until all the old nodes are replaced. The node pool and its ID are
+// kept.
+// The nodes whose specification doesn't match the new node template are the old ones, so the old node template is
+// kept in the state if the rolling update fails, and the next apply resumes it by only replacing the remaining old
+// nodes.
+func resourceNodePoolRollingUpdate(ctx context.Context, d *schema.ResourceData, client *golangsdk.ServiceClient) error {
+	opts := buildRollingUpdateOpts(d.Get("rolling_update").([]interface{}))
+	if opts == nil {
+		return fmt.Errorf("rolling_update must be specified to update %s", strings.Join(nodePoolRollingKeys, ", "))
+	}
+
+	// keep the old node template in the state until the rolling update completes, so that it can be resumed
+	d.Partial(true)
+
+	clusterID := d.Get("cluster_id").(string)
+	poolID := d.Id()
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	pool, err := nodepools.Get(client, clusterID, poolID).Extract()
+	if err != nil {
+		return fmt.Errorf("error retrieving CCE node pool (%s): %s", poolID, err)
+	}
+	// the autoscaling is disabled during the rolling update, otherwise the autoscaler may scale up the node pool while
+	// its nodes are being removed
+	if err := updateNodePoolTemplate(ctx, d, client, pool.Status.CurrentNode); err != nil {
+		return err
+	}
+
+	createOpts, err := buildNodePoolCreateOpts(d)
+	if err != nil {
+		return err
+	}
+	template := buildNodeSpecIdentity(&createOpts.Spec.NodeTemplate)
+
+	expected := -1
+	for {
+		poolNodes, err := listNodePoolNodes(client, clusterID, poolID)
+		if err != nil {
+			return err
+		}
+		if expected < 0 {
+			expected = len(poolNodes)
+		}
+		oldNodes := make([]nodes.Nodes, 0, len(poolNodes))
+		for _, node := range poolNodes {
+			if !template.matches(buildNodeSpecIdentity(&node.Spec)) {
+				oldNodes = append(oldNodes, node)
+			}
+		}
+		if len(oldNodes) == 0 {
+			break
+		}
+
+		scaleCount, removeCount := nodePoolRollingBatch(opts, expected, len(oldNodes), len(poolNodes)-len(oldNodes))
+		if scaleCount > len(poolNodes) {
+			log.Printf("[DEBUG] Scaling the CCE node pool (%s) to %d nodes", poolID, scaleCount)
+			if err := scaleRollingNodePool(ctx, d, client, scaleCount); err != nil {
+				return err
+			}
+		}
+		if removeCount == 0 {
+			return fmt.Errorf("no node of the CCE node pool (%s) can be replaced, please increase max_surge "+
+				"or max_unavailable", poolID)
+		}
+		if err := removeNodePoolNodes(ctx, client, clusterID, oldNodes[:removeCount], opts.Drain, timeout); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] All nodes of the CCE node pool (%s) have been replaced", poolID)
+	d.Partial(false)
+	return nil
+}
+
+// buildNodePoolTemplateUpdateOpts builds the options to update the node pool with the node template in the
+// configuration, the autoscaling is disabled and the node pool is scaled to the specified count.
+func buildNodePoolTemplateUpdateOpts(d *schema.ResourceData, count int) (*nodepools.UpdateOpts, error) {
+	createOpts, err := buildNodePoolCreateOpts(d)
+	if err != nil {
+		return nil, err
+	}
+	updateOpts, err := buildNodePoolUpdateOpts(d)
+	if err != nil {
+		return nil, err
+	}
+
+	template := createOpts.Spec.NodeTemplate
+	updateOpts.Spec.InitialNodeCount = utils.Int(count)
+	updateOpts.Spec.Autoscaling = nodepools.AutoscalingSpec{}
+	updateOpts.Spec.NodeTemplate.Flavor = template.Flavor
+	updateOpts.Spec.NodeTemplate.Az = template.Az
+	updateOpts.Spec.NodeTemplate.Os = template.Os
+	updateOpts.Spec.NodeTemplate.Login = &template.Login
+	updateOpts.Spec.NodeTemplate.RootVolume = &template.RootVolume
+	updateOpts.Spec.NodeTemplate.DataVolumes = template.DataVolumes
+	updateOpts.Spec.NodeTemplate.Storage = template.Storage
+	updateOpts.Spec.NodeTemplate.NodeNicSpec = &template.NodeNicSpec
+	updateOpts.Spec.NodeTemplate.ExtendParam = template.ExtendParam
+	updateOpts.Spec.NodeTemplate.RunTime = template.RunTime
+	return updateOpts, nil
+}
+
+// updateNodePoolTemplate updates the node template of the node pool, which only applies to the nodes created
+// afterwards.
+func updateNodePoolTemplate(ctx context.Context, d *schema.ResourceData, client *golangsdk.ServiceClient,
+	count int) error {
+	clusterID := d.Get("cluster_id").(string)
+	poolID := d.Id()
+	updateOpts, err := buildNodePoolTemplateUpdateOpts(d, count)
+	if err != nil {
+		return err
+	}
+	if _, err := nodepools.Update(client, clusterID, poolID, updateOpts).Extract(); err != nil {
+		return fmt.Errorf("error updating the node template of CCE node pool (%s): %s", poolID, err)
+	}
+	return waitForNodePoolReady(ctx, client, clusterID, poolID, d.Timeout(schema.TimeoutUpdate))
+}
+
+func scaleRollingNodePool(ctx context.Context, d *schema.ResourceData, client *golangsdk.ServiceClient,
+	count int) error {
+	clusterID := d.Get("cluster_id").(string)
+	poolID := d.Id()
+	updateOpts, err := buildNodePoolTemplateUpdateOpts(d, count)
+	if err != nil {
+		return err
+	}
+	if _, err := nodepools.Update(client, clusterID, poolID, updateOpts).Extract(); err != nil {
+		return fmt.Errorf("error scaling CCE node pool (%s) to %d nodes: %s", poolID, count, err)
+	}
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if err := waitForNodePoolReady(ctx, client, clusterID, poolID, timeout); err != nil {
+		return err
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      nodePoolActiveNodesRefreshFunc(client, clusterID, poolID, count),
+		Timeout:      timeout,
+		Delay:        10 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the nodes of CCE node pool (%s) to become active: %s", poolID, err)
+	}
+	return nil
+}
+
+func waitForNodePoolReady(ctx context.Context, client *golangsdk.ServiceClient, clusterID, poolID string,
+	timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		// The statuses of pending phase includes "Synchronizing" and "Synchronized".
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      nodePoolStateRefreshFunc(client, clusterID, poolID, []string{""}),
+		Timeout:      timeout,
+		Delay:        30 * time.Second,
+		PollInterval: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for CCE node pool (%s) to become available: %s", poolID, err)
+	}
+	return nil
+}
+
+func nodePoolActiveNodesRefreshFunc(client *golangsdk.ServiceClient, clusterID, poolID string,
+	count int) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		poolNodes, err := listNodePoolNodes(client, clusterID, poolID)
+		if err != nil {
+			return nil, "ERROR", err
+		}
+
+		active := 0
+		for _, node := range poolNodes {
+			switch node.Status.Phase {
+			case "Active":
+				active++
+			case "Error", "Abnormal":
+				return poolNodes, "ERROR", fmt.Errorf("the node %s (%s) is %s: %s", node.Metadata.Name,
+					node.Metadata.Id, node.Status.Phase, node.Status.Message)
+			}
+		}
+		log.Printf("[DEBUG] %d of %d nodes of CCE node pool (%s) are active", active, count, poolID)
+		if active >= count {
+			return poolNodes, "COMPLETED", nil
+		}
+		return poolNodes, "PENDING", nil
+	}
+}
+
+// listNodePoolNodes returns the nodes which belong to the node pool and are not being deleted.
+func listNodePoolNodes(client *golangsdk.ServiceClient, clusterID, poolID string) ([]nodes.Nodes, error) {
+	allNodes, err := nodes.List(client, clusterID, nodes.ListOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("error querying the nodes of CCE cluster (%s): %s", clusterID, err)
+	}
+
+	result := make([]nodes.Nodes, 0)
+	for _, node := range allNodes {
+		// the value is the node pool ID, or prefixed with the availability zone, e.g. cn-north-4a#{nodepool_id}
+		poolAnnotation := node.Metadata.Annotations[nodePoolAnnotation]
+		if poolAnnotation != poolID && !strings.HasSuffix(poolAnnotation, "#"+poolID) {
+			continue
+		}
+		if node.Status.Phase == "Deleting" {
+			continue
+		}
+		result = append(result, node)
+	}
+	return result, nil
+}
+
+// removeNodePoolNodes drains the nodes if required and deletes them, the node pool is scaled down by deleting the
+// nodes.
+func removeNodePoolNodes(ctx context.Context, client *golangsdk.ServiceClient, clusterID string,
+	poolNodes []nodes.Nodes, drain bool, timeout time.Duration) error {
+	nodeIDs := make([]string, len(poolNodes))
+	for i, node := range poolNodes {
+		nodeIDs[i] = node.Metadata.Id
+	}
+
+	if drain {
+		if err := drainNodes(ctx, client, clusterID, nodeIDs, timeout); err != nil {
+			return err
+		}
+	}
+
+	for _, nodeID := range nodeIDs {
+		log.Printf("[DEBUG] Deleting the CCE node (%s)", nodeID)
+		err := nodes.Delete(client, clusterID, nodeID).ExtractErr()
+		if err != nil {
+			if _, ok := err.(golangsdk.ErrDefault404); ok {
+				continue
+			}
+			return fmt.Errorf("error deleting CCE node (%s): %s", nodeID, err)
+		}
+	}
+
+	for _, nodeID := range nodeIDs {
+		stateConf := &resource.StateChangeConf{
+			Pending:      []string{"PENDING"},
+			Target:       []string{"COMPLETED"},
+			Refresh:      nodeStateRefreshFunc(client, clusterID, nodeID, nil),
+			Timeout:      timeout,
+			Delay:        30 * time.Second,
+			PollInterval: 20 * time.Second,
+		}
+		if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+			return fmt.Errorf("error waiting for CCE node (%s) to become deleted: %s", nodeID, err)
+		}
+	}
+	return nil
+}
+
+// drainNodes cordons the nodes and evicts their pods, the pods of the DaemonSets are ignored.
+func drainNodes(ctx context.Context, client *golangsdk.ServiceClient, clusterID string, nodeIDs []string,
+	timeout time.Duration) error {
+	drainPath := client.ServiceURL("clusters", clusterID, "nodes", "operation", "drain")
+	drainOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		OkCodes:          []int{200, 201},
+		JSONBody: map[string]interface{}{
+			"apiVersion": "v3",
+			"kind":       "DrainNodesTask",
+			"spec": map[string]interface{}{
+				"nodes":            nodeIDs,
+				"ignoreDaemonSets": true,
+				"deleteLocalData":  true,
+			},
+		},
+	}
+	log.Printf("[DEBUG] Draining the CCE nodes: %v", nodeIDs)
+	resp, err := client.Request("POST", drainPath, &drainOpt)
+	if err != nil {
+		return fmt.Errorf("error draining CCE nodes %v: %s", nodeIDs, err)
+	}
+	respBody, err := utils.FlattenResponse(resp)
+	if err != nil {
+		return err
+	}
+	taskID := utils.PathSearch("metadata.uid", respBody, "").(string)
+	if taskID == "" {
+		return fmt.Errorf("error draining CCE nodes %v: the task ID is not found", nodeIDs)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      drainTaskRefreshFunc(client, clusterID, taskID),
+		Timeout:      timeout,
+		Delay:        10 * time.Second,
+		PollInterval: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the drain task (%s) of CCE nodes %v to complete: %s", taskID, nodeIDs, err)
+	}
+	return nil
+}
+
+func drainTaskRefreshFunc(client *golangsdk.ServiceClient, clusterID, taskID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		getPath := client.ServiceURL("clusters", clusterID, "nodes", "operation", "drain", "tasks", taskID)
+		getOpt := golangsdk.RequestOpts{
+			KeepResponseBody: true,
+			OkCodes:          []int{200},
+		}
+		resp, err := client.Request("GET", getPath, &getOpt)
+		if err != nil {
+			return nil, "ERROR", err
+		}
+		respBody, err := utils.FlattenResponse(resp)
+		if err != nil {
+			return nil, "ERROR", err
+		}
+
+		phase := utils.PathSearch("status.phase", respBody, "").(string)
+		switch phase {
+		case "Success":
+			return respBody, "COMPLETED", nil
+		case "Failed", "Error":
+			return respBody, "ERROR", fmt.Errorf("the drain task is %s: %v", phase,
+				utils.PathSearch("status.message", respBody, ""))
+		}
+		return respBody, "PENDING", nil
+	}
+}
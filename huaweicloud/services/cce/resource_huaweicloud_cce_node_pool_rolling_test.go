@@ -0,0 +1,178 @@
+package cce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+)
+
+func TestNodePoolRollingBatch(t *testing.T) {
+	cases := []struct {
+		name                         string
+		opts                         rollingUpdateOpts
+		expected, oldCount, newCount int
+		scaleCount, removeCount      int
+	}{
+		{"surge first batch", rollingUpdateOpts{MaxSurge: 1}, 3, 3, 0, 4, 1},
+		{"surge next batch", rollingUpdateOpts{MaxSurge: 1}, 3, 2, 1, 4, 1},
+		{"surge last batch", rollingUpdateOpts{MaxSurge: 1}, 3, 1, 2, 4, 1},
+		{"surge larger than expected", rollingUpdateOpts{MaxSurge: 5}, 3, 3, 0, 6, 3},
+		{"unavailable without surge", rollingUpdateOpts{MaxUnavailable: 1}, 3, 3, 0, 3, 1},
+		{"unavailable after removal", rollingUpdateOpts{MaxUnavailable: 1}, 3, 2, 0, 3, 1},
+		{"surge and unavailable", rollingUpdateOpts{MaxSurge: 1, MaxUnavailable: 1}, 3, 3, 0, 4, 2},
+		{"resumed with surge nodes", rollingUpdateOpts{MaxSurge: 1}, 3, 3, 1, 4, 1},
+		{"nothing replaceable", rollingUpdateOpts{}, 3, 3, 0, 3, 0},
+	}
+
+	for _, c := range cases {
+		scaleCount, removeCount := nodePoolRollingBatch(&c.opts, c.expected, c.oldCount, c.newCount)
+		assert.Equal(t, c.scaleCount, scaleCount, c.name)
+		assert.Equal(t, c.removeCount, removeCount, c.name)
+	}
+}
+
+func TestNodePoolRollingBatchConverges(t *testing.T) {
+	for _, opts := range []rollingUpdateOpts{
+		{MaxSurge: 1},
+		{MaxSurge: 2},
+		{MaxUnavailable: 1},
+		{MaxSurge: 1, MaxUnavailable: 2},
+		{MaxSurge: 10, MaxUnavailable: 10},
+	} {
+		for _, expected := range []int{1, 2, 5} {
+			oldCount, newCount := expected, 0
+			for batch := 0; oldCount > 0; batch++ {
+				if !assert.Less(t, batch, expected+1, "%+v of %d nodes doesn't converge", opts, expected) {
+					break
+				}
+
+				scaleCount, removeCount := nodePoolRollingBatch(&opts, expected, oldCount, newCount)
+				assert.LessOrEqual(t, scaleCount, expected+opts.MaxSurge)
+				if scaleCount > oldCount+newCount {
+					newCount = scaleCount - oldCount
+				}
+				assert.Greater(t, removeCount, 0)
+				oldCount -= removeCount
+				assert.GreaterOrEqual(t, oldCount+newCount, expected-opts.MaxUnavailable)
+			}
+		}
+	}
+}
+
+func TestNodeSpecIdentityMatches(t *testing.T) {
+	node := buildNodeSpecIdentity(&nodes.Spec{
+		Flavor:      "c7.large.2",
+		Az:          "cn-north-4a",
+		Os:          "EulerOS 2.9",
+		RunTime:     &nodes.RunTimeSpec{Name: "containerd"},
+		NodeNicSpec: nodes.NodeNicSpec{PrimaryNic: nodes.PrimaryNic{SubnetId: "subnet-id"}},
+		RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+		DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+	})
+
+	cases := []struct {
+		name     string
+		template nodes.Spec
+		expected bool
+	}{
+		{"same spec", nodes.Spec{
+			Flavor: "c7.large.2", Az: "cn-north-4a", Os: "EulerOS 2.9", RunTime: &nodes.RunTimeSpec{Name: "containerd"},
+			NodeNicSpec: nodes.NodeNicSpec{PrimaryNic: nodes.PrimaryNic{SubnetId: "subnet-id"}},
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "ssd"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, true},
+		{"chosen by the service", nodes.Spec{
+			Flavor: "c7.large.2", Az: "random",
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, true},
+		{"flavor changed", nodes.Spec{
+			Flavor:      "c7.xlarge.2",
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, false},
+		{"os changed", nodes.Spec{
+			Flavor: "c7.large.2", Os: "Huawei Cloud EulerOS 2.0",
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, false},
+		{"runtime changed", nodes.Spec{
+			Flavor: "c7.large.2", RunTime: &nodes.RunTimeSpec{Name: "docker"},
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, false},
+		{"availability zone changed", nodes.Spec{
+			Flavor: "c7.large.2", Az: "cn-north-4b",
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, false},
+		{"data volume added", nodes.Spec{
+			Flavor:      "c7.large.2",
+			RootVolume:  nodes.VolumeSpec{Size: 40, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}, {Size: 100, VolumeType: "SSD"}},
+		}, false},
+		{"root volume resized", nodes.Spec{
+			Flavor:      "c7.large.2",
+			RootVolume:  nodes.VolumeSpec{Size: 50, VolumeType: "SSD"},
+			DataVolumes: []nodes.VolumeSpec{{Size: 100, VolumeType: "SSD"}},
+		}, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, buildNodeSpecIdentity(&c.template).matches(node), c.name)
+	}
+}
+
+func TestNodePoolRollingCustomizeDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "pool-id",
+		Attributes: map[string]string{
+			"id":                               "pool-id",
+			"name":                             "test",
+			"cluster_id":                       "cluster-id",
+			"initial_node_count":               "2",
+			"flavor_id":                        "c7.large.2",
+			"password":                         "old-password",
+			"root_volume.#":                    "1",
+			"root_volume.0.size":               "40",
+			"root_volume.0.volumetype":         "SSD",
+			"rolling_update.#":                 "1",
+			"rolling_update.0.max_surge":       "1",
+			"rolling_update.0.drain":           "true",
+			"rolling_update.0.max_unavailable": "0",
+		},
+	}
+	buildConfig := func(flavor, password string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":               "test",
+			"cluster_id":         "cluster-id",
+			"initial_node_count": 2,
+			"flavor_id":          flavor,
+			"password":           password,
+			"root_volume": []interface{}{
+				map[string]interface{}{"size": 40, "volumetype": "SSD"},
+			},
+			"rolling_update": []interface{}{
+				map[string]interface{}{"max_surge": 1},
+			},
+		})
+	}
+
+	diff, err := ResourceNodePool().Diff(context.Background(), state, buildConfig("c7.xlarge.2", "new-password"), nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, diff) && assert.Contains(t, diff.Attributes, "flavor_id") {
+		assert.False(t, diff.Attributes["flavor_id"].RequiresNew)
+		assert.False(t, diff.Attributes["password"].RequiresNew)
+	}
+
+	// the nodes created with the new password can't be told from the old ones
+	diff, err = ResourceNodePool().Diff(context.Background(), state, buildConfig("c7.large.2", "new-password"), nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, diff) && assert.Contains(t, diff.Attributes, "password") {
+		assert.True(t, diff.Attributes["password"].RequiresNew)
+	}
+}
@@ -650,8 +650,8 @@ func resourcePrivateCAUpdate(_ context.Context, d *schema.ResourceData, meta int
 	}
 
 	// update tags
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
+	if utils.HasTagsChange(d) {
+		oRaw, nRaw := utils.GetTagsChange(d)
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
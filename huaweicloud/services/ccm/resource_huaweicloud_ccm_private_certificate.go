@@ -363,8 +363,8 @@ func resourceCcmPrivateCertificateUpdate(_ context.Context, d *schema.ResourceDa
 	}
 
 	// update tags
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
+	if utils.HasTagsChange(d) {
+		oRaw, nRaw := utils.GetTagsChange(d)
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
@@ -1194,7 +1194,7 @@ func resourceCdnDomainUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		if err := updateDomainTags(hcCdnClient, d); err != nil {
 			return diag.FromErr(err)
 		}
@@ -1204,7 +1204,7 @@ func resourceCdnDomainUpdate(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func updateDomainTags(hcCdnClient *cdnv2.CdnClient, d *schema.ResourceData) error {
-	oTagsRaw, nTagsRaw := d.GetChange("tags")
+	oTagsRaw, nTagsRaw := utils.GetTagsChange(d)
 	oTagsMap := oTagsRaw.(map[string]interface{})
 	nTagsMap := nTagsRaw.(map[string]interface{})
 
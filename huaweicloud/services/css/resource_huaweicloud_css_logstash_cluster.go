@@ -384,7 +384,7 @@ func resourceLogstashClusterUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		client, err := conf.CssV1Client(region)
 		if err != nil {
 			return diag.Errorf("error creating CSS V1 client: %s", err)
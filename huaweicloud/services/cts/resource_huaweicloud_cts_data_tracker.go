@@ -234,7 +234,7 @@ func buildTransferBucketOpts(d *schema.ResourceData) *cts.TrackerObsInfo {
 }
 
 func updateResourceTags(ctsClient *client.CtsClient, d *schema.ResourceData) error {
-	oldRaw, newRaw := d.GetChange("tags")
+	oldRaw, newRaw := utils.GetTagsChange(d)
 	id := d.Id()
 
 	if oldTags := oldRaw.(map[string]interface{}); len(oldTags) > 0 {
@@ -343,7 +343,7 @@ func resourceCTSDataTrackerUpdate(ctx context.Context, d *schema.ResourceData, m
 			return diag.Errorf("error updating CTS tracker: %s", err)
 		}
 
-		if d.HasChange("tags") {
+		if utils.HasTagsChange(d) {
 			err = updateResourceTags(ctsClient, d)
 			if err != nil {
 				return diag.Errorf("error updating CTS tracker tags: %s", err)
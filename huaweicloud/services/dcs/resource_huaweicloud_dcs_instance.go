@@ -29,6 +29,7 @@ import (
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
@@ -94,6 +95,7 @@ var (
 // @API DCS GET /v2/{project_id}/instances/{instance_id}/tags
 // @API DCS PUT /v2/{project_id}/instances/{instance_id}
 // @API DCS PUT /v2/{project_id}/instances/{instance_id}/password
+// @API DCS POST /v2/{project_id}/instances/{instance_id}/password/reset
 // @API DCS POST /v2/{project_id}/instances/{instance_id}/resize
 // @API DCS POST /v3/{project_id}/instances/{instance_id}/tags/action
 // @API EPS POST /v1.0/enterprise-projects/{enterprise_project_id}/resources-migrat
@@ -129,6 +131,18 @@ func ResourceDcsInstance() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"encrypted_password": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -694,9 +708,14 @@ func resourceDcsInstancesCreate(ctx context.Context, d *schema.ResourceData, met
 		return diag.FromErr(err)
 	}
 
+	password, err := encryption.GetPassword(d, "password")
+	if err != nil {
+		return diag.Errorf("error generating the password of the DCS instance: %s", err)
+	}
+
 	// noPasswordAccess
 	noPasswordAccess := true
-	if d.Get("access_user").(string) != "" || d.Get("password").(string) != "" {
+	if d.Get("access_user").(string) != "" || password != "" {
 		noPasswordAccess = false
 	}
 	// resourceSpecCode
@@ -757,7 +776,7 @@ func resourceDcsInstancesCreate(ctx context.Context, d *schema.ResourceData, met
 	log.Printf("[DEBUG] Create DCS instance options(hide password) : %#v", createOpts)
 
 	// Add password here so it wouldn't go in the above log entry
-	createOpts.Password = d.Get("password").(string)
+	createOpts.Password = password
 
 	// create instance
 	r, err := instances.Create(client, createOpts)
@@ -767,6 +786,12 @@ func resourceDcsInstancesCreate(ctx context.Context, d *schema.ResourceData, met
 	id := r.Instances[0].InstanceId
 	d.SetId(id)
 
+	if password != "" {
+		if err := encryption.EncryptToState(d, "encrypted_password", password, "DCS instance password"); err != nil {
+			return diag.Errorf("error saving the encrypted password of the DCS instance (%s): %s", id, err)
+		}
+	}
+
 	// If charging mode is PrePaid, wait for the order to be completed.
 	if strings.EqualFold(d.Get("charging_mode").(string), chargeModePrePaid) {
 		err = waitForOrderComplete(ctx, d, cfg, region, r.OrderId)
@@ -1203,14 +1228,24 @@ func resourceDcsInstancesUpdate(ctx context.Context, d *schema.ResourceData, met
 		}
 	}
 
-	if d.HasChange("password") {
-		oldVal, newVal := d.GetChange("password")
+	password, resetPassword, err := encryption.GetUpdatedPassword(d, "password")
+	if err != nil {
+		return diag.Errorf("error generating the password of the DCS instance: %s", err)
+	}
+	if resetPassword {
+		oldVal, _ := d.GetChange("password")
+		oldPgpKey, _ := d.GetChange("pgp_key")
 		opts := instances.UpdatePasswordOpts{
 			OldPassword: oldVal.(string),
-			NewPassword: newVal.(string),
+			NewPassword: password,
 		}
 		err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-			_, err = instances.UpdatePassword(client, instanceId, opts)
+			// the generated password is only saved encrypted, so it's reset without the old password
+			if opts.OldPassword == "" && oldPgpKey.(string) != "" {
+				err = resetDcsInstancePassword(client, instanceId, password)
+			} else {
+				_, err = instances.UpdatePassword(client, instanceId, opts)
+			}
 			isRetry, err := handleOperationError(err)
 			if isRetry {
 				return resource.RetryableError(err)
@@ -1225,6 +1260,12 @@ func resourceDcsInstancesUpdate(ctx context.Context, d *schema.ResourceData, met
 		}
 	}
 
+	if d.HasChanges("password", "pgp_key") {
+		if err := encryption.EncryptToState(d, "encrypted_password", password, "DCS instance password"); err != nil {
+			return diag.Errorf("error saving the encrypted password of the DCS instance (%s): %s", instanceId, err)
+		}
+	}
+
 	// resize instance
 	err = resizeDcsInstance(ctx, d, meta)
 	if err != nil {
@@ -1232,8 +1273,8 @@ func resourceDcsInstancesUpdate(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	// update tags
-	if d.HasChange("tags") {
-		oldVal, newVal := d.GetChange("tags")
+	if utils.HasTagsChange(d) {
+		oldVal, newVal := utils.GetTagsChange(d)
 		err = updateDcsTags(client, instanceId, oldVal.(map[string]interface{}), newVal.(map[string]interface{}))
 		if err != nil {
 			return diag.FromErr(err)
@@ -1497,6 +1538,22 @@ func getFlavorBySpecCode(client *golangsdk.ServiceClient, specCode string) (*fla
 	return &list[0], nil
 }
 
+// resetDcsInstancePassword resets the password of the instance without the old password.
+func resetDcsInstancePassword(client *golangsdk.ServiceClient, instanceID, password string) error {
+	resetPasswordHttpUrl := "v2/{project_id}/instances/{instance_id}/password/reset"
+	resetPasswordPath := client.Endpoint + resetPasswordHttpUrl
+	resetPasswordPath = strings.ReplaceAll(resetPasswordPath, "{project_id}", client.ProjectID)
+	resetPasswordPath = strings.ReplaceAll(resetPasswordPath, "{instance_id}", instanceID)
+
+	resetPasswordOpt := golangsdk.RequestOpts{KeepResponseBody: true}
+	resetPasswordOpt.JSONBody = map[string]interface{}{
+		"new_password":       password,
+		"no_password_access": false,
+	}
+	_, err := client.Request("POST", resetPasswordPath, &resetPasswordOpt)
+	return err
+}
+
 func handleOperationError(err error) (bool, error) {
 	if err == nil {
 		return false, nil
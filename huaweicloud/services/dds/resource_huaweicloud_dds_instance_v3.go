@@ -23,6 +23,7 @@ import (
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
@@ -228,6 +229,18 @@ func ResourceDdsInstanceV3() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"encrypted_password": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -428,7 +441,11 @@ func resourceDdsInstanceV3Create(ctx context.Context, d *schema.ResourceData, me
 	}
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
 	// Add password here so it wouldn't go in the above log entry
-	createOpts.Password = d.Get("password").(string)
+	password, err := encryption.GetPassword(d, "password")
+	if err != nil {
+		return diag.Errorf("error generating the password of the DDS instance: %s", err)
+	}
+	createOpts.Password = password
 
 	if val, ok := d.GetOk("port"); ok {
 		createOpts.Port = strconv.Itoa(val.(int))
@@ -460,6 +477,10 @@ func resourceDdsInstanceV3Create(ctx context.Context, d *schema.ResourceData, me
 		d.SetId(instance.Id)
 	}
 
+	if err := encryption.EncryptToState(d, "encrypted_password", password, "DDS instance password"); err != nil {
+		return diag.Errorf("error saving the encrypted password of the DDS instance (%s): %s", d.Id(), err)
+	}
+
 	if description, ok := d.GetOk("description"); ok {
 		opt := instances.RemarkOpts{
 			Remark: description.(string),
@@ -692,10 +713,14 @@ func resourceDdsInstanceV3Update(ctx context.Context, d *schema.ResourceData, me
 		opts = append(opts, opt)
 	}
 
-	if d.HasChange("password") {
+	password, resetPassword, err := encryption.GetUpdatedPassword(d, "password")
+	if err != nil {
+		return diag.Errorf("error generating the password of the DDS instance: %s", err)
+	}
+	if resetPassword {
 		opt := instances.UpdateOpt{
 			Param:  "user_pwd",
-			Value:  d.Get("password").(string),
+			Value:  password,
 			Action: "reset-password",
 			Method: "put",
 		}
@@ -769,6 +794,12 @@ func resourceDdsInstanceV3Update(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	if d.HasChanges("password", "pgp_key") {
+		if err := encryption.EncryptToState(d, "encrypted_password", password, "DDS instance password"); err != nil {
+			return diag.Errorf("error saving the encrypted password of the DDS instance (%s): %s", instanceId, err)
+		}
+	}
+
 	if d.HasChange("port") {
 		retryFunc := func() (interface{}, bool, error) {
 			resp, err := instances.UpdatePort(client, instanceId, d.Get("port").(int))
@@ -802,7 +833,7 @@ func resourceDdsInstanceV3Update(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceId)
 		if tagErr != nil {
 			return diag.Errorf("Error updating tags of DDS instance:%s, err:%s", instanceId, tagErr)
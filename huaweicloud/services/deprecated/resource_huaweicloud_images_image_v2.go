@@ -310,7 +310,7 @@ func resourceImagesImageV2Update(d *schema.ResourceData, meta interface{}) error
 		updateOpts = append(updateOpts, v)
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tags := d.Get("tags").(*schema.Set).List()
 		v := images.ReplaceImageTags{
 			NewTags: resourceImagesImageV2BuildTags(tags),
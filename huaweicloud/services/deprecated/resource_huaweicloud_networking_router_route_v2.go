@@ -51,11 +51,19 @@ func ResourceNetworkingRouterRouteV2() *schema.Resource {
 }
 
 func resourceNetworkingRouterRouteV2Create(d *schema.ResourceData, meta interface{}) error {
-
 	routerId := d.Get("router_id").(string)
 	config.MutexKV.Lock(routerId)
-	defer config.MutexKV.Unlock(routerId)
+	err := addNetworkingRouterRoute(d, meta, routerId)
+	// the lock must be released before reading the route, which holds the read lock of the router
+	config.MutexKV.Unlock(routerId)
+	if err != nil {
+		return err
+	}
 
+	return resourceNetworkingRouterRouteV2Read(d, meta)
+}
+
+func addNetworkingRouterRoute(d *schema.ResourceData, meta interface{}, routerId string) error {
 	var destCidr string = d.Get("destination_cidr").(string)
 	var nextHop string = d.Get("next_hop").(string)
 
@@ -110,15 +118,15 @@ func resourceNetworkingRouterRouteV2Create(d *schema.ResourceData, meta interfac
 		logp.Printf("[DEBUG] Router %s has route already", routerId)
 	}
 
-	return resourceNetworkingRouterRouteV2Read(d, meta)
+	return nil
 }
 
 func resourceNetworkingRouterRouteV2Read(d *schema.ResourceData, meta interface{}) error {
 
 	routerId := d.Get("router_id").(string)
 
-	config := meta.(*config.Config)
-	networkingClient, err := config.NetworkingV2Client(config.GetRegion(d))
+	conf := meta.(*config.Config)
+	networkingClient, err := conf.NetworkingV2Client(conf.GetRegion(d))
 	if err != nil {
 		return fmtp.Errorf("Error creating HuaweiCloud networking client: %s", err)
 	}
@@ -142,7 +150,10 @@ func resourceNetworkingRouterRouteV2Read(d *schema.ResourceData, meta interface{
 		}
 	}
 
+	// the routes of the router are only read, so the read lock is enough to wait for the routes being updated
+	config.MutexKV.RLock(routerId)
 	n, err := routers.Get(networkingClient, routerId).Extract()
+	config.MutexKV.RUnlock(routerId)
 	if err != nil {
 		if _, ok := err.(golangsdk.ErrDefault404); ok {
 			d.SetId("")
@@ -166,7 +177,7 @@ func resourceNetworkingRouterRouteV2Read(d *schema.ResourceData, meta interface{
 		}
 	}
 
-	d.Set("region", config.GetRegion(d))
+	d.Set("region", conf.GetRegion(d))
 
 	return nil
 }
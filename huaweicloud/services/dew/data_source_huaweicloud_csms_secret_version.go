@@ -12,6 +12,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/csms/v1/secrets"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 )
 
 // @API DEW GET /v1/{project_id}/secrets/{secret_name}/versions
@@ -35,11 +36,23 @@ func DataSourceDewCsmsSecret() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 			"secret_text": {
 				Type:      schema.TypeString,
 				Computed:  true,
 				Sensitive: true,
 			},
+			"encrypted_secret_text": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"kms_key_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -81,7 +94,7 @@ func dataSourceDewCsmsSecretRead(_ context.Context, d *schema.ResourceData, meta
 	createTime := time.Unix(int64(vMetadata.CreateTime)/1000, 0).UTC().Format("2006-01-02 15:04:05 MST")
 	mErr := multierror.Append(
 		d.Set("region", region),
-		d.Set("secret_text", version.SecretString),
+		encryption.SetSensitiveValue(d, "secret_text", version.SecretString, "CSMS secret"),
 		d.Set("secret_name", vMetadata.SecretName),
 		d.Set("kms_key_id", vMetadata.KmsKeyID),
 		d.Set("version", vMetadata.ID),
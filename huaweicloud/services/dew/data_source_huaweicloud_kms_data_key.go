@@ -10,6 +10,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/kms/v1/keys"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 )
 
 // @API DEW POST /v1.0/{project_id}/kms/create-datakey
@@ -35,10 +36,22 @@ func DataSourceKmsDataKeyV1() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 			"plain_text": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"encrypted_plain_text": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cipher_text": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -66,8 +79,10 @@ func resourceKmsDataKeyV1Read(_ context.Context, d *schema.ResourceData, meta in
 
 	// lintignore:R017
 	d.SetId(time.Now().UTC().String())
-	d.Set("plain_text", v.PlainText)
 	d.Set("cipher_text", v.CipherText)
+	if err := encryption.SetSensitiveValue(d, "plain_text", v.PlainText, "KMS data key"); err != nil {
+		return diag.Errorf("error saving KMS data key: %s", err)
+	}
 
 	return nil
 }
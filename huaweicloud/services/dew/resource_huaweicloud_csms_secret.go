@@ -311,7 +311,7 @@ func resourceCsmsSecretUpdate(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	// Update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		err = utils.UpdateResourceTags(client, d, serviceType, id)
 		if err != nil {
 			return diag.Errorf("failed to update CSMS secret tags: %s", err)
@@ -19,6 +19,7 @@ import (
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
@@ -114,6 +115,20 @@ func ResourceKeypair() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+			"pgp_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"public_key"},
+			},
+			"encrypted_private_key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 
 			"created_at": {
 				Type:     schema.TypeString,
@@ -171,6 +186,12 @@ func resourceKeypairCreate(ctx context.Context, d *schema.ResourceData, meta int
 		d.Set("key_file", fp)
 	}
 
+	if privateKey := response.Keypair.PrivateKey; privateKey != nil && *privateKey != "" {
+		if err := encryption.EncryptToState(d, "encrypted_private_key", *privateKey, "private key"); err != nil {
+			return diag.Errorf("error saving the encrypted private key: %s", err)
+		}
+	}
+
 	return resourceKeypairRead(ctx, d, meta)
 }
 
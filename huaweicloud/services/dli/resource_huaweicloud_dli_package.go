@@ -242,14 +242,14 @@ func ResourceDliDependentPackageV2Update(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		v3Client, err := cfg.DliV3Client(region)
 		if err != nil {
 			return diag.Errorf("error creating DLI v3 client: %s", err)
 		}
 
 		resourceId := getASCIIFormationId(d.Id())
-		oldTags, newTags := d.GetChange("tags")
+		oldTags, newTags := utils.GetTagsChange(d)
 		err = updateResourceTags(v3Client, resourceId, "dli_package_resource", oldTags, newTags)
 		if err != nil {
 			return diag.Errorf("error updating tags of the package (%s): %s", resourceId, err)
@@ -592,7 +592,7 @@ func resourceDmsRocketMQInstanceUpdate(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(updateRocketmqInstanceClient, d, "rocketmq", instanceId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of RocketMQ:%s, err:%s", instanceId, tagErr)
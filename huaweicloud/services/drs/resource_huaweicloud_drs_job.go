@@ -746,7 +746,7 @@ func resourceJobUpdate(ctx context.Context, d *schema.ResourceData, meta interfa
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(clientV5, d, "jobs/"+d.Get("type").(string), d.Id())
 		if tagErr != nil {
 			return diag.Diagnostics{
@@ -10,6 +10,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/filters"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/hashcode"
 )
 
@@ -62,6 +63,7 @@ func DataSourceComputeInstances() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"filter": filters.Schema(),
 			"instances": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -139,22 +141,27 @@ func DataSourceComputeInstances() *schema.Resource {
 	}
 }
 
+// instancesQueryPaths are the filter paths sent to the API as the query parameters.
+var instancesQueryPaths = filters.QueryPaths{
+	"name":      "name",
+	"flavor_id": "flavor_id",
+	"status":    "status",
+}
+
 func buildListOptsWithoutIP(d *schema.ResourceData, conf *config.Config) *cloudservers.ListOpts {
 	result := cloudservers.ListOpts{
 		Limit:               100,
 		EnterpriseProjectID: conf.DataGetEnterpriseProjectID(d),
-		Name:                d.Get("name").(string),
-		Flavor:              d.Get("flavor_id").(string),
-		Status:              d.Get("status").(string),
+		Name:                instancesQueryPaths.Value(d, "name"),
+		Flavor:              instancesQueryPaths.Value(d, "flavor_id"),
+		Status:              instancesQueryPaths.Value(d, "status"),
 	}
 
 	return &result
 }
 
-func filterCloudServers(d *schema.ResourceData, servers []cloudservers.CloudServer) ([]cloudservers.CloudServer,
-	[]string) {
+func filterCloudServers(d *schema.ResourceData, servers []cloudservers.CloudServer) []cloudservers.CloudServer {
 	result := make([]cloudservers.CloudServer, 0, len(servers))
-	ids := make([]string, 0, len(servers))
 
 	for _, server := range servers {
 		if serverId, ok := d.GetOk("instance_id"); ok && serverId != server.ID {
@@ -173,10 +180,9 @@ func filterCloudServers(d *schema.ResourceData, servers []cloudservers.CloudServ
 			continue
 		}
 		result = append(result, server)
-		ids = append(ids, server.ID)
 	}
 
-	return result, ids
+	return result
 }
 
 func dataSourceComputeInstancesRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -193,10 +199,7 @@ func dataSourceComputeInstancesRead(_ context.Context, d *schema.ResourceData, m
 		return diag.Errorf("unable to retrieve ECS instances: %s", err)
 	}
 
-	servers, ids := filterCloudServers(d, allServers)
-	// Save the data source ID using a hash code constructed using all instance IDs.
-	d.SetId(hashcode.Strings(ids))
-
+	servers := filterCloudServers(d, allServers)
 	return setComputeInstancesParams(d, conf, servers)
 }
 
@@ -251,6 +254,17 @@ func setComputeInstancesParams(d *schema.ResourceData, conf *config.Config, serv
 		result[i] = server
 	}
 
+	result, err = filters.Apply(d, result)
+	if err != nil {
+		return diag.Errorf("error filtering ECS instances: %s", err)
+	}
+	ids := make([]string, 0, len(result))
+	for _, server := range result {
+		ids = append(ids, server["id"].(string))
+	}
+	// Save the data source ID using a hash code constructed using all instance IDs.
+	d.SetId(hashcode.Strings(ids))
+
 	mErr := multierror.Append(nil,
 		d.Set("instances", result),
 	)
@@ -75,6 +75,25 @@ func ResourceComputeVolumeAttach() *schema.Resource {
 	}
 }
 
+// lockVolumeAttachment locks the instance and the volume, the waiting time for the locks is limited to half of the
+// timeout, so that the attaching or detaching job has enough time left to complete.
+func lockVolumeAttachment(ctx context.Context, instanceId, volumeId string, timeout time.Duration) (func(), error) {
+	lockCtx, cancel := context.WithTimeout(ctx, timeout/2)
+	defer cancel()
+
+	if err := config.MutexKV.LockContext(lockCtx, instanceId, 0); err != nil {
+		return nil, err
+	}
+	if err := config.MutexKV.LockContext(lockCtx, volumeId, 0); err != nil {
+		config.MutexKV.Unlock(instanceId)
+		return nil, err
+	}
+	return func() {
+		config.MutexKV.Unlock(volumeId)
+		config.MutexKV.Unlock(instanceId)
+	}, nil
+}
+
 func resourceComputeVolumeAttachCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	conf := meta.(*config.Config)
 	region := conf.GetRegion(d)
@@ -83,14 +102,15 @@ func resourceComputeVolumeAttachCreate(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("Error creating compute v1 client: %s", err)
 	}
 
-	// The ECS instances do not support mounting multiple volumes at the same time.
+	// The ECS instances do not support mounting multiple volumes at the same time, and the EVS volumes also do not
+	// support being mounted to multiple instances at the same time.
 	instanceId := d.Get("instance_id").(string)
-	config.MutexKV.Lock(instanceId)
-	defer config.MutexKV.Unlock(instanceId)
-	// The EVS volumes also do not support being mounted to multiple instances at the same time.
 	volumeId := d.Get("volume_id").(string)
-	config.MutexKV.Lock(volumeId)
-	defer config.MutexKV.Unlock(volumeId)
+	unlock, err := lockVolumeAttachment(ctx, instanceId, volumeId, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	var device string
 	if v, ok := d.GetOk("device"); ok {
@@ -170,14 +190,15 @@ func resourceComputeVolumeAttachDelete(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("Error creating compute V1 client: %s", err)
 	}
 
-	// The ECS instances do not support unmounting multiple volumes at the same time.
+	// The ECS instances do not support unmounting multiple volumes at the same time, and the EVS volumes also do not
+	// support being unmounted from multiple instances at the same time.
 	instanceId := d.Get("instance_id").(string)
-	config.MutexKV.Lock(instanceId)
-	defer config.MutexKV.Unlock(instanceId)
-	// The EVS volumes also do not support being unmounted from multiple instances at the same time.
 	volumeId := d.Get("volume_id").(string)
-	config.MutexKV.Lock(volumeId)
-	defer config.MutexKV.Unlock(volumeId)
+	unlock, err := lockVolumeAttachment(ctx, instanceId, volumeId, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	opts := block_devices.DetachOpts{
 		ServerId: instanceId,
@@ -702,7 +702,7 @@ func resourceVpcEipUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(vpcV2Client, d, "publicips", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPC (%s): %s", d.Id(), tagErr)
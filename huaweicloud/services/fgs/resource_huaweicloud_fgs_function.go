@@ -855,7 +855,7 @@ func resourceFgsFunctionRead(_ context.Context, d *schema.ResourceData, meta int
 
 func updateFunctionTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
 	var (
-		oRaw, nRaw  = d.GetChange("tags")
+		oRaw, nRaw  = utils.GetTagsChange(d)
 		oMap        = oRaw.(map[string]interface{})
 		nMap        = nRaw.(map[string]interface{})
 		functionUrn = d.Id()
@@ -1115,7 +1115,7 @@ func resourceFgsFunctionUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		if err = updateFunctionTags(fgsClient, d); err != nil {
 			return diag.FromErr(err)
 		}
@@ -681,7 +681,7 @@ func resourceGeminiDBInstanceV3Update(ctx context.Context, d *schema.ResourceDat
 	}
 	// update tags
 	instanceId := d.Id()
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of GeminiDB %q: %s", instanceId, tagErr)
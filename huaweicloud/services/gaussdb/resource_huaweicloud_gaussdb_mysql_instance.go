@@ -1098,7 +1098,7 @@ func resourceGaussDBInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of Gaussdb mysql instance %q: %s", instanceId, tagErr)
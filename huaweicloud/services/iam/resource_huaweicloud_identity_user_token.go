@@ -12,6 +12,7 @@ import (
 	"github.com/chnsz/golangsdk"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
@@ -44,10 +45,23 @@ func ResourceIdentityUserToken() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 			"token": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"encrypted_token": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"expires_at": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -91,7 +105,10 @@ func createToken(client *golangsdk.ServiceClient, d *schema.ResourceData) error
 		return fmt.Errorf("error retrieving IAM user token: %s", err)
 	}
 
-	d.Set("token", createTokenResp.Header.Get("X-Subject-Token"))
+	err = encryption.SetSensitiveValue(d, "token", createTokenResp.Header.Get("X-Subject-Token"), "IAM user token")
+	if err != nil {
+		return fmt.Errorf("error saving IAM user token: %s", err)
+	}
 
 	createTokenRespBody, err := utils.FlattenResponse(createTokenResp)
 	if err != nil {
@@ -302,7 +302,7 @@ func resourceImsImageCopyUpdate(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(imsClient, d, "images", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of IMS image :%s, err:%s", d.Id(), tagErr)
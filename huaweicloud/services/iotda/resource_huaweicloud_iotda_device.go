@@ -344,8 +344,8 @@ func ResourceDeviceUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	// tags
-	if d.HasChange("tags") {
-		o, n := d.GetChange("tags")
+	if utils.HasTagsChange(d) {
+		o, n := utils.GetTagsChange(d)
 		err = bindDeviceTags(client, d.Id(), o.(map[string]interface{}), n.(map[string]interface{}))
 		if err != nil {
 			return diag.Errorf("error updating the tags of IoTDA device: %s", err)
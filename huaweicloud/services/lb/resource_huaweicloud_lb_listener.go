@@ -333,7 +333,7 @@ func resourceListenerV2Update(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(lbv2Client, d, "listeners", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of ELB listener:%s, err:%s", d.Id(), tagErr)
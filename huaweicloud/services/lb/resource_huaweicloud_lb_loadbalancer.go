@@ -343,7 +343,7 @@ func resourceLoadBalancerV2Update(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		elbV2Client, err := cfg.ElbV2Client(region)
 		if err != nil {
 			return diag.Errorf("error creating ELB v2.0 client: %s", err)
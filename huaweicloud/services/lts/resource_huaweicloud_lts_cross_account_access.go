@@ -278,7 +278,7 @@ func resourceCrossAccountAccessUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.Errorf("error creating LTS client: %s", err)
 	}
 
-	if d.HasChanges("tags") {
+	if utils.HasTagsChange(d) {
 		tagsPath := ltsClient.Endpoint + tagsHttpUrl
 		tagsPath = strings.ReplaceAll(tagsPath, "{project_id}", ltsClient.ProjectID)
 		tagsPath = strings.ReplaceAll(tagsPath, "{resource_type}", "ltsAccessConfig")
@@ -123,7 +123,7 @@ func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, meta inter
 		TTL: d.Get("ttl_in_days").(int),
 	}
 
-	if d.HasChanges("tags") {
+	if utils.HasTagsChange(d) {
 		// NOTE: the key in tags can not be removed due to the API restrictions.
 		tagRaw := d.Get("tags").(map[string]interface{})
 		taglist := utils.ExpandResourceTags(tagRaw)
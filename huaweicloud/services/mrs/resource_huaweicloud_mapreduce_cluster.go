@@ -1346,7 +1346,7 @@ func resourceMRSClusterV2Update(ctx context.Context, d *schema.ResourceData, met
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := updateResourceTagsWithSleep(client, d, "clusters", clusterId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of MRS cluster:%s, err:%s", clusterId, tagErr)
@@ -1475,8 +1475,8 @@ func queryMrsClusterHosts(d *schema.ResourceData, mrsV1Client *golangsdk.Service
 }
 
 func updateResourceTagsWithSleep(conn *golangsdk.ServiceClient, d *schema.ResourceData, resourceType, id string) error {
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
+	if utils.HasTagsChange(d) {
+		oRaw, nRaw := utils.GetTagsChange(d)
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
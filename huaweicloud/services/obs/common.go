@@ -0,0 +1,429 @@
+package obs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+const (
+	// maxSinglePutSize is the max size of the object which can be uploaded by a single PUT request, the larger ones
+	// are always uploaded in parts.
+	maxSinglePutSize int64 = 5 * 1024 * 1024 * 1024
+	// maxPartCount is the max number of the parts of a multipart upload.
+	maxPartCount int64 = 10000
+
+	defaultPartSize    int64 = 100 * 1024 * 1024
+	defaultConcurrency       = 4
+)
+
+// objectUploadOpts is the options to upload a local file to an object.
+type objectUploadOpts struct {
+	obs.ObjectOperationInput
+	// PartSize is the size of each part in bytes, the file is uploaded in parts when it's larger than the part size.
+	// The file is uploaded by a single PUT request when the part size is 0 and the file is not larger than 5 GB.
+	PartSize int64
+	// Concurrency is the number of the parts uploaded at the same time.
+	Concurrency int
+}
+
+// uploadFileToObject uploads the local file to the object, and returns the version ID of the object.
+func uploadFileToObject(obsClient *obs.ObsClient, source string, opts objectUploadOpts) (string, error) {
+	stat, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+
+	if size := stat.Size(); (opts.PartSize > 0 && size > opts.PartSize) || size > maxSinglePutSize {
+		return uploadFileInParts(obsClient, source, size, opts)
+	}
+
+	putInput := &obs.PutFileInput{}
+	putInput.ObjectOperationInput = opts.ObjectOperationInput
+	putInput.SourceFile = source
+
+	log.Printf("[DEBUG] putting %s to OBS Bucket %s, opts: %#v", opts.Key, opts.Bucket, putInput)
+	resp, err := obsClient.PutFile(putInput)
+	if err != nil {
+		return "", err
+	}
+	return resp.VersionId, nil
+}
+
+// uploadFileInParts uploads the local file by the multipart upload, the parts are uploaded concurrently, and the
+// upload is aborted if any part fails.
+func uploadFileInParts(obsClient *obs.ObsClient, source string, size int64, opts objectUploadOpts) (string, error) {
+	bucket, key := opts.Bucket, opts.Key
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	// enlarge the part size if the file is too large to be uploaded in 10000 parts
+	if size > partSize*maxPartCount {
+		partSize = (size + maxPartCount - 1) / maxPartCount
+	}
+	partCount := int((size + partSize - 1) / partSize)
+	if partCount == 0 {
+		partCount = 1
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	initInput := &obs.InitiateMultipartUploadInput{
+		ObjectOperationInput: opts.ObjectOperationInput,
+		ContentType:          opts.ContentType,
+	}
+	log.Printf("[DEBUG] uploading %s to OBS Bucket %s in %d parts, opts: %#v", key, bucket, partCount, initInput)
+	initResp, err := obsClient.InitiateMultipartUpload(initInput)
+	if err != nil {
+		return "", fmt.Errorf("error initiating the multipart upload of %s: %s", key, err)
+	}
+	uploadID := initResp.UploadId
+
+	parts, err := uploadParts(obsClient, source, size, partSize, partCount, concurrency, opts.ObjectOperationInput,
+		uploadID)
+	if err != nil {
+		abortMultipartUpload(obsClient, bucket, key, uploadID)
+		return "", err
+	}
+
+	completeResp, err := obsClient.CompleteMultipartUpload(&obs.CompleteMultipartUploadInput{
+		Bucket:   bucket,
+		Key:      key,
+		UploadId: uploadID,
+		Parts:    parts,
+	})
+	if err != nil {
+		abortMultipartUpload(obsClient, bucket, key, uploadID)
+		return "", fmt.Errorf("error completing the multipart upload of %s: %s", key, err)
+	}
+
+	// the HTTP headers except Content-Type are not accepted by the initiating request, so set them afterwards
+	header := opts.HttpHeader
+	if header.CacheControl != "" || header.ContentDisposition != "" || header.ContentEncoding != "" ||
+		header.ContentLanguage != "" || header.HttpExpires != "" {
+		metaInput := &obs.SetObjectMetadataInput{
+			Bucket:                  bucket,
+			Key:                     key,
+			VersionId:               completeResp.VersionId,
+			MetadataDirective:       obs.ReplaceMetadata,
+			WebsiteRedirectLocation: opts.WebsiteRedirectLocation,
+			StorageClass:            opts.StorageClass,
+			Metadata:                opts.Metadata,
+			HttpHeader:              header,
+		}
+		if _, err := obsClient.SetObjectMetadata(metaInput); err != nil {
+			return "", fmt.Errorf("error setting the metadata of %s: %s", key, err)
+		}
+	}
+
+	return completeResp.VersionId, nil
+}
+
+func uploadParts(obsClient *obs.ObsClient, source string, size, partSize int64, partCount, concurrency int,
+	input obs.ObjectOperationInput, uploadID string) ([]obs.Part, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		parts    = make([]obs.Part, 0, partCount)
+		partNums = make(chan int)
+	)
+
+	for i := 0; i < concurrency && i < partCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNum := range partNums {
+				offset := int64(partNum-1) * partSize
+				currentSize := partSize
+				if offset+currentSize > size {
+					currentSize = size - offset
+				}
+
+				resp, err := obsClient.UploadPart(&obs.UploadPartInput{
+					Bucket:     input.Bucket,
+					Key:        input.Key,
+					PartNumber: partNum,
+					UploadId:   uploadID,
+					SseHeader:  input.SseHeader,
+					SourceFile: source,
+					Offset:     offset,
+					PartSize:   currentSize,
+				})
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("error uploading part %d of %s: %s", partNum, input.Key, err)
+					}
+				} else {
+					parts = append(parts, obs.Part{PartNumber: partNum, ETag: resp.ETag})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for partNum := 1; partNum <= partCount; partNum++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		partNums <- partNum
+	}
+	close(partNums)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+func abortMultipartUpload(obsClient *obs.ObsClient, bucket, key, uploadID string) {
+	_, err := obsClient.AbortMultipartUpload(&obs.AbortMultipartUploadInput{
+		Bucket:   bucket,
+		Key:      key,
+		UploadId: uploadID,
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to abort the multipart upload %s of %s: %s", uploadID, key, err)
+	}
+}
+
+// obsSignedSubResources are the query parameters included in the canonicalized resource of the OBS signature. They
+// are the same as the sub-resources signed by the SDK, plus the ones of the requests which the SDK doesn't support,
+// e.g. inventory and object-lock. The other parameters, e.g. the ID of the inventory configuration, are not signed.
+var obsSignedSubResources = map[string]bool{
+	"acl": true, "append": true, "backtosource": true, "cors": true, "customdomain": true, "delete": true,
+	"deletebucket": true, "encryption": true, "ignore-sign-in-query": true, "inventory": true, "lifecycle": true,
+	"location": true, "logging": true, "metadata": true, "mirrorbacktosource": true, "modify": true, "name": true,
+	"notification": true, "object-lock": true, "partnumber": true, "policy": true, "position": true, "quota": true,
+	"rename": true, "replication": true, "requestpayment": true, "response-cache-control": true,
+	"response-content-disposition": true, "response-content-encoding": true, "response-content-language": true,
+	"response-content-type": true, "response-expires": true, "restore": true, "storageclass": true,
+	"storageinfo": true, "storagepolicy": true, "tagging": true, "torrent": true, "uploadid": true, "uploads": true,
+	"versionid": true, "versioning": true, "versions": true, "website": true, "x-image-process": true,
+	"x-image-save-bucket": true, "x-image-save-object": true, "x-oss-process": true,
+}
+
+// obsRawClient sends the bucket requests which are not supported by the SDK, e.g. the WORM and inventory
+// configurations. The requests are addressed and signed in the same way as the SDK client with the OBS signature,
+// which is replaced with the V2 signature compatible with S3 if the buckets are addressed in path style.
+type obsRawClient struct {
+	httpClient    *http.Client
+	scheme        string
+	host          string
+	pathStyle     bool
+	headerPrefix  string
+	hashPrefix    string
+	accessKey     string
+	secretKey     string
+	securityToken string
+}
+
+func newObsRawClient(conf *config.Config, region string) (*obsRawClient, error) {
+	// the credentials are checked, and reloaded if they are expired, by creating the SDK client, so the client uses
+	// the same credentials as the SDK client
+	if _, err := conf.ObjectStorageClient(region); err != nil {
+		return nil, err
+	}
+	return buildObsRawClient(&conf.DomainClient.HTTPClient, conf.ObjectStorageEndpoint(region),
+		conf.AccessKey, conf.SecretKey, conf.SecurityToken)
+}
+
+// buildObsRawClient parses the endpoint in the same way as the SDK, the scheme defaults to https and the buckets are
+// addressed in path style if the endpoint is an IP address.
+func buildObsRawClient(httpClient *http.Client, endpoint, ak, sk, securityToken string) (*obsRawClient, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if index := strings.Index(endpoint, "?"); index > 0 {
+		endpoint = endpoint[:index]
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+		endpoint = "https://" + endpoint
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid OBS endpoint: %s", endpoint)
+	}
+	client := &obsRawClient{
+		httpClient:    httpClient,
+		scheme:        endpointURL.Scheme,
+		host:          endpointURL.Host,
+		pathStyle:     obs.IsIP(endpointURL.Hostname()),
+		headerPrefix:  "x-obs-",
+		hashPrefix:    "OBS",
+		accessKey:     ak,
+		secretKey:     sk,
+		securityToken: securityToken,
+	}
+	if client.pathStyle {
+		client.headerPrefix = "x-amz-"
+		client.hashPrefix = "AWS"
+	}
+	return client, nil
+}
+
+// newRequest builds the request to the sub-resources of the bucket, the body is encoded in XML.
+func (c *obsRawClient) newRequest(method, bucket string, params map[string]string, body interface{}) (*http.Request,
+	error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = xml.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	query := make([]string, 0, len(params))
+	for _, key := range sortedKeys(params) {
+		param := url.QueryEscape(key)
+		if value := params[key]; value != "" {
+			param = fmt.Sprintf("%s=%s", param, url.QueryEscape(value))
+		}
+		query = append(query, param)
+	}
+
+	requestURL := fmt.Sprintf("%s://%s.%s/", c.scheme, bucket, c.host)
+	if c.pathStyle {
+		requestURL = fmt.Sprintf("%s://%s/%s", c.scheme, c.host, bucket)
+	}
+	if len(query) > 0 {
+		requestURL += "?" + strings.Join(query, "&")
+	}
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Date", obs.FormatUtcToRfc1123(time.Now()))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+		req.Header.Set("Content-MD5", obs.Base64Md5(data))
+	}
+	if c.securityToken != "" {
+		req.Header.Set(c.headerPrefix+"security-token", c.securityToken)
+	}
+	return req, nil
+}
+
+// canonicalizedResource returns the resource part of the string to sign, the values of the sub-resources are signed
+// without being escaped.
+func (c *obsRawClient) canonicalizedResource(bucket string, params map[string]string) string {
+	signed := make([]string, 0, len(params))
+	for _, key := range sortedKeys(params) {
+		lowerKey := strings.ToLower(key)
+		if !obsSignedSubResources[lowerKey] && !strings.HasPrefix(lowerKey, c.headerPrefix) {
+			continue
+		}
+		param := url.QueryEscape(key)
+		if value := params[key]; value != "" {
+			param = fmt.Sprintf("%s=%s", param, value)
+		}
+		signed = append(signed, param)
+	}
+
+	resource := fmt.Sprintf("/%s/", bucket)
+	if c.pathStyle {
+		resource = "/" + bucket
+	}
+	if len(signed) > 0 {
+		resource += "?" + strings.Join(signed, "&")
+	}
+	return resource
+}
+
+// sign sets the authorization header of the request, the string to sign consists of the method, the Content-MD5,
+// Content-Type and Date headers, the OBS (or S3) headers and the canonicalized resource.
+func (c *obsRawClient) sign(req *http.Request, bucket string, params map[string]string) {
+	prefixedHeaders := make(map[string]string)
+	for key, values := range req.Header {
+		if lowerKey := strings.ToLower(key); strings.HasPrefix(lowerKey, c.headerPrefix) {
+			prefixedHeaders[lowerKey] = strings.Join(values, ",")
+		}
+	}
+
+	date := req.Header.Get("Date")
+	if _, ok := prefixedHeaders[c.headerPrefix+"date"]; ok {
+		date = ""
+	}
+	stringToSign := []string{req.Method, req.Header.Get("Content-MD5"), req.Header.Get("Content-Type"), date}
+	for _, key := range sortedKeys(prefixedHeaders) {
+		stringToSign = append(stringToSign, fmt.Sprintf("%s:%s", key, prefixedHeaders[key]))
+	}
+	stringToSign = append(stringToSign, c.canonicalizedResource(bucket, params))
+
+	signature := obs.Base64Encode(obs.HmacSha1([]byte(c.secretKey), []byte(strings.Join(stringToSign, "\n"))))
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s:%s", c.hashPrefix, c.accessKey, signature))
+}
+
+// Do sends the request to the sub-resources of the bucket, the body is encoded and the result is decoded in XML.
+// The errors returned by the server are converted to obs.ObsError.
+func (c *obsRawClient) Do(method, bucket string, params map[string]string, body, result interface{}) error {
+	req, err := c.newRequest(method, bucket, params, body)
+	if err != nil {
+		return err
+	}
+	c.sign(req, bucket, params)
+
+	log.Printf("[DEBUG] sending OBS request: %s %s", method, req.URL)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		obsError := obs.ObsError{}
+		if len(respBody) > 0 {
+			if err := xml.Unmarshal(respBody, &obsError); err != nil {
+				log.Printf("[WARN] failed to parse the OBS error: %s", err)
+			}
+		}
+		obsError.StatusCode = resp.StatusCode
+		obsError.Status = resp.Status
+		obsError.RequestId = resp.Header.Get("x-obs-request-id")
+		return obsError
+	}
+
+	if result != nil && len(respBody) > 0 {
+		return xml.Unmarshal(respBody, result)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,178 @@
+package obs
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+)
+
+const (
+	testAccessKey     = "AKTEST"
+	testSecretKey     = "SKTEST"
+	testSecurityToken = "token"
+)
+
+// TestObsRawClientSignatureWithSDK compares the requests and signatures with the ones of the SDK client. The buckets
+// are addressed in virtual host style with the OBS signature, and in path style with the V2 signature if the endpoint
+// is an IP address.
+func TestObsRawClientSignatureWithSDK(t *testing.T) {
+	var captured *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// all the requests, including the ones to the virtual hosts of the buckets, are sent to the test server
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+			},
+		},
+	}
+
+	for endpoint, hashPrefix := range map[string]string{"http://obs.test.example.com": "OBS ", server.URL: "AWS "} {
+		sdkClient, err := obs.New(testAccessKey, testSecretKey, endpoint, obs.WithSignature(obs.SignatureObs),
+			obs.WithSecurityToken(testSecurityToken), obs.WithHttpClient(httpClient), obs.WithMaxRetryCount(0))
+		if !assert.NoError(t, err) {
+			return
+		}
+		rawClient, err := buildObsRawClient(httpClient, endpoint, testAccessKey, testSecretKey, testSecurityToken)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		cases := []struct {
+			name   string
+			method string
+			params map[string]string
+			body   interface{}
+			send   func() error
+		}{
+			{
+				name:   "sub-resource",
+				method: "GET",
+				params: map[string]string{"notification": ""},
+				send: func() error {
+					_, err := sdkClient.GetBucketNotification("test-bucket")
+					return err
+				},
+			},
+			{
+				name:   "sub-resource with a value to be escaped",
+				method: "DELETE",
+				params: map[string]string{"customdomain": "www.example.com/a b"},
+				send: func() error {
+					_, err := sdkClient.DeleteBucketCustomDomain(&obs.DeleteBucketCustomDomainInput{
+						Bucket:       "test-bucket",
+						CustomDomain: "www.example.com/a b",
+					})
+					return err
+				},
+			},
+			{
+				name:   "body",
+				method: "PUT",
+				params: map[string]string{"tagging": ""},
+				body: obs.BucketTagging{
+					Tags: []obs.Tag{{Key: "foo", Value: "bar"}},
+				},
+				send: func() error {
+					input := &obs.SetBucketTaggingInput{Bucket: "test-bucket"}
+					input.Tags = []obs.Tag{{Key: "foo", Value: "bar"}}
+					_, err := sdkClient.SetBucketTagging(input)
+					return err
+				},
+			},
+		}
+
+		for _, c := range cases {
+			name := fmt.Sprintf("%s (%s)", c.name, endpoint)
+			captured = nil
+			if !assert.NoError(t, c.send(), name) || !assert.NotNil(t, captured, name) {
+				continue
+			}
+
+			req, err := rawClient.newRequest(c.method, "test-bucket", c.params, c.body)
+			if !assert.NoError(t, err, name) {
+				continue
+			}
+			assert.Equal(t, strings.Split(captured.Host, ":")[0], req.URL.Hostname(), name)
+			assert.Equal(t, captured.URL.Path, req.URL.Path, name)
+			assert.Equal(t, captured.URL.RawQuery, req.URL.RawQuery, name)
+
+			// sign the request at the same time as the SDK
+			for _, header := range []string{"Date", "Content-MD5", "Content-Type"} {
+				req.Header.Del(header)
+				if v := captured.Header.Get(header); v != "" {
+					req.Header.Set(header, v)
+				}
+			}
+			rawClient.sign(req, "test-bucket", c.params)
+			assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), hashPrefix), name)
+			assert.Equal(t, captured.Header.Get("Authorization"), req.Header.Get("Authorization"), name)
+		}
+		sdkClient.Close()
+	}
+}
+
+// TestObsRawClientSignature checks the signatures of the sub-resources which are not supported by the SDK with the
+// known strings to sign, the buckets are addressed in virtual host style.
+func TestObsRawClientSignature(t *testing.T) {
+	rawClient, err := buildObsRawClient(http.DefaultClient, "obs.cn-north-4.myhuaweicloud.com/", testAccessKey,
+		testSecretKey, testSecurityToken)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cases := []struct {
+		method       string
+		params       map[string]string
+		url          string
+		stringToSign string
+	}{
+		{
+			method: "PUT",
+			params: map[string]string{"inventory": "", "id": "test id"},
+			url:    "https://test-bucket.obs.cn-north-4.myhuaweicloud.com/?id=test+id&inventory",
+			stringToSign: "PUT\nMD5\napplication/xml\nSat, 01 Jan 2022 00:00:00 GMT\n" +
+				"x-obs-security-token:token\n/test-bucket/?inventory",
+		},
+		{
+			method: "GET",
+			params: map[string]string{"object-lock": ""},
+			url:    "https://test-bucket.obs.cn-north-4.myhuaweicloud.com/?object-lock",
+			stringToSign: "GET\n\n\nSat, 01 Jan 2022 00:00:00 GMT\n" +
+				"x-obs-security-token:token\n/test-bucket/?object-lock",
+		},
+	}
+
+	for _, c := range cases {
+		var body interface{}
+		if c.method == "PUT" {
+			body = obs.BucketTagging{}
+		}
+		req, err := rawClient.newRequest(c.method, "test-bucket", c.params, body)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, c.url, req.URL.String())
+
+		req.Header.Set("Date", "Sat, 01 Jan 2022 00:00:00 GMT")
+		if body != nil {
+			req.Header.Set("Content-MD5", "MD5")
+		}
+		rawClient.sign(req, "test-bucket", c.params)
+		signature := obs.Base64Encode(obs.HmacSha1([]byte(testSecretKey), []byte(c.stringToSign)))
+		assert.Equal(t, "OBS "+testAccessKey+":"+signature, req.Header.Get("Authorization"))
+	}
+}
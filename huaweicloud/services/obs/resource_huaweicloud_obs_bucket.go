@@ -60,6 +60,8 @@ import (
 // @API OBS DELETE ?cors
 // @API OBS PUT ?cors
 // @API OBS GET ?cors
+// @API OBS PUT ?object-lock
+// @API OBS GET ?object-lock
 // @API EPS POST /v1.0/enterprise-projects/{enterprise_project_id}/resources-migrate
 func ResourceObsBucket() *schema.Resource {
 	return &schema.Resource{
@@ -374,6 +376,12 @@ func ResourceObsBucket() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"object_lock_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"object_lock_retention": objectLockRetentionSchema,
 
 			"bucket_domain_name": {
 				Type:     schema.TypeString,
@@ -411,7 +419,16 @@ func resourceObsBucketCreate(ctx context.Context, d *schema.ResourceData, meta i
 	}
 
 	log.Printf("[DEBUG] OBS bucket create opts: %#v", opts)
-	_, err = obsClient.CreateBucket(opts)
+	if d.Get("object_lock_enabled").(bool) {
+		// the object lock can only be enabled by the header with the OBS signature when creating the bucket
+		obsClientWithSignature, sigErr := conf.ObjectStorageClientWithSignature(region)
+		if sigErr != nil {
+			return diag.Errorf("Error creating OBS client with signature: %s", sigErr)
+		}
+		_, err = obsClientWithSignature.CreateBucket(opts, obs.WithCustomHeader(objectLockEnabledHeader, "true"))
+	} else {
+		_, err = obsClient.CreateBucket(opts)
+	}
 	if err != nil {
 		return diag.FromErr(getObsError("Error creating bucket", bucket, err))
 	}
@@ -457,7 +474,7 @@ func resourceObsBucketUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		if err := resourceObsBucketTagsUpdate(obsClient, d); err != nil {
 			return diag.FromErr(err)
 		}
@@ -519,6 +536,16 @@ func resourceObsBucketUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
+	if d.HasChange("object_lock_retention") {
+		rawClient, err := newObsRawClient(conf, region)
+		if err != nil {
+			return diag.Errorf("Error creating OBS client: %s", err)
+		}
+		if err := resourceObsBucketObjectLockUpdate(rawClient, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceObsBucketRead(ctx, d, meta)
 }
 
@@ -554,7 +581,9 @@ func resourceObsBucketRead(_ context.Context, d *schema.ResourceData, meta inter
 
 	mErr := &multierror.Error{}
 	// for import case
-	if _, ok := d.GetOk("bucket"); !ok {
+	_, ok := d.GetOk("bucket")
+	isImport := !ok
+	if isImport {
 		mErr = multierror.Append(mErr, d.Set("bucket", bucket))
 	}
 
@@ -635,6 +664,17 @@ func resourceObsBucketRead(_ context.Context, d *schema.ResourceData, meta inter
 		return diag.FromErr(err)
 	}
 
+	// Read the object lock configuration, which can only be enabled when creating the bucket
+	if isImport || d.Get("object_lock_enabled").(bool) {
+		rawClient, err := newObsRawClient(conf, region)
+		if err != nil {
+			return diag.Errorf("Error creating OBS client: %s", err)
+		}
+		if err := setObsBucketObjectLock(rawClient, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,262 @@
+package obs
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+type inventoryConfiguration struct {
+	XMLName                xml.Name             `xml:"InventoryConfiguration"`
+	ID                     string               `xml:"Id"`
+	IsEnabled              bool                 `xml:"IsEnabled"`
+	Filter                 *inventoryFilter     `xml:"Filter,omitempty"`
+	Destination            inventoryDestination `xml:"Destination"`
+	Schedule               inventorySchedule    `xml:"Schedule"`
+	IncludedObjectVersions string               `xml:"IncludedObjectVersions"`
+	OptionalFields         []string             `xml:"OptionalFields>Field"`
+}
+
+type inventoryFilter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type inventoryDestination struct {
+	Format string `xml:"Format"`
+	Bucket string `xml:"Bucket"`
+	Prefix string `xml:"Prefix,omitempty"`
+}
+
+type inventorySchedule struct {
+	Frequency string `xml:"Frequency"`
+}
+
+// @API OBS PUT ?inventory
+// @API OBS GET ?inventory
+// @API OBS DELETE ?inventory
+func ResourceObsBucketInventory() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceObsBucketInventoryPut,
+		ReadContext:   resourceObsBucketInventoryRead,
+		UpdateContext: resourceObsBucketInventoryPut,
+		DeleteContext: resourceObsBucketInventoryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceObsBucketInventoryImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"configuration_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"frequency": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Daily", "Weekly"}, false),
+			},
+			"destination": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"prefix": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"format": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "CSV",
+							ValidateFunc: validation.StringInSlice([]string{"CSV"}, false),
+						},
+					},
+				},
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"filter_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"included_object_versions": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Current",
+				ValidateFunc: validation.StringInSlice([]string{"All", "Current"}, false),
+			},
+			"optional_fields": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{
+						"Size", "LastModifiedDate", "ETag", "StorageClass", "IsMultipartUploaded", "ReplicationStatus",
+						"EncryptionStatus",
+					}, false),
+				},
+			},
+		},
+	}
+}
+
+func buildInventoryConfiguration(d *schema.ResourceData) inventoryConfiguration {
+	destination := d.Get("destination").([]interface{})[0].(map[string]interface{})
+	result := inventoryConfiguration{
+		ID:        d.Get("configuration_id").(string),
+		IsEnabled: d.Get("enabled").(bool),
+		Destination: inventoryDestination{
+			Format: destination["format"].(string),
+			Bucket: destination["bucket"].(string),
+			Prefix: destination["prefix"].(string),
+		},
+		Schedule: inventorySchedule{
+			Frequency: d.Get("frequency").(string),
+		},
+		IncludedObjectVersions: d.Get("included_object_versions").(string),
+		OptionalFields:         utils.ExpandToStringListBySet(d.Get("optional_fields").(*schema.Set)),
+	}
+	if v, ok := d.GetOk("filter_prefix"); ok {
+		result.Filter = &inventoryFilter{Prefix: v.(string)}
+	}
+	return result
+}
+
+func inventoryParams(id string) map[string]string {
+	return map[string]string{"inventory": "", "id": id}
+}
+
+func resourceObsBucketInventoryPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	rawClient, err := newObsRawClient(conf, conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	inventory := buildInventoryConfiguration(d)
+	log.Printf("[DEBUG] setting inventory configuration of OBS bucket %s: %#v", bucket, inventory)
+	err = rawClient.Do("PUT", bucket, inventoryParams(inventory.ID), inventory, nil)
+	if err != nil {
+		return diag.FromErr(getObsError("Error setting inventory configuration of OBS bucket", bucket, err))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", bucket, inventory.ID))
+	return resourceObsBucketInventoryRead(ctx, d, meta)
+}
+
+func resourceObsBucketInventoryRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	region := conf.GetRegion(d)
+	rawClient, err := newObsRawClient(conf, region)
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	id := d.Get("configuration_id").(string)
+	var inventory inventoryConfiguration
+	err = rawClient.Do("GET", bucket, inventoryParams(id), nil, &inventory)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			return common.CheckDeletedDiag(d, golangsdk.ErrDefault404{}, "OBS bucket inventory")
+		}
+		return diag.FromErr(getObsError("Error getting inventory configuration of OBS bucket", bucket, err))
+	}
+	log.Printf("[DEBUG] getting inventory configuration %s of OBS bucket %s: %#v", id, bucket, inventory)
+
+	filterPrefix := ""
+	if inventory.Filter != nil {
+		filterPrefix = inventory.Filter.Prefix
+	}
+	destination := []map[string]interface{}{
+		{
+			"bucket": inventory.Destination.Bucket,
+			"prefix": inventory.Destination.Prefix,
+			"format": inventory.Destination.Format,
+		},
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("enabled", inventory.IsEnabled),
+		d.Set("frequency", inventory.Schedule.Frequency),
+		d.Set("destination", destination),
+		d.Set("filter_prefix", filterPrefix),
+		d.Set("included_object_versions", inventory.IncludedObjectVersions),
+		d.Set("optional_fields", inventory.OptionalFields),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting inventory configuration of OBS bucket %s: %s", bucket, err)
+	}
+	return nil
+}
+
+func resourceObsBucketInventoryDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	rawClient, err := newObsRawClient(conf, conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	id := d.Get("configuration_id").(string)
+	err = rawClient.Do("DELETE", bucket, inventoryParams(id), nil, nil)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			return nil
+		}
+		return diag.FromErr(getObsError("Error deleting inventory configuration of OBS bucket", bucket, err))
+	}
+	return nil
+}
+
+func resourceObsBucketInventoryImport(_ context.Context, d *schema.ResourceData,
+	_ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid format specified for OBS bucket inventory. Format must be <bucket>/<id>")
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("bucket", parts[0]),
+		d.Set("configuration_id", parts[1]),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("error setting attributes of OBS bucket inventory: %s", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,254 @@
+package obs
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+var notificationEvents = []string{
+	"ObjectCreated:*", "ObjectCreated:Put", "ObjectCreated:Post", "ObjectCreated:Copy",
+	"ObjectCreated:CompleteMultipartUpload", "ObjectRemoved:*", "ObjectRemoved:Delete",
+	"ObjectRemoved:DeleteMarkerCreated",
+}
+
+// notificationConfiguration is the notification configuration of the bucket, the FunctionGraph configurations are
+// not supported by the SDK.
+type notificationConfiguration struct {
+	XMLName                     xml.Name                     `xml:"NotificationConfiguration"`
+	TopicConfigurations         []topicConfiguration         `xml:"TopicConfiguration"`
+	FunctionGraphConfigurations []functionGraphConfiguration `xml:"FunctionGraphConfiguration"`
+}
+
+type topicConfiguration struct {
+	ID          string           `xml:"Id,omitempty"`
+	FilterRules []obs.FilterRule `xml:"Filter>Object>FilterRule"`
+	Topic       string           `xml:"Topic"`
+	Events      []string         `xml:"Event"`
+}
+
+type functionGraphConfiguration struct {
+	ID            string           `xml:"Id,omitempty"`
+	FilterRules   []obs.FilterRule `xml:"Filter>Object>FilterRule"`
+	FunctionGraph string           `xml:"FunctionGraph"`
+	Events        []string         `xml:"Event"`
+}
+
+func notificationTargetSchema(targetKey, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				targetKey: {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"events": {
+					Type:     schema.TypeList,
+					Required: true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice(notificationEvents, false),
+					},
+				},
+				"id": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+				},
+				"filter_prefix": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"filter_suffix": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// @API OBS PUT ?notification
+// @API OBS GET ?notification
+func ResourceObsBucketNotification() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceObsBucketNotificationPut,
+		ReadContext:   resourceObsBucketNotificationRead,
+		UpdateContext: resourceObsBucketNotificationPut,
+		DeleteContext: resourceObsBucketNotificationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"topic": notificationTargetSchema("topic_urn",
+				"The configurations of the events sent to the SMN topics."),
+			"function_graph": notificationTargetSchema("function_urn",
+				"The configurations of the events which trigger the FunctionGraph functions."),
+		},
+	}
+}
+
+func buildNotificationFilterRules(raw map[string]interface{}) []obs.FilterRule {
+	rules := make([]obs.FilterRule, 0, 2)
+	if v := raw["filter_prefix"].(string); v != "" {
+		rules = append(rules, obs.FilterRule{Name: "prefix", Value: v})
+	}
+	if v := raw["filter_suffix"].(string); v != "" {
+		rules = append(rules, obs.FilterRule{Name: "suffix", Value: v})
+	}
+	return rules
+}
+
+func buildNotificationConfiguration(d *schema.ResourceData) notificationConfiguration {
+	var result notificationConfiguration
+	for _, v := range d.Get("topic").([]interface{}) {
+		raw := v.(map[string]interface{})
+		result.TopicConfigurations = append(result.TopicConfigurations, topicConfiguration{
+			ID:          raw["id"].(string),
+			FilterRules: buildNotificationFilterRules(raw),
+			Topic:       raw["topic_urn"].(string),
+			Events:      utils.ExpandToStringList(raw["events"].([]interface{})),
+		})
+	}
+	for _, v := range d.Get("function_graph").([]interface{}) {
+		raw := v.(map[string]interface{})
+		result.FunctionGraphConfigurations = append(result.FunctionGraphConfigurations, functionGraphConfiguration{
+			ID:            raw["id"].(string),
+			FilterRules:   buildNotificationFilterRules(raw),
+			FunctionGraph: raw["function_urn"].(string),
+			Events:        utils.ExpandToStringList(raw["events"].([]interface{})),
+		})
+	}
+	return result
+}
+
+func resourceObsBucketNotificationPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	rawClient, err := newObsRawClient(conf, conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	notification := buildNotificationConfiguration(d)
+	log.Printf("[DEBUG] setting notification configuration of OBS bucket %s: %#v", bucket, notification)
+	err = rawClient.Do("PUT", bucket, map[string]string{"notification": ""}, notification, nil)
+	if err != nil {
+		return diag.FromErr(getObsError("Error setting notification configuration of OBS bucket", bucket, err))
+	}
+
+	d.SetId(bucket)
+	return resourceObsBucketNotificationRead(ctx, d, meta)
+}
+
+func flattenNotificationFilterRules(target map[string]interface{}, rules []obs.FilterRule) map[string]interface{} {
+	for _, rule := range rules {
+		switch rule.Name {
+		case "prefix":
+			target["filter_prefix"] = rule.Value
+		case "suffix":
+			target["filter_suffix"] = rule.Value
+		}
+	}
+	return target
+}
+
+func resourceObsBucketNotificationRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	region := conf.GetRegion(d)
+	rawClient, err := newObsRawClient(conf, region)
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Id()
+	var notification notificationConfiguration
+	err = rawClient.Do("GET", bucket, map[string]string{"notification": ""}, nil, &notification)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			d.SetId("")
+			return diag.Diagnostics{
+				diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Resource not found",
+					Detail:   fmt.Sprintf("OBS bucket(%s) not found", bucket),
+				},
+			}
+		}
+		return diag.FromErr(getObsError("Error getting notification configuration of OBS bucket", bucket, err))
+	}
+	log.Printf("[DEBUG] getting notification configuration of OBS bucket %s: %#v", bucket, notification)
+
+	topics := make([]map[string]interface{}, len(notification.TopicConfigurations))
+	for i, v := range notification.TopicConfigurations {
+		topics[i] = flattenNotificationFilterRules(map[string]interface{}{
+			"id":        v.ID,
+			"topic_urn": v.Topic,
+			"events":    v.Events,
+		}, v.FilterRules)
+	}
+	functions := make([]map[string]interface{}, len(notification.FunctionGraphConfigurations))
+	for i, v := range notification.FunctionGraphConfigurations {
+		functions[i] = flattenNotificationFilterRules(map[string]interface{}{
+			"id":           v.ID,
+			"function_urn": v.FunctionGraph,
+			"events":       v.Events,
+		}, v.FilterRules)
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("bucket", bucket),
+		d.Set("topic", topics),
+		d.Set("function_graph", functions),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting notification configuration of OBS bucket %s: %s", bucket, err)
+	}
+	return nil
+}
+
+func resourceObsBucketNotificationDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	rawClient, err := newObsRawClient(conf, conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	// an empty configuration removes all the notifications of the bucket
+	bucket := d.Id()
+	err = rawClient.Do("PUT", bucket, map[string]string{"notification": ""}, notificationConfiguration{}, nil)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			return nil
+		}
+		return diag.FromErr(getObsError("Error deleting notification configuration of OBS bucket", bucket, err))
+	}
+	return nil
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/chnsz/golangsdk/openstack/obs"
 
@@ -20,12 +22,13 @@ import (
 // @API OBS HEAD /
 // @API OBS HEAD /{ObjectName}
 // @API OBS PUT /{ObjectName}
+// @API OBS POST /{ObjectName}
 // @API OBS DELETE /{ObjectName}
 func ResourceObsBucketObject() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceObsBucketObjectPut,
 		ReadContext:   resourceObsBucketObjectRead,
-		UpdateContext: resourceObsBucketObjectPut,
+		UpdateContext: resourceObsBucketObjectUpdate,
 		DeleteContext: resourceObsBucketObjectDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceObsBucketObjectImport,
@@ -88,10 +91,62 @@ func ResourceObsBucketObject() *schema.Resource {
 				Computed: true,
 			},
 
+			"cache_control": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"content_disposition": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"content_encoding": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"content_language": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"website_redirect": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"metadata": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				// the keys are returned in lower case by the x-obs-meta-* headers
+				ValidateDiagFunc: validation.MapKeyMatch(regexp.MustCompile(`^[0-9a-z-]+$`),
+					"only lowercase letters, digits and hyphens are allowed in the metadata keys"),
+			},
+
+			"source_hash": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"part_size": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ValidateFunc:  validation.IntBetween(1, 5120),
+				ConflictsWith: []string{"content", "etag"},
+			},
+
+			"concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+
 			"etag": {
 				Type: schema.TypeString,
-				// This will conflict with server-side-encryption and multi-part upload
-				// if/when it's actually implemented. The Etag then won't match raw-file MD5.
+				// This will conflict with server-side-encryption and multipart upload,
+				// the Etag then won't match raw-file MD5.
 				Optional: true,
 				Computed: true,
 			},
@@ -110,7 +165,7 @@ func ResourceObsBucketObject() *schema.Resource {
 }
 
 func resourceObsBucketObjectPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	var resp *obs.PutObjectOutput
+	var versionID string
 	var err error
 
 	conf := meta.(*config.Config)
@@ -142,98 +197,99 @@ func resourceObsBucketObjectPut(ctx context.Context, d *schema.ResourceData, met
 		}
 
 		// put source file
-		resp, err = putFileToObject(obsClient, d)
+		versionID, err = putFileToObject(obsClient, d)
 	}
 
 	if content != "" {
 		// put content
-		resp, err = putContentToObject(obsClient, d)
+		versionID, err = putContentToObject(obsClient, d)
 	}
 
 	if err != nil {
 		return diag.FromErr(getObsError("Error putting object to OBS bucket", bucket, err))
 	}
-	if resp == nil {
-		return diag.Errorf("putting object to OBS bucket %s without null response", bucket)
-	}
 
-	log.Printf("[DEBUG] Response of putting %s to OBS Bucket %s: %#v", key, bucket, resp)
-	mErr := &multierror.Error{}
-	if resp.VersionId != "null" {
-		mErr = multierror.Append(mErr, d.Set("version_id", resp.VersionId))
-	} else {
-		mErr = multierror.Append(mErr, d.Set("version_id", ""))
+	log.Printf("[DEBUG] The version of %s put to OBS Bucket %s: %s", key, bucket, versionID)
+	if versionID == "null" {
+		versionID = ""
 	}
-	if mErr.ErrorOrNil() != nil {
-		return diag.Errorf("error saving versionId of OBS bucket %s: %s", bucket, mErr)
+	if err := d.Set("version_id", versionID); err != nil {
+		return diag.Errorf("error saving versionId of OBS bucket %s: %s", bucket, err)
 	}
 	d.SetId(key)
 
 	return resourceObsBucketObjectRead(ctx, d, meta)
 }
 
-func putContentToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (*obs.PutObjectOutput, error) {
-	bucket := d.Get("bucket").(string)
-	key := d.Get("key").(string)
-	content := d.Get("content").(string)
-
-	putInput := &obs.PutObjectInput{}
-	putInput.Bucket = bucket
-	putInput.Key = key
-
-	if v, ok := d.GetOk("acl"); ok {
-		putInput.ACL = obs.AclType(v.(string))
+func resourceObsBucketObjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the upload options do not change the object, so there is no need to upload it again
+	if !d.HasChangesExcept("part_size", "concurrency") {
+		return resourceObsBucketObjectRead(ctx, d, meta)
 	}
-	if v, ok := d.GetOk("storage_class"); ok {
-		putInput.StorageClass = obs.StorageClassType(v.(string))
-	}
-	if v, ok := d.GetOk("content_type"); ok {
-		putInput.ContentType = v.(string)
+	return resourceObsBucketObjectPut(ctx, d, meta)
+}
+
+// buildObjectOperationInput builds the common options of putting the object, including the ACL, storage class,
+// encryption, HTTP headers and the custom metadata.
+func buildObjectOperationInput(d *schema.ResourceData) obs.ObjectOperationInput {
+	input := obs.ObjectOperationInput{
+		Bucket:                  d.Get("bucket").(string),
+		Key:                     d.Get("key").(string),
+		ACL:                     obs.AclType(d.Get("acl").(string)),
+		StorageClass:            obs.StorageClassType(d.Get("storage_class").(string)),
+		WebsiteRedirectLocation: d.Get("website_redirect").(string),
+		HttpHeader: obs.HttpHeader{
+			ContentType:        d.Get("content_type").(string),
+			CacheControl:       d.Get("cache_control").(string),
+			ContentDisposition: d.Get("content_disposition").(string),
+			ContentEncoding:    d.Get("content_encoding").(string),
+			ContentLanguage:    d.Get("content_language").(string),
+		},
 	}
 
-	var sseKmsHeader = obs.SseKmsHeader{}
 	if d.Get("encryption").(bool) {
-		sseKmsHeader.Encryption = obs.DEFAULT_SSE_KMS_ENCRYPTION
-		sseKmsHeader.Key = d.Get("kms_key_id").(string)
-		putInput.SseHeader = sseKmsHeader
+		input.SseHeader = obs.SseKmsHeader{
+			Encryption: obs.DEFAULT_SSE_KMS_ENCRYPTION,
+			Key:        d.Get("kms_key_id").(string),
+		}
 	}
 
-	log.Printf("[DEBUG] putting %s to OBS Bucket %s, opts: %#v", key, bucket, putInput)
-	// do not log content
-	body := bytes.NewReader([]byte(content))
-	putInput.Body = body
-
-	return obsClient.PutObject(putInput)
+	if raw := d.Get("metadata").(map[string]interface{}); len(raw) > 0 {
+		input.Metadata = make(map[string]string, len(raw))
+		for k, v := range raw {
+			input.Metadata[k] = v.(string)
+		}
+	}
+	return input
 }
 
-func putFileToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (*obs.PutObjectOutput, error) {
+func putContentToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (string, error) {
 	bucket := d.Get("bucket").(string)
 	key := d.Get("key").(string)
+	content := d.Get("content").(string)
 
-	putInput := &obs.PutFileInput{}
-	putInput.Bucket = bucket
-	putInput.Key = key
-	putInput.SourceFile = d.Get("source").(string)
+	putInput := &obs.PutObjectInput{}
+	putInput.ObjectOperationInput = buildObjectOperationInput(d)
 
-	if v, ok := d.GetOk("acl"); ok {
-		putInput.ACL = obs.AclType(v.(string))
-	}
-	if v, ok := d.GetOk("storage_class"); ok {
-		putInput.StorageClass = obs.StorageClassType(v.(string))
-	}
-	if v, ok := d.GetOk("content_type"); ok {
-		putInput.ContentType = v.(string)
-	}
+	log.Printf("[DEBUG] putting %s to OBS Bucket %s, opts: %#v", key, bucket, putInput)
+	// do not log content
+	body := bytes.NewReader([]byte(content))
+	putInput.Body = body
 
-	var sseKmsHeader = obs.SseKmsHeader{}
-	if d.Get("encryption").(bool) {
-		sseKmsHeader.Encryption = obs.DEFAULT_SSE_KMS_ENCRYPTION
-		sseKmsHeader.Key = d.Get("kms_key_id").(string)
-		putInput.SseHeader = sseKmsHeader
+	resp, err := obsClient.PutObject(putInput)
+	if err != nil {
+		return "", err
 	}
+	return resp.VersionId, nil
+}
 
-	log.Printf("[DEBUG] putting %s to OBS Bucket %s, opts: %#v", key, bucket, putInput)
-	return obsClient.PutFile(putInput)
+func putFileToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (string, error) {
+	opts := objectUploadOpts{
+		ObjectOperationInput: buildObjectOperationInput(d),
+		PartSize:             int64(d.Get("part_size").(int)) * 1024 * 1024,
+		Concurrency:          d.Get("concurrency").(int),
+	}
+	return uploadFileToObject(obsClient, d.Get("source").(string), opts)
 }
 
 func resourceObsBucketObjectRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -276,6 +332,12 @@ func resourceObsBucketObjectRead(_ context.Context, d *schema.ResourceData, meta
 		d.Set("region", region),
 		d.Set("storage_class", class),
 		d.Set("content_type", objectMeta.ContentType),
+		d.Set("cache_control", objectMeta.CacheControl),
+		d.Set("content_disposition", objectMeta.ContentDisposition),
+		d.Set("content_encoding", objectMeta.ContentEncoding),
+		d.Set("content_language", objectMeta.ContentLanguage),
+		d.Set("website_redirect", objectMeta.WebsiteRedirectLocation),
+		d.Set("metadata", objectMeta.Metadata),
 		d.Set("version_id", objectMeta.VersionId),
 		d.Set("size", objectMeta.ContentLength),
 		d.Set("etag", strings.Trim(objectMeta.ETag, `"`)),
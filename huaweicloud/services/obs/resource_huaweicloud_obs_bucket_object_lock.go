@@ -0,0 +1,121 @@
+package obs
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+)
+
+const (
+	objectLockEnabled = "Enabled"
+	// objectLockModeCompliance is the only retention mode supported by OBS, the objects can not be overwritten or
+	// deleted by any user, including the root user, in the retention period.
+	objectLockModeCompliance = "COMPLIANCE"
+	objectLockEnabledHeader  = "x-obs-bucket-object-lock-enabled"
+)
+
+var objectLockRetentionSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 36500),
+				ExactlyOneOf: []string{"object_lock_retention.0.days", "object_lock_retention.0.years"},
+			},
+			"years": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+		},
+	},
+}
+
+type objectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *objectLockRule `xml:"Rule,omitempty"`
+}
+
+type objectLockRule struct {
+	DefaultRetention objectLockDefaultRetention `xml:"DefaultRetention"`
+}
+
+type objectLockDefaultRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+// resourceObsBucketObjectLockUpdate sets the default retention of the objects in the bucket, the object lock must be
+// enabled when the bucket is created.
+func resourceObsBucketObjectLockUpdate(rawClient *obsRawClient, d *schema.ResourceData) error {
+	bucket := d.Id()
+	retention := d.Get("object_lock_retention").([]interface{})
+	if len(retention) > 0 && !d.Get("object_lock_enabled").(bool) {
+		return fmt.Errorf("the object lock of OBS bucket %s must be enabled to set the default retention", bucket)
+	}
+
+	lockConfig := objectLockConfiguration{
+		ObjectLockEnabled: objectLockEnabled,
+	}
+	if len(retention) > 0 && retention[0] != nil {
+		rule := retention[0].(map[string]interface{})
+		lockConfig.Rule = &objectLockRule{
+			DefaultRetention: objectLockDefaultRetention{
+				Mode:  objectLockModeCompliance,
+				Days:  rule["days"].(int),
+				Years: rule["years"].(int),
+			},
+		}
+	}
+
+	log.Printf("[DEBUG] setting object lock configuration of OBS bucket %s: %#v", bucket, lockConfig)
+	err := rawClient.Do("PUT", bucket, map[string]string{"object-lock": ""}, lockConfig, nil)
+	if err != nil {
+		return getObsError("Error setting object lock configuration of OBS bucket", bucket, err)
+	}
+	return nil
+}
+
+func setObsBucketObjectLock(rawClient *obsRawClient, d *schema.ResourceData) error {
+	bucket := d.Id()
+	var lockConfig objectLockConfiguration
+	err := rawClient.Do("GET", bucket, map[string]string{"object-lock": ""}, nil, &lockConfig)
+	if err != nil {
+		obsError, ok := err.(obs.ObsError)
+		if !ok || obsError.StatusCode != 404 {
+			return getObsError("Error getting object lock configuration of OBS bucket", bucket, err)
+		}
+		// the object lock is not enabled
+		lockConfig = objectLockConfiguration{}
+	}
+	log.Printf("[DEBUG] getting object lock configuration of OBS bucket %s: %#v", bucket, lockConfig)
+
+	retention := make([]map[string]interface{}, 0, 1)
+	if lockConfig.Rule != nil {
+		retention = append(retention, map[string]interface{}{
+			"days":  lockConfig.Rule.DefaultRetention.Days,
+			"years": lockConfig.Rule.DefaultRetention.Years,
+		})
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("object_lock_enabled", lockConfig.ObjectLockEnabled == objectLockEnabled),
+		d.Set("object_lock_retention", retention),
+	)
+	if mErr.ErrorOrNil() != nil {
+		return fmt.Errorf("error saving object lock configuration of OBS bucket %s: %s", bucket, mErr)
+	}
+	return nil
+}
@@ -0,0 +1,556 @@
+package obs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// maxDeleteObjects is the max number of the objects deleted by a single request.
+const maxDeleteObjects = 1000
+
+// syncSettingKeys are the arguments which apply to all the objects, all files are uploaded again once they change.
+var syncSettingKeys = []string{
+	"acl", "storage_class", "cache_control", "content_types", "encryption", "kms_key_id",
+}
+
+// @API OBS HEAD /
+// @API OBS GET /
+// @API OBS PUT /{ObjectName}
+// @API OBS POST /{ObjectName}
+// @API OBS POST /
+func ResourceObsBucketSync() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceObsBucketSyncCreate,
+		ReadContext:   resourceObsBucketSyncRead,
+		UpdateContext: resourceObsBucketSyncUpdate,
+		DeleteContext: resourceObsBucketSyncDelete,
+
+		CustomizeDiff: resourceObsBucketSyncCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source_dir": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"exclude": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"delete_stale": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"content_types": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"cache_control": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"acl": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"storage_class": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"encryption": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"kms_key_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"part_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 5120),
+			},
+			"concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"files": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// localFile is a file in the source directory which is synced to the object with the key.
+type localFile struct {
+	Key  string
+	Path string
+	Hash string
+}
+
+// syncPrefix returns the prefix of the object keys, which always ends with a slash unless it's empty.
+func syncPrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return prefix + "/"
+	}
+	return prefix
+}
+
+func isExcludedPath(relPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, relPath); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(relPath)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func fileMD5(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// scanSourceDir walks the source directory and returns all the regular files except the excluded ones, the hash of
+// each file is the hex encoded MD5 of its content, which is the same as the ETag of the object uploaded by a single
+// PUT request without the server-side encryption.
+func scanSourceDir(sourceDir, prefix string, excludes []string) ([]localFile, error) {
+	files := make([]localFile, 0)
+	err := filepath.WalkDir(sourceDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(sourceDir, filePath)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if isExcludedPath(rel, excludes) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		// follow the symbolic links of the files
+		stat, err := os.Stat(filePath)
+		if err != nil {
+			return err
+		}
+		if !stat.Mode().IsRegular() {
+			return nil
+		}
+
+		hash, err := fileMD5(filePath)
+		if err != nil {
+			return err
+		}
+		files = append(files, localFile{
+			Key:  prefix + rel,
+			Path: filePath,
+			Hash: hash,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning the source directory %s: %s", sourceDir, err)
+	}
+	return files, nil
+}
+
+func scanSourceDirByResource(d interface{ Get(string) interface{} }) ([]localFile, error) {
+	prefix := syncPrefix(d.Get("prefix").(string))
+	excludes := utils.ExpandToStringList(d.Get("exclude").([]interface{}))
+	return scanSourceDir(d.Get("source_dir").(string), prefix, excludes)
+}
+
+func localFileHashes(files []localFile) map[string]interface{} {
+	result := make(map[string]interface{}, len(files))
+	for _, f := range files {
+		result[f.Key] = f.Hash
+	}
+	return result
+}
+
+// resourceObsBucketSyncCustomizeDiff plans the changes of the local files, which are not detected by the arguments.
+func resourceObsBucketSyncCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("source_dir") || !d.NewValueKnown("prefix") || !d.NewValueKnown("exclude") {
+		return d.SetNewComputed("files")
+	}
+
+	files, err := scanSourceDirByResource(d)
+	if err != nil {
+		return err
+	}
+
+	hashes := localFileHashes(files)
+	if !reflect.DeepEqual(d.Get("files").(map[string]interface{}), hashes) {
+		return d.SetNew("files", hashes)
+	}
+	return nil
+}
+
+// syncContentType returns the content type of the file, the types specified by the extensions take precedence over
+// the detected ones.
+func syncContentType(filePath string, contentTypes map[string]interface{}) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filePath), "."))
+	for k, v := range contentTypes {
+		if strings.ToLower(strings.TrimPrefix(k, ".")) == ext {
+			return v.(string)
+		}
+	}
+	if contentType, ok := obs.GetContentType(filePath); ok {
+		return contentType
+	}
+	return ""
+}
+
+func resourceObsBucketSyncCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	obsClient, err := conf.ObjectStorageClient(conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	_, err = obsClient.HeadBucket(bucket)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			return diag.Errorf("OBS bucket(%s) not found", bucket)
+		}
+		return diag.Errorf("error reading OBS bucket %s: %s", bucket, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", bucket, syncPrefix(d.Get("prefix").(string))))
+	if err := syncBucketObjects(obsClient, d, map[string]interface{}{}, true); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceObsBucketSyncRead(ctx, d, meta)
+}
+
+func resourceObsBucketSyncUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	obsClient, err := conf.ObjectStorageClient(conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	oldFiles, _ := d.GetChange("files")
+	err = syncBucketObjects(obsClient, d, oldFiles.(map[string]interface{}), d.HasChanges(syncSettingKeys...))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceObsBucketSyncRead(ctx, d, meta)
+}
+
+// syncBucketObjects uploads the new and changed files and deletes the objects of which the files are removed, the
+// synced objects are saved to the "files" attribute even if some of them fail, so that they are retried next time.
+func syncBucketObjects(obsClient *obs.ObsClient, d *schema.ResourceData, oldFiles map[string]interface{},
+	uploadAll bool) error {
+	bucket := d.Get("bucket").(string)
+	files, err := scanSourceDirByResource(d)
+	if err != nil {
+		return err
+	}
+
+	uploads := make([]localFile, 0)
+	for _, f := range files {
+		if uploadAll || oldFiles[f.Key] != f.Hash {
+			uploads = append(uploads, f)
+		}
+	}
+	hashes := localFileHashes(files)
+	deletes := make([]string, 0)
+	for key := range oldFiles {
+		if _, ok := hashes[key]; !ok {
+			deletes = append(deletes, key)
+		}
+	}
+
+	result := make(map[string]interface{}, len(oldFiles))
+	for k, v := range oldFiles {
+		result[k] = v
+	}
+
+	mErr := &multierror.Error{}
+	uploaded, err := uploadSyncFiles(obsClient, d, uploads)
+	if err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	for _, f := range uploaded {
+		result[f.Key] = f.Hash
+	}
+
+	log.Printf("[DEBUG] the stale objects of OBS bucket %s will be deleted: %v", bucket, deletes)
+	deleted, err := deleteObjectsByKeys(obsClient, bucket, deletes)
+	if err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	for _, key := range deleted {
+		delete(result, key)
+	}
+
+	if err := d.Set("files", result); err != nil {
+		mErr = multierror.Append(mErr, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// uploadSyncFiles uploads the files concurrently, and returns the files uploaded successfully.
+func uploadSyncFiles(obsClient *obs.ObsClient, d *schema.ResourceData, files []localFile) ([]localFile, error) {
+	concurrency := d.Get("concurrency").(int)
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	partSize := int64(d.Get("part_size").(int)) * 1024 * 1024
+	contentTypes := d.Get("content_types").(map[string]interface{})
+	baseInput := obs.ObjectOperationInput{
+		Bucket:       d.Get("bucket").(string),
+		ACL:          obs.AclType(d.Get("acl").(string)),
+		StorageClass: obs.StorageClassType(d.Get("storage_class").(string)),
+		HttpHeader: obs.HttpHeader{
+			CacheControl: d.Get("cache_control").(string),
+		},
+	}
+	if d.Get("encryption").(bool) {
+		baseInput.SseHeader = obs.SseKmsHeader{
+			Encryption: obs.DEFAULT_SSE_KMS_ENCRYPTION,
+			Key:        d.Get("kms_key_id").(string),
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		mErr     = &multierror.Error{}
+		uploaded = make([]localFile, 0, len(files))
+		queue    = make(chan localFile)
+	)
+	for i := 0; i < concurrency && i < len(files); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range queue {
+				opts := objectUploadOpts{
+					ObjectOperationInput: baseInput,
+					PartSize:             partSize,
+					Concurrency:          concurrency,
+				}
+				opts.Key = f.Key
+				opts.ContentType = syncContentType(f.Path, contentTypes)
+
+				_, err := uploadFileToObject(obsClient, f.Path, opts)
+
+				mu.Lock()
+				if err != nil {
+					mErr = multierror.Append(mErr, fmt.Errorf("error uploading %s to %s: %s", f.Path, f.Key, err))
+				} else {
+					uploaded = append(uploaded, f)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, f := range files {
+		queue <- f
+	}
+	close(queue)
+	wg.Wait()
+
+	return uploaded, mErr.ErrorOrNil()
+}
+
+// deleteObjectsByKeys deletes the objects in batches, and returns the keys deleted successfully.
+func deleteObjectsByKeys(obsClient *obs.ObsClient, bucket string, keys []string) ([]string, error) {
+	deleted := make([]string, 0, len(keys))
+	for start := 0; start < len(keys); start += maxDeleteObjects {
+		end := start + maxDeleteObjects
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		objects := make([]obs.ObjectToDelete, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, obs.ObjectToDelete{Key: key})
+		}
+		output, err := obsClient.DeleteObjects(&obs.DeleteObjectsInput{
+			Bucket:  bucket,
+			Objects: objects,
+		})
+		if err != nil {
+			return deleted, getObsError("Error deleting objects of OBS bucket", bucket, err)
+		}
+		for _, object := range output.Deleteds {
+			deleted = append(deleted, object.Key)
+		}
+		if len(output.Errors) > 0 {
+			return deleted, fmt.Errorf("error deleting objects of OBS bucket %s: %#v", bucket, output.Errors)
+		}
+	}
+	return deleted, nil
+}
+
+// listObjectETags returns the ETags of all the objects with the prefix, the directory placeholders are skipped.
+func listObjectETags(obsClient *obs.ObsClient, bucket, prefix string) (map[string]string, error) {
+	result := make(map[string]string)
+	input := &obs.ListObjectsInput{
+		Bucket: bucket,
+	}
+	input.Prefix = prefix
+
+	for {
+		resp, err := obsClient.ListObjects(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range resp.Contents {
+			if strings.HasSuffix(content.Key, "/") {
+				continue
+			}
+			result[content.Key] = strings.Trim(content.ETag, `"`)
+		}
+		if !resp.IsTruncated {
+			return result, nil
+		}
+		input.Marker = resp.NextMarker
+	}
+}
+
+func resourceObsBucketSyncRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	region := conf.GetRegion(d)
+	obsClient, err := conf.ObjectStorageClient(region)
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	prefix := syncPrefix(d.Get("prefix").(string))
+	remote, err := listObjectETags(obsClient, bucket, prefix)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			d.SetId("")
+			return diag.Diagnostics{
+				diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Resource not found",
+					Detail:   fmt.Sprintf("OBS bucket %s not found", bucket),
+				},
+			}
+		}
+		return diag.FromErr(getObsError("Error listing objects of OBS bucket", bucket, err))
+	}
+
+	// The missing objects are removed and the modified objects are saved with their ETags, so that they are
+	// uploaded again. The ETags of the encrypted objects and the objects uploaded in parts are not the MD5.
+	encrypted := d.Get("encryption").(bool)
+	files := make(map[string]interface{})
+	for key, hash := range d.Get("files").(map[string]interface{}) {
+		etag, ok := remote[key]
+		if !ok {
+			continue
+		}
+		if !encrypted && !strings.Contains(etag, "-") && etag != hash.(string) {
+			files[key] = etag
+			continue
+		}
+		files[key] = hash
+	}
+	// the stale objects are saved with their ETags, so that they are deleted unless the same files exist
+	if d.Get("delete_stale").(bool) {
+		for key, etag := range remote {
+			if _, ok := files[key]; !ok {
+				files[key] = etag
+			}
+		}
+	}
+
+	mErr := multierror.Append(
+		d.Set("region", region),
+		d.Set("files", files),
+	)
+	if err = mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting OBS bucket sync fields: %s", err)
+	}
+	return nil
+}
+
+func resourceObsBucketSyncDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conf := meta.(*config.Config)
+	obsClient, err := conf.ObjectStorageClient(conf.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	files := d.Get("files").(map[string]interface{})
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+
+	log.Printf("[DEBUG] the synced objects of OBS bucket %s will be deleted: %v", bucket, keys)
+	if _, err := deleteObjectsByKeys(obsClient, bucket, keys); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -290,7 +290,7 @@ func resourceAccountUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		err = updateTags(d, updateAccountClient, accountsType, d.Id(), "tags")
 		if err != nil {
 			return diag.FromErr(err)
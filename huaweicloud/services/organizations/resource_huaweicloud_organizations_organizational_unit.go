@@ -214,7 +214,7 @@ func resourceOrganizationalUnitUpdate(ctx context.Context, d *schema.ResourceDat
 		}
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		err = updateTags(d, updateOrganizationalUnitClient, unitType, d.Id(), "tags")
 		if err != nil {
 			return diag.FromErr(err)
@@ -10,6 +10,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/rds/v3/instances"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/filters"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/hashcode"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
@@ -50,6 +51,7 @@ func DataSourceRdsInstances() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"filter": filters.Schema(),
 			"instances": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -238,6 +240,14 @@ func DataSourceRdsInstances() *schema.Resource {
 	}
 }
 
+// rdsInstancesQueryPaths are the filter paths sent to the API as the query parameters.
+var rdsInstancesQueryPaths = filters.QueryPaths{
+	"name":           "name",
+	"datastore_type": "db.[0].type",
+	"vpc_id":         "vpc_id",
+	"subnet_id":      "subnet_id",
+}
+
 func dataSourceRdsInstancesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*config.Config)
 	client, err := config.RdsV3Client(config.GetRegion(d))
@@ -246,11 +256,11 @@ func dataSourceRdsInstancesRead(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	listOpts := instances.ListOpts{
-		Name:          d.Get("name").(string),
+		Name:          rdsInstancesQueryPaths.Value(d, "name"),
 		Type:          d.Get("type").(string),
-		DataStoreType: d.Get("datastore_type").(string),
-		VpcId:         d.Get("vpc_id").(string),
-		SubnetId:      d.Get("subnet_id").(string),
+		DataStoreType: rdsInstancesQueryPaths.Value(d, "datastore_type"),
+		VpcId:         rdsInstancesQueryPaths.Value(d, "vpc_id"),
+		SubnetId:      rdsInstancesQueryPaths.Value(d, "subnet_id"),
 	}
 
 	pages, err := instances.List(client, listOpts).AllPages()
@@ -273,7 +283,6 @@ func dataSourceRdsInstancesRead(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	var instancesToSet []map[string]interface{}
-	var instancesIds []string
 
 	for _, item := range filteredInstances {
 		instanceInAll := item.(instances.RdsInstanceResponse)
@@ -294,7 +303,6 @@ func dataSourceRdsInstancesRead(ctx context.Context, d *schema.ResourceData, met
 		}
 
 		instanceID := instanceInAll.Id
-		instancesIds = append(instancesIds, instanceID)
 
 		// publicIps
 		publicIps := make([]interface{}, len(instanceInAll.PublicIps))
@@ -374,6 +382,15 @@ func dataSourceRdsInstancesRead(ctx context.Context, d *schema.ResourceData, met
 		instancesToSet = append(instancesToSet, instanceToSet)
 	}
 
+	instancesToSet, err = filters.Apply(d, instancesToSet)
+	if err != nil {
+		return fmtp.DiagErrorf("Error filtering RDS instances: %s", err)
+	}
+	instancesIds := make([]string, 0, len(instancesToSet))
+	for _, instance := range instancesToSet {
+		instancesIds = append(instancesIds, instance["id"].(string))
+	}
+
 	d.SetId(hashcode.Strings(instancesIds))
 	d.Set("instances", instancesToSet)
 
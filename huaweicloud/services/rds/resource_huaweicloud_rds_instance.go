@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/chnsz/golangsdk"
 	"github.com/chnsz/golangsdk/openstack/bss/v2/orders"
@@ -20,8 +21,11 @@ import (
 	"github.com/chnsz/golangsdk/openstack/rds/v3/instances"
 	"github.com/chnsz/golangsdk/openstack/rds/v3/securities"
 
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/rds/v3/model"
+
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/encryption"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/hashcode"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
@@ -54,6 +58,7 @@ type ctxType string
 // @API RDS PUT /v3/{project_id}/instances/{instance_id}/security-group
 // @API RDS POST /v3/{project_id}/instances/{instance_id}/password
 // @API RDS PUT /v3/{project_id}/instances/{instance_id}/binlog/clear-policy
+// @API RDS GET /v3/{project_id}/instances/{instance_id}/restore-time
 // @API RDS DELETE /v3/{project_id}/instances/{instance_id}
 // @API EPS POST /v1.0/enterprise-projects/{enterprise_project_id}/resources-migrat
 // @API BSS GET /v2/orders/customer-orders/details/{order_id}
@@ -77,7 +82,14 @@ func ResourceRdsInstance() *schema.Resource {
 			Default: schema.DefaultTimeout(15 * time.Minute),
 		},
 
+		CustomizeDiff: rdsInstanceVersionCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
+			// the ID is declared to be unknown in the plan of the major version upgrade
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"region": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -120,7 +132,6 @@ func ResourceRdsInstance() *schema.Resource {
 						"version": {
 							Type:     schema.TypeString,
 							Required: true,
-							ForceNew: true,
 						},
 						"password": {
 							Type:      schema.TypeString,
@@ -189,9 +200,16 @@ func ResourceRdsInstance() *schema.Resource {
 							ForceNew: true,
 						},
 						"backup_id": {
-							Type:     schema.TypeString,
-							Required: true,
-							ForceNew: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ExactlyOneOf: []string{"restore.0.backup_id", "restore.0.restore_time"},
+						},
+						"restore_time": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IsRFC3339Time,
 						},
 						"database_name": {
 							Type:     schema.TypeMap,
@@ -203,6 +221,8 @@ func ResourceRdsInstance() *schema.Resource {
 				},
 			},
 
+			"major_version_upgrade": majorVersionUpgradeSchema,
+
 			"vpc_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -290,6 +310,18 @@ func ResourceRdsInstance() *schema.Resource {
 				Optional: true,
 			},
 
+			"pgp_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"encrypted_password": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -395,6 +427,8 @@ func ResourceRdsInstance() *schema.Resource {
 				Computed: true,
 			},
 
+			"upgrade_check_report": upgradeCheckReportSchema,
+
 			"lower_case_table_names": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -436,6 +470,10 @@ func resourceRdsInstanceCreate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.Errorf("only MySQL database support SSL enable and disable")
 	}
 
+	if err := checkRdsInstanceRestoreTime(config, region, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	createOpts := instances.CreateOpts{
 		Name:                d.Get("name").(string),
 		FlavorRef:           d.Get("flavor").(string),
@@ -481,7 +519,11 @@ func resourceRdsInstanceCreate(ctx context.Context, d *schema.ResourceData, meta
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
 	// Add password here so it wouldn't go in the above log entry
-	createOpts.Password = d.Get("db.0.password").(string)
+	password, err := encryption.GetPassword(d, "db.0.password")
+	if err != nil {
+		return diag.Errorf("error generating the password of the RDS instance: %s", err)
+	}
+	createOpts.Password = password
 
 	res, err := instances.Create(client, createOpts).Extract()
 	if err != nil {
@@ -490,6 +532,10 @@ func resourceRdsInstanceCreate(ctx context.Context, d *schema.ResourceData, meta
 	d.SetId(res.Instance.Id)
 	instanceID := d.Id()
 
+	if err := encryption.EncryptToState(d, "encrypted_password", password, "RDS instance password"); err != nil {
+		return diag.Errorf("error saving the encrypted password of the RDS instance (%s): %s", instanceID, err)
+	}
+
 	// wait for order success
 	if res.OrderId != "" {
 		bssClient, err := config.BssV2Client(config.GetRegion(d))
@@ -773,6 +819,39 @@ func resourceRdsInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.Errorf("error creating RDS V3.1 client: %s", err)
 	}
 
+	var diags diag.Diagnostics
+	if d.HasChange("db.0.version") {
+		hcClient, err := config.HcRdsV3Client(region)
+		if err != nil {
+			return diag.Errorf("error creating RDS v3 client: %s", err)
+		}
+		upgradedID, privateIPChanged, err := upgradeRdsInstanceMajorVersion(ctx, d, client, hcClient)
+		if err != nil {
+			// keep the original version in the state so that the upgrade can be retried
+			d.Partial(true)
+			return diag.FromErr(err)
+		}
+		// the new instance is managed as allow_new_instance is required by the plan of the upgrade, and the ID is
+		// unknown in the plan
+		if originalID := d.Id(); upgradedID != originalID {
+			d.SetId(upgradedID)
+			if d.Get("major_version_upgrade.0.delete_original_instance").(bool) && privateIPChanged {
+				if err := deleteRdsInstance(ctx, d, config, client, originalID); err != nil {
+					return diag.Errorf("error deleting the original RDS instance (%s) after the major version "+
+						"upgrade, please delete it manually: %s", originalID, err)
+				}
+			} else {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "RDS instance replaced by the major version upgrade",
+					Detail: fmt.Sprintf("the major version upgrade is performed on a new instance (%s), the "+
+						"original instance (%s) is retained and still billed, it's not managed by Terraform any more "+
+						"and should be deleted after the new instance is verified", upgradedID, originalID),
+				})
+			}
+		}
+	}
+
 	instanceID := d.Id()
 
 	if err := updateRdsInstanceName(d, client, instanceID); err != nil {
@@ -831,7 +910,7 @@ func resourceRdsInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceID)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of RDS instance (%s): %s", instanceID, tagErr)
@@ -876,7 +955,7 @@ func resourceRdsInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	return resourceRdsInstanceRead(ctx, d, meta)
+	return append(diags, resourceRdsInstanceRead(ctx, d, meta)...)
 }
 
 func resourceRdsInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -886,7 +965,17 @@ func resourceRdsInstanceDelete(ctx context.Context, d *schema.ResourceData, meta
 		return diag.Errorf("error creating rds client: %s ", err)
 	}
 
-	id := d.Id()
+	if err := deleteRdsInstance(ctx, d, config, client, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// deleteRdsInstance deletes or unsubscribes the instance and waits for it to be deleted, it's also used to delete the
+// original instance after the major version upgrade.
+func deleteRdsInstance(ctx context.Context, d *schema.ResourceData, config *config.Config,
+	client *golangsdk.ServiceClient, id string) error {
+	var err error
 	log.Printf("[DEBUG] Deleting Instance %s", id)
 	if v, ok := d.GetOk("charging_mode"); ok && v.(string) == "prePaid" {
 		resourceIds := []string{id}
@@ -911,7 +1000,7 @@ func resourceRdsInstanceDelete(ctx context.Context, d *schema.ResourceData, meta
 			PollInterval: 10 * time.Second,
 		})
 		if err != nil {
-			return diag.Errorf("error unsubscribe RDS instance: %s", err)
+			return fmt.Errorf("error unsubscribe RDS instance: %s", err)
 		}
 	} else {
 		retryFunc := func() (interface{}, bool, error) {
@@ -929,7 +1018,7 @@ func resourceRdsInstanceDelete(ctx context.Context, d *schema.ResourceData, meta
 			PollInterval: 10 * time.Second,
 		})
 		if err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	}
 
@@ -944,7 +1033,7 @@ func resourceRdsInstanceDelete(ctx context.Context, d *schema.ResourceData, meta
 
 	_, err = stateConf.WaitForStateContext(ctx)
 	if err != nil {
-		return diag.Errorf(
+		return fmt.Errorf(
 			"error waiting for rds instance (%s) to be deleted: %s ",
 			id, err)
 	}
@@ -1045,12 +1134,64 @@ func buildRdsInstanceRestorePoint(d *schema.ResourceData) *instances.RestorePoin
 				BackupId:     v["backup_id"].(string),
 				DatabaseName: utils.ExpandToStringMap(v["database_name"].(map[string]interface{})),
 			}
+			if restoreTime := v["restore_time"].(string); restoreTime != "" {
+				// the format has been checked by the schema
+				t, _ := time.Parse(time.RFC3339, restoreTime)
+				restorePoint.Type = "timestamp"
+				restorePoint.RestoreTime = strconv.FormatInt(t.UnixMilli(), 10)
+			}
 			return &restorePoint
 		}
 	}
 	return nil
 }
 
+// checkRdsInstanceRestoreTime checks whether the restore time is in the restorable time windows of the source
+// instance, the windows of that day are returned in the error if not.
+func checkRdsInstanceRestoreTime(cfg *config.Config, region string, d *schema.ResourceData) error {
+	restoreTime := d.Get("restore.0.restore_time").(string)
+	if restoreTime == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, restoreTime)
+	if err != nil {
+		return fmt.Errorf("invalid restore time %s: %s", restoreTime, err)
+	}
+
+	hcClient, err := cfg.HcRdsV3Client(region)
+	if err != nil {
+		return fmt.Errorf("error creating RDS v3 client: %s", err)
+	}
+	instanceID := d.Get("restore.0.instance_id").(string)
+	date := t.UTC().Format("2006-01-02")
+	resp, err := hcClient.ListRestoreTimes(&model.ListRestoreTimesRequest{
+		InstanceId: instanceID,
+		Date:       utils.String(date),
+	})
+	if err != nil {
+		return fmt.Errorf("error getting the restorable time windows of RDS instance (%s): %s", instanceID, err)
+	}
+
+	windows := make([]string, 0)
+	if resp.RestoreTime != nil {
+		restoreMilli := t.UnixMilli()
+		for _, v := range *resp.RestoreTime {
+			if restoreMilli >= v.StartTime && restoreMilli <= v.EndTime {
+				return nil
+			}
+			windows = append(windows, fmt.Sprintf("%s ~ %s",
+				time.UnixMilli(v.StartTime).UTC().Format(time.RFC3339),
+				time.UnixMilli(v.EndTime).UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(windows) == 0 {
+		return fmt.Errorf("the restore time %s is invalid, there is no restorable time window of RDS instance (%s) "+
+			"on %s", restoreTime, instanceID, date)
+	}
+	return fmt.Errorf("the restore time %s is not in the restorable time windows of RDS instance (%s): %s",
+		restoreTime, instanceID, strings.Join(windows, ", "))
+}
+
 func buildRdsInstanceHaReplicationMode(d *schema.ResourceData) *instances.Ha {
 	var ha *instances.Ha
 	if v, ok := d.GetOk("ha_replication_mode"); ok {
@@ -1769,12 +1910,16 @@ func rdsInstanceStateRefreshFunc(client *golangsdk.ServiceClient, instanceID str
 
 func updateRdsRootPassword(ctx context.Context, d *schema.ResourceData, client *golangsdk.ServiceClient,
 	instanceID string) error {
-	if !d.HasChange("db.0.password") {
-		return nil
+	password, reset, err := encryption.GetUpdatedPassword(d, "db.0.password")
+	if err != nil {
+		return fmt.Errorf("error generating the root password: %s", err)
+	}
+	if !reset {
+		return encryptRdsRootPassword(d, password)
 	}
 
 	updateOpts := instances.RestRootPasswordOpts{
-		DbUserPwd: d.Get("db.0.password").(string),
+		DbUserPwd: password,
 	}
 
 	retryFunc := func() (interface{}, bool, error) {
@@ -1782,7 +1927,7 @@ func updateRdsRootPassword(ctx context.Context, d *schema.ResourceData, client *
 		retry, err := handleMultiOperationsError(err)
 		return nil, retry, err
 	}
-	_, err := common.RetryContextWithWaitForState(&common.RetryContextWithWaitForStateParam{
+	_, err = common.RetryContextWithWaitForState(&common.RetryContextWithWaitForStateParam{
 		Ctx:          ctx,
 		RetryFunc:    retryFunc,
 		WaitFunc:     rdsInstanceStateRefreshFunc(client, instanceID),
@@ -1794,6 +1939,17 @@ func updateRdsRootPassword(ctx context.Context, d *schema.ResourceData, client *
 	if err != nil {
 		return fmt.Errorf("error resetting the root password: %s", err)
 	}
+	return encryptRdsRootPassword(d, password)
+}
+
+// encryptRdsRootPassword saves the root password encrypted with the new pgp_key if the password or pgp_key is changed.
+func encryptRdsRootPassword(d *schema.ResourceData, password string) error {
+	if !d.HasChanges("db.0.password", "pgp_key") {
+		return nil
+	}
+	if err := encryption.EncryptToState(d, "encrypted_password", password, "RDS instance password"); err != nil {
+		return fmt.Errorf("error saving the encrypted root password: %s", err)
+	}
 	return nil
 }
 
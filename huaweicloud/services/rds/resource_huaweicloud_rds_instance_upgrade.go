@@ -0,0 +1,415 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk"
+
+	rdsv3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/rds/v3"
+	rds "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/rds/v3/model"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// @API RDS GET /v3/{project_id}/instances/{instance_id}/major-version/available-version
+// @API RDS POST /v3/{project_id}/instances/{instance_id}/major-version/inspection
+// @API RDS GET /v3/{project_id}/instances/{instance_id}/major-version/inspection-histories
+// @API RDS GET /v3/{project_id}/instances/{instance_id}/major-version/status
+// @API RDS POST /v3/{project_id}/instances/{instance_id}/major-version/upgrade
+// @API RDS GET /v3/{project_id}/instances/{instance_id}/major-version/upgrade-histories
+
+const (
+	statisticsBeforeChangePrivateIp = "before_change_private_ip"
+	statisticsAfterChangePrivateIp  = "after_change_private_ip"
+)
+
+var majorVersionUpgradeSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"allow_new_instance": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"delete_original_instance": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"is_change_private_ip": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"statistics_collection_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  statisticsBeforeChangePrivateIp,
+				ValidateFunc: validation.StringInSlice([]string{
+					statisticsBeforeChangePrivateIp, statisticsAfterChangePrivateIp,
+				}, false),
+			},
+		},
+	},
+}
+
+var upgradeCheckReportSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Computed: true,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"target_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"result": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"check_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expiration_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"detail": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	},
+}
+
+// isMajorVersionUpgradable returns whether the major version of the DB engine can be upgraded by the major version
+// upgrade APIs, which only support PostgreSQL. The upgrade of MySQL, e.g. from 5.7 to 8.0, is not provided by the
+// APIs, so it's rejected in the plan.
+func isMajorVersionUpgradable(dbType string) bool {
+	return strings.EqualFold(dbType, "postgresql")
+}
+
+// compareRdsVersion compares the numeric parts of the database versions, e.g. 5.7 and 8.0, and returns -1, 0 or 1
+// if the first one is lower than, equal to or higher than the second one.
+func compareRdsVersion(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var numA, numB int
+		if i < len(partsA) {
+			numA, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			numB, _ = strconv.Atoi(partsB[i])
+		}
+		if numA < numB {
+			return -1
+		}
+		if numA > numB {
+			return 1
+		}
+	}
+	return 0
+}
+
+// rdsInstanceVersionCustomizeDiff upgrades the PostgreSQL instances when db.version is increased and the new instance
+// produced by the upgrade is allowed, the instances without major_version_upgrade are still recreated. As the upgrade
+// may move the resource to the new instance, the ID is unknown in the plan, so the dependents to be replaced are
+// shown before the upgrade.
+func rdsInstanceVersionCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("db.0.version") {
+		return nil
+	}
+
+	_, upgradeConfigured := d.GetOk("major_version_upgrade")
+	if !upgradeConfigured {
+		return d.ForceNew("db.0.version")
+	}
+	if dbType := d.Get("db.0.type").(string); !isMajorVersionUpgradable(dbType) {
+		return fmt.Errorf("the major version upgrade of %s RDS instances is not supported, only PostgreSQL can be "+
+			"upgraded, remove major_version_upgrade to recreate the instance with the new version", dbType)
+	}
+
+	oldVersion, newVersion := d.GetChange("db.0.version")
+	if compareRdsVersion(newVersion.(string), oldVersion.(string)) < 0 {
+		return fmt.Errorf("the database version of the RDS instance can not be downgraded from %s to %s",
+			oldVersion, newVersion)
+	}
+	if !d.Get("major_version_upgrade.0.allow_new_instance").(bool) {
+		return fmt.Errorf("the major version upgrade of the RDS instance is performed on a new instance, " +
+			"major_version_upgrade.0.allow_new_instance must be true to manage the new instance after the upgrade")
+	}
+	if d.Get("major_version_upgrade.0.delete_original_instance").(bool) &&
+		!d.Get("major_version_upgrade.0.is_change_private_ip").(bool) {
+		return fmt.Errorf("major_version_upgrade.0.is_change_private_ip must be true to delete the original " +
+			"instance, otherwise the clients still connect to the original instance")
+	}
+
+	if err := d.SetNewComputed("id"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("upgrade_check_report")
+}
+
+// upgradeRdsInstanceMajorVersion upgrades the major version of the instance: the target version must be available,
+// the pre-check must pass and the upgrade task is polled until it succeeds. The upgrade which is running or has
+// succeeded, e.g. the previous apply is interrupted, is waited for instead of being performed again. It returns the
+// ID of the upgraded instance, which differs from the original one if the upgrade is performed on a new instance, and
+// whether the private IP of the original instance has been switched to the upgraded one.
+func upgradeRdsInstanceMajorVersion(ctx context.Context, d *schema.ResourceData, client *golangsdk.ServiceClient,
+	hcClient *rdsv3.RdsClient) (string, bool, error) {
+	instanceID := d.Id()
+	_, newVersion := d.GetChange("db.0.version")
+	targetVersion := newVersion.(string)
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	report, err := getRdsInstanceUpgradeReport(hcClient, instanceID, targetVersion)
+	if err != nil {
+		return "", false, err
+	}
+	if report == nil || report.Result == "failed" {
+		if err := requestRdsInstanceUpgrade(ctx, d, hcClient); err != nil {
+			return "", false, err
+		}
+	} else {
+		log.Printf("[DEBUG] The upgrade (%s) of RDS instance (%s) to %s is %s", report.Id, instanceID, targetVersion,
+			report.Result)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"running"},
+		Target:       []string{"success"},
+		Refresh:      rdsInstanceMajorVersionStatusRefreshFunc(hcClient, instanceID, "upgrade"),
+		Timeout:      timeout,
+		Delay:        30 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return "", false, fmt.Errorf("error waiting for RDS instance (%s) to be upgraded to %s: %s",
+			instanceID, targetVersion, err)
+	}
+
+	report, err = getRdsInstanceUpgradeReport(hcClient, instanceID, targetVersion)
+	if err != nil {
+		return "", false, err
+	}
+	upgradedID := instanceID
+	privateIPChanged := false
+	if report != nil && report.DstInstanceId != "" {
+		upgradedID = report.DstInstanceId
+		privateIPChanged = report.IsPrivateIpChanged
+	}
+
+	stateConf = &resource.StateChangeConf{
+		Target:       []string{"ACTIVE"},
+		Refresh:      rdsInstanceStateRefreshFunc(client, upgradedID),
+		Timeout:      timeout,
+		Delay:        10 * time.Second,
+		PollInterval: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return "", false, fmt.Errorf("error waiting for RDS instance (%s) to become active: %s", upgradedID, err)
+	}
+	return upgradedID, privateIPChanged, nil
+}
+
+// requestRdsInstanceUpgrade checks the target version and sends the upgrade request after the pre-check passes.
+func requestRdsInstanceUpgrade(ctx context.Context, d *schema.ResourceData, hcClient *rdsv3.RdsClient) error {
+	instanceID := d.Id()
+	oldVersion, newVersion := d.GetChange("db.0.version")
+	currentVersion := oldVersion.(string)
+	targetVersion := newVersion.(string)
+
+	if err := checkRdsInstanceAvailableVersion(hcClient, instanceID, targetVersion); err != nil {
+		return err
+	}
+
+	report, err := rdsInstanceUpgradePreCheck(ctx, hcClient, instanceID, targetVersion, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return err
+	}
+	if err := d.Set("upgrade_check_report", []map[string]interface{}{report}); err != nil {
+		return fmt.Errorf("error saving the upgrade check report of RDS instance (%s): %s", instanceID, err)
+	}
+
+	upgradeOpts := rds.UpgradePgMajorVersion{
+		TargetVersion:     targetVersion,
+		IsChangePrivateIp: true,
+	}
+	if raw := d.Get("major_version_upgrade").([]interface{}); len(raw) > 0 && raw[0] != nil {
+		upgradeOpts.IsChangePrivateIp = raw[0].(map[string]interface{})["is_change_private_ip"].(bool)
+		if upgradeOpts.IsChangePrivateIp {
+			upgradeOpts.StatisticsCollectionMode = utils.String(
+				raw[0].(map[string]interface{})["statistics_collection_mode"].(string))
+		}
+	} else {
+		upgradeOpts.StatisticsCollectionMode = utils.String(statisticsBeforeChangePrivateIp)
+	}
+
+	log.Printf("[DEBUG] Upgrading RDS instance (%s) from %s to %s: %#v", instanceID, currentVersion, targetVersion,
+		upgradeOpts)
+	_, err = hcClient.UpgradeDbMajorVersion(&rds.UpgradeDbMajorVersionRequest{
+		InstanceId: instanceID,
+		Body:       &upgradeOpts,
+	})
+	if err != nil {
+		return fmt.Errorf("error upgrading RDS instance (%s) to %s: %s", instanceID, targetVersion, err)
+	}
+	return nil
+}
+
+func checkRdsInstanceAvailableVersion(hcClient *rdsv3.RdsClient, instanceID, targetVersion string) error {
+	resp, err := hcClient.ShowAvailableVersion(&rds.ShowAvailableVersionRequest{InstanceId: instanceID})
+	if err != nil {
+		return fmt.Errorf("error getting the available versions of RDS instance (%s): %s", instanceID, err)
+	}
+	if resp.AvailableVersions == nil {
+		return fmt.Errorf("RDS instance (%s) can not be upgraded to %s, no available version is found",
+			instanceID, targetVersion)
+	}
+	for _, v := range *resp.AvailableVersions {
+		if v == targetVersion {
+			return nil
+		}
+	}
+	return fmt.Errorf("RDS instance (%s) can not be upgraded to %s, the available versions are: %s",
+		instanceID, targetVersion, strings.Join(*resp.AvailableVersions, ", "))
+}
+
+// rdsInstanceUpgradePreCheck performs the compatibility pre-check of the target version and returns the check report,
+// the detail of the report is returned in the error if the pre-check fails.
+func rdsInstanceUpgradePreCheck(ctx context.Context, hcClient *rdsv3.RdsClient, instanceID, targetVersion string,
+	timeout time.Duration) (map[string]interface{}, error) {
+	resp, err := hcClient.UpgradeDbMajorVersionPreCheck(&rds.UpgradeDbMajorVersionPreCheckRequest{
+		InstanceId: instanceID,
+		Body: &rds.PostgresqlPreCheckUpgradeMajorVersionReq{
+			TargetVersion: targetVersion,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating the upgrade pre-check of RDS instance (%s): %s", instanceID, err)
+	}
+	reportID := utils.StringValue(resp.ReportId)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"running"},
+		Target:       []string{"success", "failed"},
+		Refresh:      rdsInstanceMajorVersionStatusRefreshFunc(hcClient, instanceID, "check"),
+		Timeout:      timeout,
+		Delay:        10 * time.Second,
+		PollInterval: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return nil, fmt.Errorf("error waiting for the upgrade pre-check of RDS instance (%s): %s", instanceID, err)
+	}
+
+	report, err := getRdsInstanceCheckReport(hcClient, instanceID, targetVersion, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report["result"] != "success" {
+		return nil, fmt.Errorf("the upgrade pre-check (%s) of RDS instance (%s) to %s is %s, the report:\n%s",
+			report["id"], instanceID, targetVersion, report["result"], report["detail"])
+	}
+	return report, nil
+}
+
+func getRdsInstanceCheckReport(hcClient *rdsv3.RdsClient, instanceID, targetVersion,
+	reportID string) (map[string]interface{}, error) {
+	resp, err := hcClient.ListInspectionHistories(&rds.ListInspectionHistoriesRequest{
+		InstanceId:    instanceID,
+		TargetVersion: utils.String(targetVersion),
+		Order:         utils.String("DESC"),
+		SortField:     utils.String("check_time"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting the upgrade check reports of RDS instance (%s): %s", instanceID, err)
+	}
+	if resp.InspectionReports == nil || len(*resp.InspectionReports) == 0 {
+		return nil, fmt.Errorf("the upgrade check report of RDS instance (%s) is not found", instanceID)
+	}
+
+	// the latest report is used if the report ID is not returned by the pre-check
+	reports := *resp.InspectionReports
+	report := reports[0]
+	for _, v := range reports {
+		if v.Id == reportID {
+			report = v
+			break
+		}
+	}
+	return map[string]interface{}{
+		"id":              report.Id,
+		"target_version":  report.TargetVersion,
+		"result":          report.Result,
+		"check_time":      report.CheckTime,
+		"expiration_time": report.ExpirationTime,
+		"detail":          report.Detail,
+	}, nil
+}
+
+// getRdsInstanceUpgradeReport returns the latest major version upgrade report of the instance, nil is returned if
+// the instance has not been upgraded to the target version.
+func getRdsInstanceUpgradeReport(hcClient *rdsv3.RdsClient, instanceID,
+	targetVersion string) (*rds.UpgradeReports, error) {
+	resp, err := hcClient.ListUpgradeHistories(&rds.ListUpgradeHistoriesRequest{
+		InstanceId: instanceID,
+		Limit:      utils.Int32(1),
+		Order:      utils.String("DESC"),
+		SortField:  utils.String("start_time"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting the upgrade reports of RDS instance (%s): %s", instanceID, err)
+	}
+	if resp.UpgradeReports == nil || len(*resp.UpgradeReports) == 0 {
+		return nil, nil
+	}
+
+	report := (*resp.UpgradeReports)[0]
+	log.Printf("[DEBUG] The upgrade report of RDS instance (%s): %#v", instanceID, report)
+	if report.DstDatabaseVersion != targetVersion {
+		return nil, nil
+	}
+	return &report, nil
+}
+
+func rdsInstanceMajorVersionStatusRefreshFunc(hcClient *rdsv3.RdsClient, instanceID,
+	action string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := hcClient.ShowUpgradeDbMajorVersionStatus(&rds.ShowUpgradeDbMajorVersionStatusRequest{
+			InstanceId: instanceID,
+			Action:     action,
+		})
+		if err != nil {
+			return nil, "ERROR", err
+		}
+
+		status := strings.TrimSpace(utils.StringValue(resp.Status))
+		log.Printf("[DEBUG] The major version %s of RDS instance (%s) is %s", action, instanceID, status)
+		switch status {
+		case "success":
+			return resp, status, nil
+		case "failed":
+			// the failed pre-check is reported with the check report
+			if action == "check" {
+				return resp, status, nil
+			}
+			return resp, "ERROR", fmt.Errorf("the major version upgrade failed: %s", utils.StringValue(resp.Detail))
+		}
+		return resp, "running", nil
+	}
+}
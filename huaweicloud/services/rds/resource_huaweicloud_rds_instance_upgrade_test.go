@@ -0,0 +1,65 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRdsInstanceVersionCustomizeDiff(t *testing.T) {
+	buildState := func(dbType string) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "instance-id",
+			Attributes: map[string]string{
+				"id":           "instance-id",
+				"db.#":         "1",
+				"db.0.type":    dbType,
+				"db.0.version": "12",
+			},
+		}
+	}
+	buildConfig := func(dbType, version string, upgrade map[string]interface{}) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"db": []interface{}{
+				map[string]interface{}{"type": dbType, "version": version},
+			},
+		}
+		if upgrade != nil {
+			raw["major_version_upgrade"] = []interface{}{upgrade}
+		}
+		return terraform.NewResourceConfigRaw(raw)
+	}
+	allowNewInstance := map[string]interface{}{"allow_new_instance": true}
+
+	// the instances without major_version_upgrade are recreated
+	diff, err := ResourceRdsInstance().Diff(context.Background(), buildState("PostgreSQL"),
+		buildConfig("PostgreSQL", "14", nil), nil)
+	if assert.NoError(t, err) && assert.Contains(t, diff.Attributes, "db.0.version") {
+		assert.True(t, diff.Attributes["db.0.version"].RequiresNew)
+	}
+
+	// the major version upgrade of MySQL is rejected
+	_, err = ResourceRdsInstance().Diff(context.Background(), buildState("MySQL"),
+		buildConfig("MySQL", "8.0", allowNewInstance), nil)
+	assert.ErrorContains(t, err, "the major version upgrade of MySQL RDS instances is not supported")
+
+	_, err = ResourceRdsInstance().Diff(context.Background(), buildState("PostgreSQL"),
+		buildConfig("PostgreSQL", "14", map[string]interface{}{
+			"allow_new_instance":       true,
+			"is_change_private_ip":     false,
+			"delete_original_instance": true,
+		}), nil)
+	assert.ErrorContains(t, err, "is_change_private_ip must be true to delete the original instance")
+
+	// the PostgreSQL instances are upgraded and the ID is unknown in the plan
+	diff, err = ResourceRdsInstance().Diff(context.Background(), buildState("PostgreSQL"),
+		buildConfig("PostgreSQL", "14", allowNewInstance), nil)
+	if assert.NoError(t, err) && assert.Contains(t, diff.Attributes, "db.0.version") {
+		assert.False(t, diff.Attributes["db.0.version"].RequiresNew)
+		if assert.Contains(t, diff.Attributes, "id") {
+			assert.True(t, diff.Attributes["id"].NewComputed)
+		}
+	}
+}
@@ -228,7 +228,7 @@ func resourceResourceTagsUpdate(ctx context.Context, d *schema.ResourceData, met
 	var (
 		projectId        = d.Get("project_id").(string)
 		oldRes, newRes   = d.GetChange("resources")
-		oldTags, newTags = d.GetChange("tags")
+		oldTags, newTags = utils.GetTagsChange(d)
 	)
 
 	deleteOpts := tags.BatchOpts{
@@ -12,6 +12,7 @@ import (
 	"github.com/chnsz/golangsdk/openstack/networking/v1/vpcs"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/filters"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/hashcode"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
@@ -54,6 +55,7 @@ func DataSourceVpcs() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"filter": filters.Schema(),
 			"vpcs": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -100,6 +102,14 @@ func DataSourceVpcs() *schema.Resource {
 	}
 }
 
+// vpcsQueryPaths are the filter paths sent to the API as the query parameters.
+var vpcsQueryPaths = filters.QueryPaths{
+	"id":     "id",
+	"name":   "name",
+	"status": "status",
+	"cidr":   "cidr",
+}
+
 func dataSourceVpcsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	conf := meta.(*config.Config)
 	region := conf.GetRegion(d)
@@ -119,10 +129,10 @@ func dataSourceVpcsRead(_ context.Context, d *schema.ResourceData, meta interfac
 	}
 
 	listOpts := vpcs.ListOpts{
-		ID:                  d.Get("id").(string),
-		Name:                d.Get("name").(string),
-		Status:              d.Get("status").(string),
-		CIDR:                d.Get("cidr").(string),
+		ID:                  vpcsQueryPaths.Value(d, "id"),
+		Name:                vpcsQueryPaths.Value(d, "name"),
+		Status:              vpcsQueryPaths.Value(d, "status"),
+		CIDR:                vpcsQueryPaths.Value(d, "cidr"),
 		EnterpriseProjectID: conf.DataGetEnterpriseProjectID(d),
 	}
 
@@ -135,7 +145,6 @@ func dataSourceVpcsRead(_ context.Context, d *schema.ResourceData, meta interfac
 
 	var vpcs []map[string]interface{}
 	tagFilter := d.Get("tags").(map[string]interface{})
-	var ids []string
 	for _, vpcResource := range vpcList {
 		vpc := map[string]interface{}{
 			"id":                    vpcResource.ID,
@@ -170,7 +179,15 @@ func dataSourceVpcsRead(_ context.Context, d *schema.ResourceData, meta interfac
 		vpc["secondary_cidrs"] = res.Vpc.ExtendCidrs
 
 		vpcs = append(vpcs, vpc)
-		ids = append(ids, vpcResource.ID)
+	}
+
+	vpcs, err = filters.Apply(d, vpcs)
+	if err != nil {
+		return diag.Errorf("error filtering VPCs: %s", err)
+	}
+	ids := make([]string, 0, len(vpcs))
+	for _, vpc := range vpcs {
+		ids = append(ids, vpc["id"].(string))
 	}
 	log.Printf("[DEBUG] VPC List after filter, count: %d vpcs: %+v", len(vpcs), vpcs)
 
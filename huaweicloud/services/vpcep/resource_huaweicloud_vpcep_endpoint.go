@@ -252,7 +252,7 @@ func resourceVPCEndpointUpdate(ctx context.Context, d *schema.ResourceData, meta
 			return diag.Errorf("error updating VPC endpoint whitelist: %s", err)
 		}
 	}
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := utils.UpdateResourceTags(vpcepClient, d, tagVPCEP, d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPC endpoint %s: %s", d.Id(), tagErr)
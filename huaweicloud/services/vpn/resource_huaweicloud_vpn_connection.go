@@ -789,7 +789,7 @@ func resourceConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := updateTags(updateConnectionClient, d, "vpn-connection", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPN connection (%s): %s", d.Id(), tagErr)
@@ -287,7 +287,7 @@ func resourceCustomerGatewayUpdate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := updateTags(updateCustomerGatewayClient, d, "customer-gateway", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPN customer gateway (%s): %s", d.Id(), tagErr)
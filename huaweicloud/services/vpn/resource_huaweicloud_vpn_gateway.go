@@ -942,7 +942,7 @@ func resourceGatewayUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if utils.HasTagsChange(d) {
 		tagErr := updateTags(updateGatewayClient, d, "vpn-gateway", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPN gateway (%s): %s", d.Id(), tagErr)
@@ -1155,7 +1155,7 @@ func deleteGatewayWaitingForStateCompleted(ctx context.Context, d *schema.Resour
 }
 
 func updateTags(client *golangsdk.ServiceClient, d *schema.ResourceData, tagsType string, id string) error {
-	oRaw, nRaw := d.GetChange("tags")
+	oRaw, nRaw := utils.GetTagsChange(d)
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -13,20 +14,46 @@ import (
 const SysTagKeyEnterpriseProjectId = "_sys_enterprise_project_id"
 
 // CreateResourceTags is a helper to create the tags for a resource.
-// It expects the schema name must be "tags"
+// It expects the schema name must be "tags", the value of "tags_all" will be used if the resource has it.
 func CreateResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData, resourceType, id string) error {
-	if tagRaw := d.Get("tags").(map[string]interface{}); len(tagRaw) > 0 {
+	tagRaw, ok := d.Get("tags_all").(map[string]interface{})
+	if !ok || len(tagRaw) == 0 {
+		tagRaw = d.Get("tags").(map[string]interface{})
+	}
+
+	if len(tagRaw) > 0 {
 		tagList := ExpandResourceTags(tagRaw)
 		return tags.Create(client, resourceType, id, tagList).ExtractErr()
 	}
 	return nil
 }
 
+// tagsChangeKey returns "tags_all" if the resource has it, otherwise "tags".
+func tagsChangeKey(d *schema.ResourceData) string {
+	if _, ok := d.Get("tags_all").(map[string]interface{}); ok {
+		return "tags_all"
+	}
+	return "tags"
+}
+
+// HasTagsChange checks whether the effective tags of a resource are changed, a change of the provider default tags
+// only shows in "tags_all".
+func HasTagsChange(d *schema.ResourceData) bool {
+	return d.HasChanges("tags", tagsChangeKey(d))
+}
+
+// GetTagsChange returns the old and new effective tags of a resource, the changes of "tags_all" will be used if the
+// resource has it.
+func GetTagsChange(d *schema.ResourceData) (interface{}, interface{}) {
+	return d.GetChange(tagsChangeKey(d))
+}
+
 // UpdateResourceTags is a helper to update the tags for a resource.
-// It expects the tags field to be named "tags"
+// It expects the tags field to be named "tags", the changes of "tags_all" will be used if the resource has it.
 func UpdateResourceTags(conn *golangsdk.ServiceClient, d *schema.ResourceData, resourceType, id string) error {
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
+	if HasTagsChange(d) {
+		oRaw, nRaw := GetTagsChange(d)
+		// the ignored tags are never saved to the state by the provider, so they are not removed
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
@@ -95,6 +122,63 @@ func TagsToMap(tags []tags.ResourceTag) map[string]string {
 	return result
 }
 
+// MergeDefaultTags returns the effective tags of a resource, the resource tags take precedence over the default tags
+// which are configured in the provider.
+func MergeDefaultTags(defaultTags map[string]string, resourceTags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(defaultTags)+len(resourceTags))
+	for k, v := range defaultTags {
+		result[k] = v
+	}
+	for k, v := range resourceTags {
+		result[k] = v
+	}
+	return result
+}
+
+// RemoveDefaultTags returns the tags which should be saved in the "tags" field, a tag inherited from the default tags
+// will be removed unless it is also configured in the resource tags.
+func RemoveDefaultTags(defaultTags map[string]string, allTags, resourceTags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(allTags))
+	for k, v := range allTags {
+		if _, ok := resourceTags[k]; !ok {
+			if defaultVal, ok := defaultTags[k]; ok && defaultVal == v {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// IgnoreTags returns the tags without the ignored ones, a tag will be ignored if its key is one of the keys or starts
+// with one of the key prefixes.
+func IgnoreTags(tags map[string]interface{}, keys, keyPrefixes []string) map[string]interface{} {
+	if len(keys) == 0 && len(keyPrefixes) == 0 {
+		return tags
+	}
+
+	result := make(map[string]interface{}, len(tags))
+	for k, v := range tags {
+		if !IsTagKeyIgnored(k, keys, keyPrefixes) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// IsTagKeyIgnored checks whether the tag key is one of the keys or starts with one of the key prefixes.
+func IsTagKeyIgnored(key string, keys, keyPrefixes []string) bool {
+	if StrSliceContains(keys, key) {
+		return true
+	}
+	for _, prefix := range keyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // FlattenTagsToMap returns the list of tags into a map.
 func FlattenTagsToMap(tags interface{}) map[string]interface{} {
 	if tagArray, ok := tags.([]interface{}); ok {
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 const (
@@ -131,3 +134,102 @@ func TestAccFunction_IsUUID(t *testing.T) {
 		t.Logf("The processing result of IsUUID method meets expectation: %s", green(expected[i]))
 	}
 }
+
+func TestAccFunction_MergeDefaultTags(t *testing.T) {
+	var (
+		defaultTags  = map[string]string{"owner": "platform", "env": "test"}
+		resourceTags = map[string]interface{}{"env": "prod", "app": "web"}
+		expected     = map[string]interface{}{"owner": "platform", "env": "prod", "app": "web"}
+	)
+
+	testOutput := MergeDefaultTags(defaultTags, resourceTags)
+	if !reflect.DeepEqual(testOutput, expected) {
+		t.Fatalf("The processing result of MergeDefaultTags method is not as expected, want %s, but %s",
+			green(expected), yellow(testOutput))
+	}
+	t.Logf("The processing result of MergeDefaultTags method meets expectation: %s", green(expected))
+}
+
+func TestAccFunction_RemoveDefaultTags(t *testing.T) {
+	var (
+		defaultTags  = map[string]string{"owner": "platform", "env": "test", "team": "infra"}
+		allTags      = map[string]interface{}{"owner": "platform", "env": "prod", "app": "web", "team": "infra"}
+		resourceTags = map[string]interface{}{"env": "prod", "team": "infra"}
+		expected     = map[string]interface{}{"env": "prod", "app": "web", "team": "infra"}
+	)
+
+	testOutput := RemoveDefaultTags(defaultTags, allTags, resourceTags)
+	if !reflect.DeepEqual(testOutput, expected) {
+		t.Fatalf("The processing result of RemoveDefaultTags method is not as expected, want %s, but %s",
+			green(expected), yellow(testOutput))
+	}
+	t.Logf("The processing result of RemoveDefaultTags method meets expectation: %s", green(expected))
+}
+
+func TestAccFunction_HasTagsChange(t *testing.T) {
+	tagsSchema := map[string]*schema.Schema{
+		"tags":     {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"tags_all": {Type: schema.TypeMap, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	state := &terraform.InstanceState{
+		ID: "test",
+		Attributes: map[string]string{
+			"tags.%": "1", "tags.app": "web",
+			"tags_all.%": "2", "tags_all.app": "web", "tags_all.owner": "platform",
+		},
+	}
+	// only the default tags are changed
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"tags_all.owner": {Old: "platform", New: "finops"},
+		},
+	}
+
+	d, err := schema.InternalMap(tagsSchema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !HasTagsChange(d) {
+		t.Fatalf("The processing result of HasTagsChange method is not as expected, want %s, but %s",
+			green(true), yellow(false))
+	}
+	oldTags, newTags := GetTagsChange(d)
+	expected := map[string]interface{}{"app": "web", "owner": "finops"}
+	if !reflect.DeepEqual(newTags, expected) || oldTags.(map[string]interface{})["owner"] != "platform" {
+		t.Fatalf("The processing result of GetTagsChange method is not as expected, want %s, but %s",
+			green(expected), yellow(newTags))
+	}
+
+	// the resource without "tags_all"
+	delete(tagsSchema, "tags_all")
+	d, err = schema.InternalMap(tagsSchema).Data(&terraform.InstanceState{ID: "test"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HasTagsChange(d) {
+		t.Fatalf("The processing result of HasTagsChange method is not as expected, want %s, but %s",
+			green(false), yellow(true))
+	}
+	t.Logf("The processing result of HasTagsChange method meets expectation: %s", green(expected))
+}
+
+func TestAccFunction_IgnoreTags(t *testing.T) {
+	var (
+		tags = map[string]interface{}{
+			"owner":                         "platform",
+			"_sys_enterprise_project_id":    "0",
+			"CCE-Dynamic-Provisioning-Node": "node-id",
+			"finops:cost-center":            "1234",
+		}
+		keys        = []string{"_sys_enterprise_project_id", "CCE-Dynamic-Provisioning-Node"}
+		keyPrefixes = []string{"finops:"}
+		expected    = map[string]interface{}{"owner": "platform"}
+	)
+
+	testOutput := IgnoreTags(tags, keys, keyPrefixes)
+	if !reflect.DeepEqual(testOutput, expected) {
+		t.Fatalf("The processing result of IgnoreTags method is not as expected, want %s, but %s",
+			green(expected), yellow(testOutput))
+	}
+	t.Logf("The processing result of IgnoreTags method meets expectation: %s", green(expected))
+}
@@ -0,0 +1,120 @@
+// The endpoint_catalog dumps the service endpoints resolved for a region without any API calls, and validates the
+// customizing endpoints, which helps to configure the endpoints of the dedicated and hybrid clouds.
+//
+// Usage:
+//
+//	go run ./scripts/endpoint_catalog -region cn-north-4
+//	go run ./scripts/endpoint_catalog -region region-1 -cloud example.com -regional -endpoints endpoints.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+var (
+	region        string
+	cloud         string
+	regional      bool
+	endpointsFile string
+	format        string
+	customized    bool
+
+	commandLine flag.FlagSet
+)
+
+func init() {
+	commandLine.Init(os.Args[0], flag.ExitOnError)
+
+	commandLine.StringVar(&region, "region", "", "The region for which to resolve the endpoints")
+	commandLine.StringVar(&cloud, "cloud", "myhuaweicloud.com", "The cloud domain name")
+	commandLine.BoolVar(&regional, "regional", false, "Whether the endpoints of the global services are regional")
+	commandLine.StringVar(&endpointsFile, "endpoints", "",
+		"The JSON file of the customizing endpoints, the same as the endpoints argument of the provider")
+	commandLine.StringVar(&format, "format", "table", "The output format, table or json")
+	commandLine.BoolVar(&customized, "customized", false, "Print the customized endpoints only")
+
+	commandLine.Usage = func() {
+		fmt.Fprintf(commandLine.Output(), "Usage of %s:\n\n", os.Args[0])
+		commandLine.PrintDefaults()
+	}
+}
+
+func main() {
+	commandLine.Parse(os.Args[1:]) //nolint: errcheck
+
+	if region == "" {
+		fmt.Fprintln(os.Stderr, "-region must be specified")
+		os.Exit(1)
+	}
+
+	cfg := &config.Config{
+		Region:       region,
+		Cloud:        cloud,
+		RegionClient: regional,
+	}
+	if endpointsFile != "" {
+		endpoints, err := loadEndpoints(endpointsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid endpoints: %s\n", err)
+			os.Exit(2)
+		}
+		cfg.Endpoints = endpoints
+	}
+
+	catalog := config.ResolveServiceCatalog(cfg, region)
+	if customized {
+		filtered := make([]config.CatalogEndpoint, 0)
+		for _, ep := range catalog {
+			if ep.Customized {
+				filtered = append(filtered, ep)
+			}
+		}
+		catalog = filtered
+	}
+
+	if err := render(catalog); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render the catalog: %s\n", err)
+		os.Exit(3)
+	}
+}
+
+// loadEndpoints reads the customizing endpoints from the JSON file, the keys are validated and the endpoints are
+// normalized in the same way as the provider.
+func loadEndpoints(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints map[string]string
+	if err := json.Unmarshal(data, &endpoints); err != nil {
+		return nil, err
+	}
+	return config.NormalizeEndpoints(endpoints)
+}
+
+func render(catalog []config.CatalogEndpoint) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(catalog, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tVERSION\tSCOPE\tCUSTOMIZED\tURL")
+		for _, ep := range catalog {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", ep.Service, ep.Version, ep.Scope, ep.Customized, ep.URL)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("unsupported format %s", format)
+}
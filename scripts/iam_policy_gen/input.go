@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/iampolicy"
+)
+
+// apiDoc is the API document of a resource or data source in docs/api
+type apiDoc struct {
+	Info struct {
+		Title string `yaml:"title"`
+	} `yaml:"info"`
+	Paths map[string]map[string]struct {
+		Tag string `yaml:"tag"`
+	} `yaml:"paths"`
+}
+
+// loadAPIDocs loads the API path templates of all the resources and data sources, keyed by the document titles, e.g.
+// resource_huaweicloud_vpc and data_source_huaweicloud_vpcs.
+func loadAPIDocs(dir string) (map[string][]iampolicy.Template, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no API document is found in %s", dir)
+	}
+
+	docs := make(map[string][]iampolicy.Template, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var doc apiDoc
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", file, err)
+		}
+
+		title := doc.Info.Title
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(file), ".yaml")
+		}
+		for path, methods := range doc.Paths {
+			for method, api := range methods {
+				docs[title] = append(docs[title], iampolicy.Template{Service: api.Tag, Method: method, Path: path})
+			}
+		}
+	}
+	return docs, nil
+}
+
+// planChange is a resource change in the plan rendered by "terraform show -json"
+type planChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Change  struct {
+		Actions []string `json:"actions"`
+	} `json:"change"`
+}
+
+// addPlan adds the APIs of the resources and data sources in the plan. The resources which are only read need the
+// GET APIs, and all the APIs are required by the others since the APIs used by each operation are unknown.
+func addPlan(g *iampolicy.Generator, docs map[string][]iampolicy.Template, path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan struct {
+		ResourceChanges []planChange `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("error parsing the plan %s, it must be rendered by \"terraform show -json\": %s", path, err)
+	}
+
+	var warnings []string
+	for _, rc := range plan.ResourceChanges {
+		if !strings.HasPrefix(rc.Type, "huaweicloud_") {
+			continue
+		}
+
+		title := "resource_" + rc.Type
+		if rc.Mode == "data" {
+			title = "data_source_" + rc.Type
+		}
+		templates, ok := docs[title]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("the APIs of %s are not documented in docs/api", rc.Address))
+			continue
+		}
+
+		readOnly := true
+		for _, action := range rc.Change.Actions {
+			if action != "no-op" && action != "read" {
+				readOnly = false
+			}
+		}
+		for _, t := range templates {
+			if readOnly && t.Method != "GET" {
+				continue
+			}
+			g.AddTemplate(t.Service, t.Method, t.Path)
+		}
+	}
+	return warnings, nil
+}
+
+// addTrace adds the API calls recorded in the trace file written with HW_API_TRACE_FILE, the summary is skipped.
+func addTrace(g *iampolicy.Generator, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record struct {
+			Service string `json:"service"`
+			Method  string `json:"method"`
+			Path    string `json:"path"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("error parsing the trace %s: %s", path, err)
+		}
+		if record.Method == "" || record.Path == "" {
+			continue
+		}
+		g.AddCall(record.Service, record.Method, record.Path)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,151 @@
+// The iam_policy_gen generates the least-privilege custom IAM policies from a Terraform plan or an API trace.
+//
+// Usage:
+//
+//	terraform show -json tfplan > plan.json
+//	go run ./scripts/iam_policy_gen -plan plan.json -format hcl
+//	HW_API_TRACE_FILE=trace.json terraform apply
+//	go run ./scripts/iam_policy_gen -trace trace.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/iampolicy"
+)
+
+var (
+	apiDir    string
+	planFile  string
+	traceFile string
+	format    string
+	roleName  string
+	verbose   bool
+	strict    bool
+
+	commandLine flag.FlagSet
+)
+
+func init() {
+	commandLine.Init(os.Args[0], flag.ExitOnError)
+
+	commandLine.StringVar(&apiDir, "api", "docs/api", "The directory of the API documents")
+	commandLine.StringVar(&planFile, "plan", "", "The plan file rendered by \"terraform show -json\"")
+	commandLine.StringVar(&traceFile, "trace", "", "The API trace file written with HW_API_TRACE_FILE")
+	commandLine.StringVar(&format, "format", "json", "The output format, json or hcl")
+	commandLine.StringVar(&roleName, "name", "terraform", "The name of huaweicloud_identity_role in hcl format")
+	commandLine.BoolVar(&verbose, "v", false, "Print the action of each API to stderr for review")
+	commandLine.BoolVar(&strict, "strict", false, "Exit with an error if any action is not vetted")
+
+	commandLine.Usage = func() {
+		fmt.Fprintf(commandLine.Output(), "Usage of %s:\n\n", os.Args[0])
+		commandLine.PrintDefaults()
+	}
+}
+
+func main() {
+	commandLine.Parse(os.Args[1:]) //nolint: errcheck
+
+	if planFile == "" && traceFile == "" {
+		fmt.Fprintln(os.Stderr, "-plan or -trace must be specified")
+		os.Exit(1)
+	}
+
+	docs, err := loadAPIDocs(apiDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load the API documents: %s\n", err)
+		os.Exit(2)
+	}
+	var templates []iampolicy.Template
+	for _, list := range docs {
+		templates = append(templates, list...)
+	}
+	g := iampolicy.NewGenerator(templates)
+
+	if planFile != "" {
+		warnings, err := addPlan(g, docs, planFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read the plan: %s\n", err)
+			os.Exit(3)
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "[WARN] %s\n", w)
+		}
+	}
+	if traceFile != "" {
+		if err := addTrace(g, traceFile); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read the trace: %s\n", err)
+			os.Exit(3)
+		}
+	}
+
+	for _, m := range g.Mappings() {
+		switch {
+		case !m.Matched:
+			fmt.Fprintf(os.Stderr, "[WARN] %s %s of %s is not documented, the action %s is derived from the path and "+
+				"must be reviewed\n", m.Method, m.Path, m.Service, m.Action)
+		case !m.Vetted:
+			fmt.Fprintf(os.Stderr, "[WARN] the action of %s %s of %s is not vetted, %s is derived from the path and "+
+				"must be reviewed\n", m.Method, m.Path, m.Service, m.Action)
+		case verbose:
+			fmt.Fprintf(os.Stderr, "%s %s %s => %s\n", m.Service, m.Method, m.Path, m.Action)
+		}
+	}
+	if unvetted := g.Unvetted(); strict && len(unvetted) > 0 {
+		fmt.Fprintf(os.Stderr, "%d actions are not vetted\n", len(unvetted))
+		os.Exit(5)
+	}
+
+	if err := render(g.Policies()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render the policies: %s\n", err)
+		os.Exit(4)
+	}
+}
+
+// render prints the policy document in json format, the policies of both the global and project-level services are
+// keyed by the role types. The hcl format prints a huaweicloud_identity_role for each role type.
+func render(policies map[string]*iampolicy.Policy) error {
+	if format == "json" {
+		data, err := iampolicy.MarshalPolicies(policies)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if format != "hcl" {
+		return fmt.Errorf("unsupported format %s", format)
+	}
+
+	roleTypes := make([]string, 0, len(policies))
+	for roleType := range policies {
+		roleTypes = append(roleTypes, roleType)
+	}
+	sort.Strings(roleTypes)
+	for _, roleType := range roleTypes {
+		data, err := json.MarshalIndent(policies[roleType], "", "  ")
+		if err != nil {
+			return err
+		}
+
+		name := roleName
+		if roleType == iampolicy.RoleTypeGlobal {
+			name += "_global"
+		}
+		fmt.Printf(`resource "huaweicloud_identity_role" "%[1]s" {
+  name        = "%[1]s"
+  type        = "%[2]s"
+  description = "The least-privilege policy generated by iam_policy_gen"
+  policy      = <<EOT
+%[3]s
+EOT
+}
+
+`, name, roleType, data)
+	}
+	return nil
+}